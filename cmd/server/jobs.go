@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"log"
+	"pepper-analytics-ai/internal/notify"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/internal/utils"
+	"pepper-analytics-ai/templates/email"
+	"sort"
+	"strings"
+	"time"
+)
+
+// registerJobs sets up the background jobs. The schedules here are the
+// defaults; they can be changed on the jobs admin page.
+func registerJobs(scheduler *services.SchedulerService, db *sqlx.DB, notificationService *services.NotificationService) error {
+	plantService := services.NewPlantService(db)
+	taskService := services.NewTaskService(db, plantService)
+	trashService := services.NewTrashService(db)
+	digestService := services.NewDigestService(plantService, taskService, services.NewHarvestService(db), notificationService, email.RenderDigest)
+
+	healthWindowDays := utils.GetEnvAsInt("HEALTH_DECLINE_WINDOW_DAYS", 7)
+	trashRetentionDays := utils.GetEnvAsInt("TRASH_RETENTION_DAYS", 30)
+
+	jobs := []struct {
+		name        string
+		description string
+		schedule    string
+		run         services.JobFunc
+	}{
+		{
+			"health-check", "Flag plants whose health dropped sharply and send alerts", "0 * * * *",
+			func(ctx context.Context) (string, error) {
+				return checkHealth(ctx, plantService, notificationService, healthWindowDays)
+			},
+		},
+		{
+			"care-overdue", "Remind about plants overdue for water or fertilizer", "0 7 * * *",
+			func(ctx context.Context) (string, error) {
+				return remindOverdueCare(ctx, plantService, notificationService)
+			},
+		},
+		{
+			"task-reminders", "Remind about tasks due today or overdue", "0 7 * * *",
+			func(ctx context.Context) (string, error) {
+				return remindDueTasks(ctx, taskService, notificationService)
+			},
+		},
+		{
+			"daily-digest", "Send the garden digest to users who get it daily", "0 6 * * *",
+			func(ctx context.Context) (string, error) {
+				sent, err := digestService.SendDigests(ctx, types.DigestDaily, time.Now())
+				return fmt.Sprintf("Sent to %d users", sent), err
+			},
+		},
+		{
+			"weekly-digest", "Send the garden digest to users who get it weekly", "0 6 * * 1",
+			func(ctx context.Context) (string, error) {
+				sent, err := digestService.SendDigests(ctx, types.DigestWeekly, time.Now())
+				return fmt.Sprintf("Sent to %d users", sent), err
+			},
+		},
+		{
+			"deliver-notifications", "Send notifications held back by quiet hours or failed sends", "*/5 * * * *",
+			func(ctx context.Context) (string, error) {
+				sent, err := notificationService.DeliverPending(ctx, time.Now())
+				return fmt.Sprintf("%d sent", sent), err
+			},
+		},
+		{
+			"purge-trash", fmt.Sprintf("Remove records deleted more than %d days ago", trashRetentionDays), "30 3 * * *",
+			func(ctx context.Context) (string, error) {
+				purged, err := trashService.PurgeDeleted(time.Now().AddDate(0, 0, -trashRetentionDays))
+				return purgeSummary(purged), err
+			},
+		},
+	}
+
+	for _, job := range jobs {
+		if err := scheduler.Register(job.name, job.description, job.schedule, job.run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkHealth(ctx context.Context, plantService *services.PlantService, notificationService *services.NotificationService, windowDays int) (string, error) {
+	alerts, err := plantService.CheckHealthDeclines(windowDays)
+	if err != nil {
+		return "", err
+	}
+
+	// The alerts are already saved, so one failing send must not keep the
+	// rest from going out
+	var errs []error
+	for _, alert := range alerts {
+		log.Printf("Health alert: %s went from %s to %s", alert.PlantName, alert.PeakHealth, alert.Health)
+		_, err := notificationService.Notify(ctx, types.NotifyHealthAlert, notify.Message{
+			Event: string(types.NotifyHealthAlert),
+			Title: "Health alert: " + alert.PlantName,
+			Body:  fmt.Sprintf("%s went from %s to %s.", alert.PlantName, alert.PeakHealth, alert.Health),
+			URL:   notificationService.Link(fmt.Sprintf("/plants/%d/journal", alert.PlantID)),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error sending health alert for %s: %w", alert.PlantName, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d new alerts", len(alerts)), nil
+}
+
+func remindOverdueCare(ctx context.Context, plantService *services.PlantService, notificationService *services.NotificationService) (string, error) {
+	overdue, err := plantService.GetOverduePlants(time.Now())
+	if err != nil {
+		return "", err
+	}
+	if len(overdue) == 0 {
+		return "Nothing overdue", nil
+	}
+
+	var lines []string
+	for _, plant := range overdue {
+		var needs []string
+		if plant.WateringStatus == types.CareOverdue {
+			needs = append(needs, "water since "+plant.WateringDue.Time.Format("Jan 02"))
+		}
+		if plant.FertilizingStatus == types.CareOverdue {
+			needs = append(needs, "fertilizer since "+plant.FertilizingDue.Time.Format("Jan 02"))
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", plant.PlantName, strings.Join(needs, ", ")))
+	}
+
+	queued, err := notificationService.Notify(ctx, types.NotifyCareOverdue, notify.Message{
+		Event: string(types.NotifyCareOverdue),
+		Title: fmt.Sprintf("%d plants overdue for care", len(overdue)),
+		Body:  strings.Join(lines, "\n"),
+		URL:   notificationService.Link("/plants?care_filter=overdue"),
+	})
+	return fmt.Sprintf("%d plants overdue, sent to %d channels", len(overdue), queued), err
+}
+
+func remindDueTasks(ctx context.Context, taskService *services.TaskService, notificationService *services.NotificationService) (string, error) {
+	tasks, err := taskService.GetDueTasks(time.Now())
+	if err != nil {
+		return "", err
+	}
+	if len(tasks) == 0 {
+		return "No tasks due", nil
+	}
+
+	var lines []string
+	for _, task := range tasks {
+		line := fmt.Sprintf("- %s (%s", task.Title, task.Status(time.Now()))
+		if task.PlantNames.Valid {
+			line += ", " + task.PlantNames.String
+		}
+		lines = append(lines, line+")")
+	}
+
+	queued, err := notificationService.Notify(ctx, types.NotifyTaskDue, notify.Message{
+		Event: string(types.NotifyTaskDue),
+		Title: fmt.Sprintf("%d tasks due", len(tasks)),
+		Body:  strings.Join(lines, "\n"),
+		URL:   notificationService.Link("/tasks"),
+	})
+	return fmt.Sprintf("%d tasks due, sent to %d channels", len(tasks), queued), err
+}
+
+func purgeSummary(purged map[string]int64) string {
+	if len(purged) == 0 {
+		return "Nothing to purge"
+	}
+	var parts []string
+	for table, rows := range purged {
+		parts = append(parts, fmt.Sprintf("%s %d", table, rows))
+	}
+	sort.Strings(parts)
+	return "Purged " + strings.Join(parts, ", ")
+}
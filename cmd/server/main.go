@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"github.com/joho/godotenv"
 	"log"
 	"os"
 	"path/filepath"
 	"pepper-analytics-ai/internal/database"
+	"pepper-analytics-ai/internal/notify"
 	"pepper-analytics-ai/internal/routes"
+	"pepper-analytics-ai/internal/services"
+	"time"
 )
 
 func loadEnv() error {
@@ -51,9 +55,44 @@ func main() {
 	}
 	defer db.Close()
 
+	// Bring hand-typed generations ("f2 ", "F-2") into canonical form
+	if err := services.NewPlantService(db).NormalizeGenerations(); err != nil {
+		log.Printf("Warning: Error normalizing generations: %v", err)
+	}
+
+	// Give plants from before stage tracking a starting stage to build on
+	if err := services.NewPlantService(db).BackfillStageHistory(); err != nil {
+		log.Printf("Warning: Error backfilling stage history: %v", err)
+	}
+
+	// Give plants from before health tracking a starting health to compare to
+	if err := services.NewPlantService(db).BackfillHealthHistory(); err != nil {
+		log.Printf("Warning: Error backfilling health history: %v", err)
+	}
+
+	// Link plants from before the variety catalog to varieties named after them
+	if err := services.NewVarietyService(db).BackfillFromPlantNames(); err != nil {
+		log.Printf("Warning: Error backfilling varieties: %v", err)
+	}
+
+	notifyConfig := notify.NewDefaultConfig()
+	notificationService := services.NewNotificationService(db, notifyConfig)
+
+	// Run the background jobs alongside the web server
+	scheduler := services.NewSchedulerService(db)
+	if err := registerJobs(scheduler, db, notificationService); err != nil {
+		log.Fatalf("Failed to register jobs: %v", err)
+	}
+	go func() {
+		if err := scheduler.Start(context.Background(), 30*time.Second); err != nil {
+			log.Printf("Warning: Scheduler stopped: %v", err)
+		}
+	}()
+
 	// Set up router with error handling
 	router, err := routes.SetupRouter(routes.RouterConfig{
-		DB: db,
+		DB:     db,
+		Notify: notifyConfig,
 	})
 	if err != nil {
 		log.Fatalf("Failed to setup router: %v", err)
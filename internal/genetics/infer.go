@@ -0,0 +1,82 @@
+package genetics
+
+import (
+	"fmt"
+	"pepper-analytics-ai/internal/types"
+)
+
+// Relative is a parent or offspring and its genotype at the locus being
+// inferred.
+type Relative struct {
+	Name     string
+	Genotype types.Genotype
+}
+
+// InferFromRelatives works out a plant's genotype at a locus from its own
+// genotype and those of its parents and offspring. Two homozygous parents
+// fix the genotype outright. Otherwise a plant carrying the dominant allele
+// with a homozygous recessive parent or offspring must be heterozygous,
+// since it received or passed on a recessive allele; a red-fruited plant
+// that throws yellow offspring is Y/y. It returns GenotypeUnknown when
+// nothing follows, and conflict when the relatives contradict own.
+func InferFromRelatives(locus types.Locus, own types.Genotype, parents, offspring []Relative) (types.Genotype, string, bool) {
+	if len(parents) == 2 && isHomozygous(parents[0].Genotype) && isHomozygous(parents[1].Genotype) {
+		genotype := types.GenotypeHeterozygous
+		if parents[0].Genotype == parents[1].Genotype {
+			genotype = parents[0].Genotype
+		}
+		reason := fmt.Sprintf("Parents %s (%s) and %s (%s)",
+			parents[0].Name, locus.Notation(parents[0].Genotype),
+			parents[1].Name, locus.Notation(parents[1].Genotype))
+		if !consistent(own, genotype) {
+			return types.GenotypeUnknown, reason + " contradict " + locus.Notation(own), true
+		}
+		return genotype, reason, false
+	}
+
+	if own != types.GenotypeDominant && own != types.GenotypeHomozygousDominant {
+		return types.GenotypeUnknown, "", false
+	}
+
+	var reason string
+	for _, parent := range parents {
+		if parent.Genotype == types.GenotypeHomozygousRecessive {
+			reason = fmt.Sprintf("Parent %s is %s", parent.Name, locus.Notation(parent.Genotype))
+			break
+		}
+	}
+	if reason == "" {
+		for _, child := range offspring {
+			if child.Genotype == types.GenotypeHomozygousRecessive {
+				reason = fmt.Sprintf("Offspring %s is %s", child.Name, locus.Notation(child.Genotype))
+				break
+			}
+		}
+	}
+
+	switch {
+	case reason == "":
+		return types.GenotypeUnknown, "", false
+	case own == types.GenotypeHomozygousDominant:
+		return types.GenotypeUnknown, reason + " but the plant is called " + locus.Notation(own), true
+	default:
+		return types.GenotypeHeterozygous, reason, false
+	}
+}
+
+func isHomozygous(g types.Genotype) bool {
+	return g == types.GenotypeHomozygousDominant || g == types.GenotypeHomozygousRecessive
+}
+
+// consistent reports whether a plant's known genotype allows the given
+// exact genotype.
+func consistent(own, genotype types.Genotype) bool {
+	switch own {
+	case types.GenotypeUnknown, "":
+		return true
+	case types.GenotypeDominant:
+		return genotype != types.GenotypeHomozygousRecessive
+	default:
+		return own == genotype
+	}
+}
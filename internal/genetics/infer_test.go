@@ -0,0 +1,71 @@
+package genetics
+
+import (
+	"pepper-analytics-ai/internal/types"
+	"testing"
+)
+
+func TestInferFromRelatives(t *testing.T) {
+	red := func(name string) Relative { return Relative{Name: name, Genotype: types.GenotypeHomozygousDominant} }
+	yellow := func(name string) Relative { return Relative{Name: name, Genotype: types.GenotypeHomozygousRecessive} }
+	het := func(name string) Relative { return Relative{Name: name, Genotype: types.GenotypeHeterozygous} }
+
+	tests := []struct {
+		name      string
+		own       types.Genotype
+		parents   []Relative
+		offspring []Relative
+		want      types.Genotype
+		reason    string
+		conflict  bool
+	}{
+		{
+			name: "homozygous parents differ", own: types.GenotypeDominant,
+			parents: []Relative{red("A"), yellow("B")},
+			want:    types.GenotypeHeterozygous, reason: "Parents A (Y/Y) and B (y/y)",
+		},
+		{
+			name: "homozygous parents agree", own: types.GenotypeUnknown,
+			parents: []Relative{yellow("A"), yellow("B")},
+			want:    types.GenotypeHomozygousRecessive, reason: "Parents A (y/y) and B (y/y)",
+		},
+		{
+			name: "homozygous parents contradict", own: types.GenotypeHomozygousRecessive,
+			parents: []Relative{red("A"), red("B")},
+			want:    types.GenotypeUnknown, reason: "Parents A (Y/Y) and B (Y/Y) contradict y/y", conflict: true,
+		},
+		{
+			name: "recessive parent", own: types.GenotypeDominant,
+			parents: []Relative{het("A"), yellow("B")},
+			want:    types.GenotypeHeterozygous, reason: "Parent B is y/y",
+		},
+		{
+			name: "recessive offspring", own: types.GenotypeDominant,
+			offspring: []Relative{het("C"), yellow("D")},
+			want:      types.GenotypeHeterozygous, reason: "Offspring D is y/y",
+		},
+		{
+			name: "recessive offspring of a homozygote", own: types.GenotypeHomozygousDominant,
+			offspring: []Relative{yellow("D")},
+			want:      types.GenotypeUnknown, reason: "Offspring D is y/y but the plant is called Y/Y", conflict: true,
+		},
+		{
+			name: "recessive plant", own: types.GenotypeHomozygousRecessive,
+			parents: []Relative{yellow("A")},
+			want:    types.GenotypeUnknown,
+		},
+		{
+			name: "nothing follows", own: types.GenotypeDominant,
+			parents: []Relative{het("A")}, offspring: []Relative{red("C")},
+			want: types.GenotypeUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		got, reason, conflict := InferFromRelatives(testLocus, tt.own, tt.parents, tt.offspring)
+		if got != tt.want || reason != tt.reason || conflict != tt.conflict {
+			t.Errorf("%s: got %s, %q, %v, want %s, %q, %v",
+				tt.name, got, reason, conflict, tt.want, tt.reason, tt.conflict)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package genetics predicts offspring of planned crosses at single-gene
+// loci with simple Mendelian inheritance.
+package genetics
+
+import (
+	"pepper-analytics-ai/internal/types"
+	"sort"
+	"strings"
+)
+
+// Expected turns a genotype call into the genotype ratio of the plant
+// itself. A plant known only to carry the dominant allele is taken as
+// equally likely to be homozygous or heterozygous. ok is false for an
+// unknown genotype.
+func Expected(g types.Genotype) (types.GenotypeRatio, bool) {
+	switch g {
+	case types.GenotypeHomozygousDominant:
+		return types.GenotypeRatio{HomozygousDominant: 1}, true
+	case types.GenotypeHeterozygous:
+		return types.GenotypeRatio{Heterozygous: 1}, true
+	case types.GenotypeHomozygousRecessive:
+		return types.GenotypeRatio{HomozygousRecessive: 1}, true
+	case types.GenotypeDominant:
+		return types.GenotypeRatio{HomozygousDominant: 0.5, Heterozygous: 0.5}, true
+	default:
+		return types.GenotypeRatio{}, false
+	}
+}
+
+// dominantGamete is the chance that a gamete carries the dominant allele.
+func dominantGamete(r types.GenotypeRatio) float64 {
+	return r.HomozygousDominant + r.Heterozygous/2
+}
+
+// Cross is the offspring of two different plants.
+func Cross(seed, pollen types.GenotypeRatio) types.GenotypeRatio {
+	p, q := dominantGamete(seed), dominantGamete(pollen)
+	dominant := p * q
+	recessive := (1 - p) * (1 - q)
+	return types.GenotypeRatio{
+		HomozygousDominant:  dominant,
+		Heterozygous:        1 - dominant - recessive,
+		HomozygousRecessive: recessive,
+	}
+}
+
+// Self is the offspring of selfing. Both gametes come from the same plant,
+// so an uncertain genotype is resolved once per plant rather than once per
+// gamete.
+func Self(r types.GenotypeRatio) types.GenotypeRatio {
+	return types.GenotypeRatio{
+		HomozygousDominant:  r.HomozygousDominant + r.Heterozygous/4,
+		Heterozygous:        r.Heterozygous / 2,
+		HomozygousRecessive: r.HomozygousRecessive + r.Heterozygous/4,
+	}
+}
+
+// InferGenotype reads a locus' genotype from a plant's latest trait scores.
+func InferGenotype(locus types.Locus, scores []types.TraitScore) types.Genotype {
+	for _, score := range scores {
+		if score.TraitCode != locus.TraitCode || !score.StateValue.Valid {
+			continue
+		}
+		value := int(score.StateValue.Int64)
+		for _, state := range locus.RecessiveStates {
+			if state == value {
+				return types.GenotypeHomozygousRecessive
+			}
+		}
+		for _, state := range locus.DominantStates {
+			if state == value {
+				return types.GenotypeDominant
+			}
+		}
+	}
+	return types.GenotypeUnknown
+}
+
+// Predict works out the F1 and F2 at one locus. The F1 of a self is the S1,
+// and the F2 is always the F1 selfed.
+func Predict(locus types.Locus, seed, pollen types.Genotype, selfed bool) types.LocusPrediction {
+	prediction := types.LocusPrediction{
+		Locus:        locus,
+		SeedParent:   seed,
+		PollenParent: pollen,
+		Assumed:      seed == types.GenotypeDominant || pollen == types.GenotypeDominant,
+	}
+
+	seedRatio, seedKnown := Expected(seed)
+	pollenRatio, pollenKnown := Expected(pollen)
+	if !seedKnown || !pollenKnown {
+		return prediction
+	}
+
+	prediction.Known = true
+	if selfed {
+		prediction.F1 = Self(seedRatio)
+	} else {
+		prediction.F1 = Cross(seedRatio, pollenRatio)
+	}
+	prediction.F2 = Self(prediction.F1)
+	return prediction
+}
+
+// Classes combines the known loci into phenotype classes, assuming the loci
+// assort independently. f2 picks the F2 ratios instead of the F1. Classes
+// that cannot occur are left out and the rest are sorted most likely first.
+func Classes(predictions []types.LocusPrediction, f2 bool) []types.PhenotypeClass {
+	classes := []types.PhenotypeClass{{Probability: 1}}
+	for _, prediction := range predictions {
+		if !prediction.Known {
+			continue
+		}
+		ratio := prediction.F1
+		if f2 {
+			ratio = prediction.F2
+		}
+
+		locus := prediction.Locus
+		next := make([]types.PhenotypeClass, 0, len(classes)*2)
+		for _, class := range classes {
+			next = append(next,
+				joinClass(class, locus.DominantAllele+"_", locus.DominantLabel, ratio.Dominant()),
+				joinClass(class, locus.RecessiveAllele+locus.RecessiveAllele, locus.RecessiveLabel, ratio.Recessive()),
+			)
+		}
+		classes = next
+	}
+
+	result := make([]types.PhenotypeClass, 0, len(classes))
+	for _, class := range classes {
+		if class.Label != "" && class.Probability > 1e-9 {
+			result = append(result, class)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Probability > result[j].Probability
+	})
+	return result
+}
+
+func joinClass(class types.PhenotypeClass, allele, description string, probability float64) types.PhenotypeClass {
+	if class.Description != "" {
+		description = class.Description + ", " + description
+	}
+	return types.PhenotypeClass{
+		Label:       strings.TrimSpace(class.Label + " " + allele),
+		Description: description,
+		Probability: class.Probability * probability,
+	}
+}
@@ -0,0 +1,140 @@
+package genetics
+
+import (
+	"database/sql"
+	"math"
+	"pepper-analytics-ai/internal/types"
+	"testing"
+)
+
+var testLocus = types.Locus{
+	Symbol: "y", DominantAllele: "Y", RecessiveAllele: "y",
+	DominantLabel: "red", RecessiveLabel: "yellow",
+	TraitCode: "fruit_color", DominantStates: []int{1}, RecessiveStates: []int{2},
+}
+
+var otherLocus = types.Locus{
+	Symbol: "pun1", DominantAllele: "Pun1", RecessiveAllele: "pun1",
+	DominantLabel: "pungent", RecessiveLabel: "sweet",
+	TraitCode: "pungency", DominantStates: []int{1}, RecessiveStates: []int{0},
+}
+
+func ratioEqual(a, b types.GenotypeRatio) bool {
+	return math.Abs(a.HomozygousDominant-b.HomozygousDominant) < 1e-9 &&
+		math.Abs(a.Heterozygous-b.Heterozygous) < 1e-9 &&
+		math.Abs(a.HomozygousRecessive-b.HomozygousRecessive) < 1e-9
+}
+
+func TestPredict(t *testing.T) {
+	tests := []struct {
+		name    string
+		seed    types.Genotype
+		pollen  types.Genotype
+		selfed  bool
+		known   bool
+		assumed bool
+		f1      types.GenotypeRatio
+		f2      types.GenotypeRatio
+	}{
+		{
+			name: "homozygous parents", seed: types.GenotypeHomozygousDominant, pollen: types.GenotypeHomozygousRecessive, known: true,
+			f1: types.GenotypeRatio{Heterozygous: 1},
+			f2: types.GenotypeRatio{HomozygousDominant: 0.25, Heterozygous: 0.5, HomozygousRecessive: 0.25},
+		},
+		{
+			name: "test cross", seed: types.GenotypeHeterozygous, pollen: types.GenotypeHomozygousRecessive, known: true,
+			f1: types.GenotypeRatio{Heterozygous: 0.5, HomozygousRecessive: 0.5},
+			f2: types.GenotypeRatio{HomozygousDominant: 0.125, Heterozygous: 0.25, HomozygousRecessive: 0.625},
+		},
+		{
+			name: "selfed heterozygote", seed: types.GenotypeHeterozygous, pollen: types.GenotypeHeterozygous, selfed: true, known: true,
+			f1: types.GenotypeRatio{HomozygousDominant: 0.25, Heterozygous: 0.5, HomozygousRecessive: 0.25},
+			f2: types.GenotypeRatio{HomozygousDominant: 0.375, Heterozygous: 0.25, HomozygousRecessive: 0.375},
+		},
+		{
+			name: "selfed dominant phenotype", seed: types.GenotypeDominant, pollen: types.GenotypeDominant, selfed: true, known: true, assumed: true,
+			f1: types.GenotypeRatio{HomozygousDominant: 0.625, Heterozygous: 0.25, HomozygousRecessive: 0.125},
+			f2: types.GenotypeRatio{HomozygousDominant: 0.6875, Heterozygous: 0.125, HomozygousRecessive: 0.1875},
+		},
+		{
+			name: "dominant phenotype crossed", seed: types.GenotypeDominant, pollen: types.GenotypeHomozygousRecessive, known: true, assumed: true,
+			f1: types.GenotypeRatio{Heterozygous: 0.75, HomozygousRecessive: 0.25},
+			f2: types.GenotypeRatio{HomozygousDominant: 0.1875, Heterozygous: 0.375, HomozygousRecessive: 0.4375},
+		},
+		{name: "unknown parent", seed: types.GenotypeUnknown, pollen: types.GenotypeHomozygousRecessive},
+	}
+
+	for _, tt := range tests {
+		got := Predict(testLocus, tt.seed, tt.pollen, tt.selfed)
+		if got.Known != tt.known || got.Assumed != tt.assumed {
+			t.Errorf("%s: known, assumed = %v, %v, want %v, %v", tt.name, got.Known, got.Assumed, tt.known, tt.assumed)
+			continue
+		}
+		if !ratioEqual(got.F1, tt.f1) {
+			t.Errorf("%s: F1 = %+v, want %+v", tt.name, got.F1, tt.f1)
+		}
+		if !ratioEqual(got.F2, tt.f2) {
+			t.Errorf("%s: F2 = %+v, want %+v", tt.name, got.F2, tt.f2)
+		}
+	}
+}
+
+func TestInferGenotype(t *testing.T) {
+	score := func(code string, state int64) types.TraitScore {
+		return types.TraitScore{TraitCode: code, StateValue: sql.NullInt64{Int64: state, Valid: true}}
+	}
+
+	tests := []struct {
+		name   string
+		scores []types.TraitScore
+		want   types.Genotype
+	}{
+		{name: "recessive state", scores: []types.TraitScore{score("fruit_color", 2)}, want: types.GenotypeHomozygousRecessive},
+		{name: "dominant state", scores: []types.TraitScore{score("fruit_color", 1)}, want: types.GenotypeDominant},
+		{name: "state outside the locus", scores: []types.TraitScore{score("fruit_color", 3)}, want: types.GenotypeUnknown},
+		{name: "other trait", scores: []types.TraitScore{score("pungency", 0)}, want: types.GenotypeUnknown},
+		{name: "numeric score", scores: []types.TraitScore{{TraitCode: "fruit_color"}}, want: types.GenotypeUnknown},
+		{name: "no scores", want: types.GenotypeUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := InferGenotype(testLocus, tt.scores); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClasses(t *testing.T) {
+	predictions := []types.LocusPrediction{
+		Predict(testLocus, types.GenotypeHomozygousDominant, types.GenotypeHomozygousRecessive, false),
+		Predict(otherLocus, types.GenotypeHomozygousDominant, types.GenotypeHomozygousRecessive, false),
+		Predict(testLocus, types.GenotypeUnknown, types.GenotypeUnknown, false),
+	}
+
+	f1 := Classes(predictions, false)
+	if len(f1) != 1 || f1[0].Label != "Y_ Pun1_" || math.Abs(f1[0].Probability-1) > 1e-9 {
+		t.Fatalf("F1 classes = %+v, want only Y_ Pun1_", f1)
+	}
+
+	f2 := Classes(predictions, true)
+	want := []struct {
+		label       string
+		probability float64
+	}{
+		{"Y_ Pun1_", 9.0 / 16},
+		{"Y_ pun1pun1", 3.0 / 16},
+		{"yy Pun1_", 3.0 / 16},
+		{"yy pun1pun1", 1.0 / 16},
+	}
+	if len(f2) != len(want) {
+		t.Fatalf("F2 classes = %+v, want %d classes", f2, len(want))
+	}
+	for i, w := range want {
+		if f2[i].Label != w.label || math.Abs(f2[i].Probability-w.probability) > 1e-9 {
+			t.Errorf("F2 class %d = %s %v, want %s %v", i, f2[i].Label, f2[i].Probability, w.label, w.probability)
+		}
+	}
+	if f2[3].Description != "yellow, sweet" {
+		t.Errorf("F2 description = %q, want %q", f2[3].Description, "yellow, sweet")
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+	traitService     *services.TraitService
+}
+
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService, traitService *services.TraitService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+		traitService:     traitService,
+	}
+}
+
+// HandleSegregation renders the segregation page. The analysis only runs
+// once a family has been picked.
+func (h *AnalyticsHandler) HandleSegregation(c *gin.Context) {
+	families, err := h.analyticsService.GetCrossFamilies()
+	if err != nil {
+		log.Printf("Error fetching cross families: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	traits, err := h.traitService.GetTraitDefinitions()
+	if err != nil {
+		log.Printf("Error fetching trait definitions: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	var categorical []types.TraitDefinition
+	for _, trait := range traits {
+		if trait.ScaleType == types.TraitScaleCategorical {
+			categorical = append(categorical, trait)
+		}
+	}
+
+	form := pages.SegregationForm{
+		Family: c.Query("family"),
+		Ratio:  c.DefaultQuery("ratio", "3:1"),
+	}
+	form.TraitID, _ = strconv.Atoi(c.Query("trait"))
+	form.SecondTraitID, _ = strconv.Atoi(c.Query("second_trait"))
+
+	// Class assignments come as matching class and part lists
+	classes, parts := c.QueryArray("class"), c.QueryArray("part")
+	if len(classes) > 0 && len(classes) == len(parts) {
+		form.Parts = make(map[string]float64, len(classes))
+		for i, class := range classes {
+			part, err := strconv.ParseFloat(parts[i], 64)
+			if err != nil {
+				c.String(http.StatusBadRequest, "Invalid ratio part")
+				return
+			}
+			form.Parts[class] = part
+		}
+	}
+
+	var result *types.SegregationResult
+	var message string
+	if form.Family != "" && form.TraitID != 0 {
+		result, message = h.analyze(form)
+	}
+
+	templ.Handler(pages.SegregationAnalysis(families, categorical, form, result, message)).ServeHTTP(c.Writer, c.Request)
+}
+
+// analyze runs the analysis for the submitted form and returns any problem
+// as a message for the page rather than failing the request.
+func (h *AnalyticsHandler) analyze(form pages.SegregationForm) (*types.SegregationResult, string) {
+	parentAID, parentBID, err := types.ParseCrossFamilyKey(form.Family)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	ratio, err := types.ParseSegregationRatio(form.Ratio)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	result, err := h.analyticsService.AnalyzeSegregation(types.SegregationQuery{
+		ParentAID:     parentAID,
+		ParentBID:     parentBID,
+		TraitID:       form.TraitID,
+		SecondTraitID: form.SecondTraitID,
+		Ratio:         ratio,
+		Parts:         form.Parts,
+	})
+	switch {
+	case err == nil:
+		return result, ""
+	case errors.Is(err, services.ErrClassCountMismatch),
+		errors.Is(err, services.ErrClassAssignment),
+		errors.Is(err, services.ErrTraitNotCategorical),
+		errors.Is(err, services.ErrTraitNotFound),
+		errors.Is(err, services.ErrPlantNotFound):
+		return result, err.Error()
+	default:
+		log.Printf("Error analyzing segregation: %v", err)
+		return nil, "The analysis failed, please try again."
+	}
+}
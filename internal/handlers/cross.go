@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+)
+
+type CrossHandler struct {
+	crossService *services.CrossService
+	plantService *services.PlantService
+}
+
+func NewCrossHandler(crossService *services.CrossService, plantService *services.PlantService) *CrossHandler {
+	return &CrossHandler{
+		crossService: crossService,
+		plantService: plantService,
+	}
+}
+
+func (h *CrossHandler) HandleCrossPlanner(c *gin.Context) {
+	crosses, err := h.crossService.GetPlannedCrosses()
+	if err != nil {
+		log.Printf("Error fetching planned crosses: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	plants, err := h.plantService.GetPlantOptions()
+	if err != nil {
+		log.Printf("Error fetching plant options: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	options, err := h.plantService.GetPlantFilterOptions()
+	if err != nil {
+		log.Printf("Error fetching project options: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	seedParentID, _ := strconv.Atoi(c.Query("seed_parent_id"))
+	pollenParentID, _ := strconv.Atoi(c.Query("pollen_parent_id"))
+
+	templ.Handler(pages.CrossPlanner(crosses, plants, options.Projects, seedParentID, pollenParentID)).ServeHTTP(c.Writer, c.Request)
+}
+
+// HandlePredictCross previews the offspring of the parents picked in the
+// planner form. Ticking self ignores the pollen parent.
+func (h *CrossHandler) HandlePredictCross(c *gin.Context) {
+	seedParentID, pollenParentID, ok := crossParents(c.Query("seed_parent_id"), c.Query("pollen_parent_id"), c.Query("self"))
+	if !ok {
+		templ.Handler(pages.CrossPrediction(nil)).ServeHTTP(c.Writer, c.Request)
+		return
+	}
+
+	prediction, err := h.crossService.PredictCross(seedParentID, pollenParentID)
+	if err != nil {
+		if errors.Is(err, services.ErrPlantNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error predicting cross: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.CrossPrediction(prediction)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *CrossHandler) HandleCreatePlannedCross(c *gin.Context) {
+	seedParentID, pollenParentID, ok := crossParents(c.PostForm("seed_parent_id"), c.PostForm("pollen_parent_id"), c.PostForm("self"))
+	if !ok {
+		c.String(http.StatusBadRequest, "Pick a seed parent and a pollen parent, or self")
+		return
+	}
+
+	cross := &types.PlannedCross{
+		SeedParentID:   seedParentID,
+		PollenParentID: pollenParentID,
+		Status:         types.CrossPlanned,
+		Notes:          nullString(c.PostForm("notes")),
+	}
+	if projectID, err := strconv.Atoi(c.PostForm("project_id")); err == nil {
+		cross.ProjectID = sql.NullInt64{Int64: int64(projectID), Valid: true}
+	}
+
+	if err := h.crossService.CreatePlannedCross(cross); err != nil {
+		log.Printf("Error creating planned cross: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", fmt.Sprintf("/crosses/%d", cross.ID))
+	c.Status(http.StatusCreated)
+}
+
+func (h *CrossHandler) HandlePlannedCross(c *gin.Context) {
+	crossID, err := strconv.Atoi(c.Param("crossId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	cross, err := h.crossService.GetPlannedCross(crossID)
+	if err != nil {
+		if errors.Is(err, services.ErrPlannedCrossNotFound) {
+			c.Redirect(http.StatusFound, "/crosses")
+			return
+		}
+		log.Printf("Error fetching planned cross: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	prediction, err := h.crossService.PredictCross(cross.SeedParentID, cross.PollenParentID)
+	if err != nil {
+		log.Printf("Error predicting cross: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PlannedCrossPage(*cross, prediction)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *CrossHandler) HandleCrossProgress(c *gin.Context) {
+	crossID, err := strconv.Atoi(c.Param("crossId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderProgress(c, crossID)
+}
+
+func (h *CrossHandler) HandleUpdateCrossStatus(c *gin.Context) {
+	crossID, err := strconv.Atoi(c.Param("crossId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	status, err := types.ParseCrossStatus(c.PostForm("status"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.crossService.UpdateStatus(crossID, status); err != nil {
+		if errors.Is(err, services.ErrPlannedCrossNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error updating planned cross status: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderProgress(c, crossID)
+}
+
+func (h *CrossHandler) HandleDeletePlannedCross(c *gin.Context) {
+	crossID, err := strconv.Atoi(c.Param("crossId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.crossService.DeletePlannedCross(crossID); err != nil {
+		if errors.Is(err, services.ErrPlannedCrossNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting planned cross: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/crosses")
+	c.Status(http.StatusOK)
+}
+
+func (h *CrossHandler) HandleLinkPollination(c *gin.Context) {
+	crossID, err := strconv.Atoi(c.Param("crossId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	pollinationID, err := strconv.Atoi(c.PostForm("pollination_id"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Pick a pollination")
+		return
+	}
+
+	if err := h.crossService.LinkPollination(crossID, pollinationID); err != nil {
+		if errors.Is(err, services.ErrPollinationMismatch) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error linking pollination: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderProgress(c, crossID)
+}
+
+func (h *CrossHandler) HandleUnlinkPollination(c *gin.Context) {
+	crossID, err := strconv.Atoi(c.Param("crossId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	pollinationID, err := strconv.Atoi(c.Param("pollinationId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.crossService.UnlinkPollination(crossID, pollinationID); err != nil {
+		log.Printf("Error unlinking pollination: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderProgress(c, crossID)
+}
+
+// renderProgress re-renders the status, pollinations and offspring of a
+// planned cross.
+func (h *CrossHandler) renderProgress(c *gin.Context, crossID int) {
+	cross, err := h.crossService.GetPlannedCross(crossID)
+	if err != nil {
+		if errors.Is(err, services.ErrPlannedCrossNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error fetching planned cross: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	pollinations, err := h.crossService.GetCrossPollinations(crossID)
+	if err != nil {
+		log.Printf("Error fetching cross pollinations: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	candidates, err := h.crossService.GetCandidatePollinations(cross)
+	if err != nil {
+		log.Printf("Error fetching candidate pollinations: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	offspring, err := h.crossService.GetCrossOffspring(crossID)
+	if err != nil {
+		log.Printf("Error fetching cross offspring: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.CrossProgress(*cross, pollinations, candidates, offspring)).ServeHTTP(c.Writer, c.Request)
+}
+
+// crossParents reads the parents picked for a cross. A self uses the seed
+// parent on both sides.
+func crossParents(seedParent, pollenParent, self string) (int, int, bool) {
+	seedParentID, err := strconv.Atoi(seedParent)
+	if err != nil {
+		return 0, 0, false
+	}
+	if self != "" {
+		return seedParentID, seedParentID, true
+	}
+	pollenParentID, err := strconv.Atoi(pollenParent)
+	if err != nil {
+		return 0, 0, false
+	}
+	return seedParentID, pollenParentID, true
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"time"
+)
+
+type GenotypeHandler struct {
+	genotypeService *services.GenotypeService
+}
+
+func NewGenotypeHandler(genotypeService *services.GenotypeService) *GenotypeHandler {
+	return &GenotypeHandler{genotypeService: genotypeService}
+}
+
+func (h *GenotypeHandler) HandlePlantGenotypes(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderPlantGenotypes(c, plantID)
+}
+
+func (h *GenotypeHandler) HandleRecordGenotype(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	genotype, err := types.ParseGenotype(c.PostForm("genotype"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	source, err := types.ParseGenotypeSource(c.PostForm("source"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	calledAt := time.Now()
+	if raw := c.PostForm("called_at"); raw != "" {
+		if calledAt, err = time.Parse("2006-01-02", raw); err != nil {
+			c.String(http.StatusBadRequest, "Invalid date")
+			return
+		}
+	}
+
+	call := &types.GenotypeCall{
+		PlantID:  plantID,
+		Locus:    c.PostForm("locus"),
+		Genotype: genotype,
+		Source:   source,
+		CalledAt: calledAt,
+		Notes:    nullString(c.PostForm("notes")),
+	}
+	if err := h.genotypeService.RecordCall(call); err != nil {
+		if errors.Is(err, services.ErrUnknownLocus) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error recording genotype call: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantGenotypes(c, plantID)
+}
+
+func (h *GenotypeHandler) HandleDeleteGenotype(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	genotypeID, err := strconv.Atoi(c.Param("genotypeId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.genotypeService.DeleteCall(plantID, genotypeID); err != nil {
+		if errors.Is(err, services.ErrGenotypeNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting genotype call: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantGenotypes(c, plantID)
+}
+
+func (h *GenotypeHandler) renderPlantGenotypes(c *gin.Context, plantID int) {
+	genotypes, err := h.genotypeService.GetPlantGenotypes(plantID)
+	if err != nil {
+		if errors.Is(err, services.ErrPlantNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error fetching genotypes: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PlantGenotypes(plantID, genotypes)).ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"time"
+)
+
+type GerminationHandler struct {
+	plantService   *services.PlantService
+	seedLotService *services.SeedLotService
+	varietyService *services.VarietyService
+}
+
+func NewGerminationHandler(plantService *services.PlantService, seedLotService *services.SeedLotService, varietyService *services.VarietyService) *GerminationHandler {
+	return &GerminationHandler{
+		plantService:   plantService,
+		seedLotService: seedLotService,
+		varietyService: varietyService,
+	}
+}
+
+func (h *GerminationHandler) HandleTrialList(c *gin.Context) {
+	trials, err := h.plantService.GetGerminationTrials()
+	if err != nil {
+		log.Printf("Error fetching germination trials: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	seedLots, err := h.seedLotService.GetAvailableSeedLots()
+	if err != nil {
+		log.Printf("Error fetching seed lots: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	varieties, err := h.varietyService.GetVarieties()
+	if err != nil {
+		log.Printf("Error fetching varieties: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	// The seed inventory links here to start a trial from a lot
+	selectedLot, _ := strconv.Atoi(c.Query("seed_lot_id"))
+
+	templ.Handler(pages.GerminationList(trials, seedLots, varieties, selectedLot)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *GerminationHandler) HandleCreateTrial(c *gin.Context) {
+	method, err := types.ParseGerminationMethod(c.PostForm("method"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	seedsSown, err := strconv.Atoi(c.PostForm("seeds_sown"))
+	if err != nil || seedsSown < 1 {
+		c.String(http.StatusBadRequest, "At least one seed must be sown")
+		return
+	}
+
+	sowDate, err := time.Parse("2006-01-02", c.PostForm("sow_date"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid sow date")
+		return
+	}
+
+	trial := &types.GerminationTrial{
+		SeedsSown: seedsSown,
+		Method:    method,
+		SowDate:   sowDate,
+		Notes:     nullString(c.PostForm("notes")),
+	}
+	if trial.SeedLotID, err = parseNullInt(c.PostForm("seed_lot_id")); err != nil {
+		c.String(http.StatusBadRequest, "Invalid seed lot")
+		return
+	}
+	if trial.VarietyID, err = parseNullInt(c.PostForm("variety_id")); err != nil {
+		c.String(http.StatusBadRequest, "Invalid variety")
+		return
+	}
+	if !trial.SeedLotID.Valid && !trial.VarietyID.Valid {
+		c.String(http.StatusBadRequest, "Choose a seed lot or a variety")
+		return
+	}
+	if trial.TemperatureC, err = parseNullFloat(c.PostForm("temperature_c")); err != nil {
+		c.String(http.StatusBadRequest, "Invalid temperature")
+		return
+	}
+
+	if err := h.plantService.CreateGerminationTrial(trial); err != nil {
+		if errors.Is(err, services.ErrNotEnoughSeeds) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error creating germination trial: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", fmt.Sprintf("/germination/%d", trial.ID))
+	c.Status(http.StatusCreated)
+}
+
+func (h *GerminationHandler) HandleTrial(c *gin.Context) {
+	trialID, err := strconv.Atoi(c.Param("trialId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	trial, err := h.plantService.GetGerminationTrial(trialID)
+	if err != nil {
+		if errors.Is(err, services.ErrGerminationTrialNotFound) {
+			c.Redirect(http.StatusFound, "/germination")
+			return
+		}
+		log.Printf("Error fetching germination trial: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	counts, err := h.plantService.GetGerminationCounts(trialID)
+	if err != nil {
+		log.Printf("Error fetching germination counts: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	plants, err := h.plantService.GetGerminationTrialPlants(trialID)
+	if err != nil {
+		log.Printf("Error fetching trial plants: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.GerminationTrialPage(*trial, counts, plants)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *GerminationHandler) HandleRecordCount(c *gin.Context) {
+	trialID, err := strconv.Atoi(c.Param("trialId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	countedOn, err := time.Parse("2006-01-02", c.PostForm("counted_on"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid date")
+		return
+	}
+
+	germinated, err := strconv.Atoi(c.PostForm("germinated"))
+	if err != nil || germinated < 0 {
+		c.String(http.StatusBadRequest, "Invalid germination count")
+		return
+	}
+
+	if err := h.plantService.RecordGerminationCount(trialID, countedOn, germinated); err != nil {
+		switch {
+		case errors.Is(err, services.ErrGerminationTrialNotFound):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, services.ErrTooManyGerminated),
+			errors.Is(err, services.ErrCountBeforeSowing):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error recording germination count: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", fmt.Sprintf("/germination/%d", trialID))
+	c.Status(http.StatusCreated)
+}
+
+func (h *GerminationHandler) HandleDeleteCount(c *gin.Context) {
+	trialID, err := strconv.Atoi(c.Param("trialId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	countID, err := strconv.Atoi(c.Param("countId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.plantService.DeleteGerminationCount(trialID, countID); err != nil {
+		if errors.Is(err, services.ErrGerminationTrialNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting germination count: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", fmt.Sprintf("/germination/%d", trialID))
+	c.Status(http.StatusOK)
+}
+
+func (h *GerminationHandler) HandlePromoteSeedlings(c *gin.Context) {
+	trialID, err := strconv.Atoi(c.Param("trialId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	count, err := strconv.Atoi(c.PostForm("count"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid seedling count")
+		return
+	}
+
+	if err := h.plantService.PromoteSeedlings(trialID, count, c.PostForm("name")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrGerminationTrialNotFound):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, services.ErrNoSeedlingsLeft):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error promoting seedlings: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", fmt.Sprintf("/germination/%d", trialID))
+	c.Status(http.StatusCreated)
+}
+
+func (h *GerminationHandler) HandleDeleteTrial(c *gin.Context) {
+	trialID, err := strconv.Atoi(c.Param("trialId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.plantService.DeleteGerminationTrial(trialID); err != nil {
+		if errors.Is(err, services.ErrGerminationTrialNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting germination trial: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/germination")
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type HarvestHandler struct {
+	harvestService *services.HarvestService
+	fileService    *services.FileService
+	uploadDir      string
+}
+
+func NewHarvestHandler(harvestService *services.HarvestService, fileService *services.FileService) *HarvestHandler {
+	return &HarvestHandler{
+		harvestService: harvestService,
+		fileService:    fileService,
+		uploadDir:      "uploads",
+	}
+}
+
+func (h *HarvestHandler) HandlePlantHarvests(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderPlantHarvests(c, plantID)
+}
+
+func (h *HarvestHandler) HandleCreateHarvest(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	harvestDate, err := time.Parse("2006-01-02", c.PostForm("harvest_date"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid harvest date")
+		return
+	}
+
+	podCount, err := strconv.Atoi(c.PostForm("pod_count"))
+	if err != nil || podCount < 0 {
+		c.String(http.StatusBadRequest, "Invalid pod count")
+		return
+	}
+
+	grade, err := types.ParseQualityGrade(c.PostForm("quality_grade"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	harvest := &types.Harvest{
+		PlantID:      plantID,
+		HarvestDate:  harvestDate,
+		PodCount:     podCount,
+		QualityGrade: grade,
+		Notes:        nullString(c.PostForm("notes")),
+	}
+
+	if weight := strings.TrimSpace(c.PostForm("total_weight_grams")); weight != "" {
+		grams, err := strconv.ParseFloat(weight, 64)
+		if err != nil || grams < 0 {
+			c.String(http.StatusBadRequest, "Invalid weight")
+			return
+		}
+		harvest.TotalWeightGrams = sql.NullFloat64{Float64: grams, Valid: true}
+	}
+
+	var savedSeeds int
+	if seeds := strings.TrimSpace(c.PostForm("saved_seeds")); seeds != "" {
+		savedSeeds, err = strconv.Atoi(seeds)
+		if err != nil || savedSeeds < 0 {
+			c.String(http.StatusBadRequest, "Invalid seed count")
+			return
+		}
+	}
+
+	// Handle image upload if present
+	file, header, err := c.Request.FormFile("image")
+	if err == nil {
+		defer file.Close()
+
+		filePath := fmt.Sprintf("%s/harvests/%s", h.uploadDir, header.Filename)
+		if err := h.fileService.SaveFile(file, filePath); err != nil {
+			c.String(http.StatusInternalServerError, "Failed to save image")
+			return
+		}
+		harvest.ImagePath = sql.NullString{String: filePath, Valid: true}
+	}
+
+	if err := h.harvestService.CreateHarvest(harvest, savedSeeds); err != nil {
+		log.Printf("Error creating harvest: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantHarvests(c, plantID)
+}
+
+func (h *HarvestHandler) HandleDeleteHarvest(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	harvestID, err := strconv.Atoi(c.Param("harvestId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.harvestService.DeleteHarvest(plantID, harvestID); err != nil {
+		log.Printf("Error deleting harvest: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantHarvests(c, plantID)
+}
+
+func (h *HarvestHandler) renderPlantHarvests(c *gin.Context, plantID int) {
+	harvests, err := h.harvestService.GetPlantHarvests(plantID)
+	if err != nil {
+		log.Printf("Error fetching harvests: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	plantYield, err := h.harvestService.GetPlantYield(plantID)
+	if err != nil {
+		log.Printf("Error fetching plant yield: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	varietyYield, err := h.harvestService.GetPlantVarietyYield(plantID)
+	if err != nil {
+		log.Printf("Error fetching variety yield: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PlantHarvests(plantID, harvests, *plantYield, *varietyYield)).ServeHTTP(c.Writer, c.Request)
+}
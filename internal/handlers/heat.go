@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"time"
+)
+
+type HeatHandler struct {
+	heatService    *services.HeatService
+	harvestService *services.HarvestService
+	plantService   *services.PlantService
+}
+
+func NewHeatHandler(heatService *services.HeatService, harvestService *services.HarvestService, plantService *services.PlantService) *HeatHandler {
+	return &HeatHandler{
+		heatService:    heatService,
+		harvestService: harvestService,
+		plantService:   plantService,
+	}
+}
+
+func (h *HeatHandler) HandlePlantHeat(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderPlantHeat(c, plantID)
+}
+
+func (h *HeatHandler) HandleCreateHeatTest(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	score, err := strconv.Atoi(c.PostForm("panel_score"))
+	if err != nil || score < 0 || score >= len(types.HeatScale) {
+		c.String(http.StatusBadRequest, "Panel score must be between 0 and 10")
+		return
+	}
+
+	ripeness, err := types.ParsePodRipeness(c.PostForm("ripeness"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	testedAt := time.Now()
+	if raw := c.PostForm("tested_at"); raw != "" {
+		if testedAt, err = time.Parse("2006-01-02", raw); err != nil {
+			c.String(http.StatusBadRequest, "Invalid date")
+			return
+		}
+	}
+
+	var harvestID sql.NullInt64
+	if raw := c.PostForm("harvest_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			c.String(http.StatusBadRequest, "Invalid harvest")
+			return
+		}
+		harvestID = sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+
+	var shu sql.NullInt64
+	if raw := c.PostForm("shu"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			c.String(http.StatusBadRequest, "Invalid SHU value")
+			return
+		}
+		shu = sql.NullInt64{Int64: int64(value), Valid: true}
+	}
+
+	capsaicinoids, err := parseNullFloat(c.PostForm("capsaicinoids_mg_g"))
+	if err != nil || capsaicinoids.Float64 < 0 {
+		c.String(http.StatusBadRequest, "Invalid capsaicinoid content")
+		return
+	}
+
+	test := &types.HeatTest{
+		PlantID:       plantID,
+		HarvestID:     harvestID,
+		TestedAt:      testedAt,
+		PanelScore:    score,
+		SHU:           shu,
+		Capsaicinoids: capsaicinoids,
+		Tester:        nullString(c.PostForm("tester")),
+		Ripeness:      ripeness,
+		Notes:         nullString(c.PostForm("notes")),
+	}
+	if err := h.heatService.CreateHeatTest(test); err != nil {
+		if errors.Is(err, services.ErrHarvestNotFound) {
+			c.String(http.StatusBadRequest, "Invalid harvest")
+			return
+		}
+		log.Printf("Error creating heat test: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantHeat(c, plantID)
+}
+
+func (h *HeatHandler) HandleDeleteHeatTest(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	testID, err := strconv.Atoi(c.Param("testId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.heatService.DeleteHeatTest(plantID, testID); err != nil {
+		if errors.Is(err, services.ErrHeatTestNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting heat test: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantHeat(c, plantID)
+}
+
+// HandleHeatDistribution shows how heat spreads across the sibling families
+// of the generation picked with the generation query parameter.
+func (h *HeatHandler) HandleHeatDistribution(c *gin.Context) {
+	generations, err := h.plantService.GetGenerations()
+	if err != nil {
+		log.Printf("Error fetching generations: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	generation := c.Query("generation")
+	if parsed, err := types.ParseGeneration(generation); err == nil {
+		generation = parsed.String()
+	}
+
+	var distributions []types.HeatDistribution
+	if generation != "" {
+		distributions, err = h.heatService.GetSiblingDistribution(generation)
+		if err != nil {
+			log.Printf("Error fetching heat distribution: %v", err)
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	templ.Handler(pages.HeatDistributionPage(generations, generation, distributions)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *HeatHandler) renderPlantHeat(c *gin.Context, plantID int) {
+	tests, err := h.heatService.GetPlantHeatTests(plantID)
+	if err != nil {
+		log.Printf("Error fetching heat tests: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	summary, err := h.heatService.GetPlantHeatSummary(plantID)
+	if err != nil {
+		log.Printf("Error fetching heat summary: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	harvests, err := h.harvestService.GetPlantHarvests(plantID)
+	if err != nil {
+		log.Printf("Error fetching harvests: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PlantHeat(plantID, tests, summary, harvests)).ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/templates/pages"
+)
+
+type JobHandler struct {
+	schedulerService *services.SchedulerService
+}
+
+func NewJobHandler(schedulerService *services.SchedulerService) *JobHandler {
+	return &JobHandler{
+		schedulerService: schedulerService,
+	}
+}
+
+func (h *JobHandler) HandleJobList(c *gin.Context) {
+	jobs, err := h.schedulerService.GetJobs()
+	if err != nil {
+		log.Printf("Error fetching jobs: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	// The job names link here to show the history of one job
+	selected := c.Query("job")
+	runs, err := h.schedulerService.GetRuns(selected, 100)
+	if err != nil {
+		log.Printf("Error fetching job runs: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.JobList(jobs, runs, selected)).ServeHTTP(c.Writer, c.Request)
+}
+
+// respondJobError maps the scheduler's errors onto responses.
+func respondJobError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrJobNotFound):
+		c.Status(http.StatusNotFound)
+	case errors.Is(err, services.ErrInvalidSchedule),
+		errors.Is(err, services.ErrJobDisabled):
+		c.String(http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Error %s: %v", action, err)
+		c.Status(http.StatusInternalServerError)
+	}
+}
+
+func (h *JobHandler) HandleSetSchedule(c *gin.Context) {
+	if err := h.schedulerService.SetSchedule(c.Param("name"), c.PostForm("schedule")); err != nil {
+		respondJobError(c, err, "updating job schedule")
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/admin/jobs")
+	c.Status(http.StatusOK)
+}
+
+func (h *JobHandler) HandleSetEnabled(c *gin.Context) {
+	enabled := c.PostForm("enabled") == "true"
+	if err := h.schedulerService.SetEnabled(c.Param("name"), enabled); err != nil {
+		respondJobError(c, err, "updating job")
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/admin/jobs")
+	c.Status(http.StatusOK)
+}
+
+func (h *JobHandler) HandleRunNow(c *gin.Context) {
+	if err := h.schedulerService.RunNow(c.Param("name")); err != nil {
+		respondJobError(c, err, "queueing job")
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/admin/jobs?job="+c.Param("name"))
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/notify"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/email"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+	digestService       *services.DigestService
+}
+
+func NewNotificationHandler(notificationService *services.NotificationService, digestService *services.DigestService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		digestService:       digestService,
+	}
+}
+
+func (h *NotificationHandler) HandleNotifications(c *gin.Context) {
+	users, err := h.notificationService.GetUsers()
+	if err != nil {
+		log.Printf("Error fetching users: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	channels, err := h.notificationService.GetChannels()
+	if err != nil {
+		log.Printf("Error fetching notification channels: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, err := h.notificationService.GetRecentDeliveries(50)
+	if err != nil {
+		log.Printf("Error fetching notification deliveries: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.Notifications(users, channels, deliveries)).ServeHTTP(c.Writer, c.Request)
+}
+
+// parseQuietHours reads the quiet window from the form. Leaving both times
+// empty turns quiet hours off.
+func parseQuietHours(c *gin.Context) (types.QuietHours, error) {
+	quiet := types.QuietHours{Timezone: strings.TrimSpace(c.PostForm("timezone"))}
+	if quiet.Timezone == "" {
+		quiet.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(quiet.Timezone); err != nil {
+		return quiet, errors.New("unknown time zone")
+	}
+
+	start, end := c.PostForm("quiet_start"), c.PostForm("quiet_end")
+	if start == "" && end == "" {
+		return quiet, nil
+	}
+	startTime, err := time.Parse("15:04", start)
+	if err != nil {
+		return quiet, errors.New("invalid quiet hours start")
+	}
+	endTime, err := time.Parse("15:04", end)
+	if err != nil {
+		return quiet, errors.New("invalid quiet hours end")
+	}
+	quiet.Start = sql.NullTime{Time: startTime, Valid: true}
+	quiet.End = sql.NullTime{Time: endTime, Valid: true}
+	return quiet, nil
+}
+
+func (h *NotificationHandler) HandleCreateUser(c *gin.Context) {
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" {
+		c.String(http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	quiet, err := parseQuietHours(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user := &types.User{Name: name, QuietHours: quiet}
+	if err := h.notificationService.CreateUser(user); err != nil {
+		log.Printf("Error creating user: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/notifications")
+	c.Status(http.StatusCreated)
+}
+
+func (h *NotificationHandler) HandleSetQuietHours(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	quiet, err := parseQuietHours(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.notificationService.SetQuietHours(userID, quiet); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error updating quiet hours: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/notifications")
+	c.Status(http.StatusOK)
+}
+
+func (h *NotificationHandler) HandleDeleteUser(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.notificationService.DeleteUser(userID); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting user: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/notifications")
+	c.Status(http.StatusOK)
+}
+
+func (h *NotificationHandler) HandleCreateChannel(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	kind, err := types.ParseChannelKind(c.PostForm("kind"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	channel := &types.NotificationChannel{
+		UserID: userID,
+		Kind:   kind,
+		Target: strings.TrimSpace(c.PostForm("target")),
+		Token:  nullString(c.PostForm("token")),
+		Events: []string{},
+	}
+	for _, raw := range c.PostFormArray("events") {
+		event, err := types.ParseNotificationEvent(raw)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		channel.Events = append(channel.Events, string(event))
+	}
+
+	if err := h.notificationService.CreateChannel(channel); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, notify.ErrInvalidTarget),
+			errors.Is(err, notify.ErrSMTPNotConfigured):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error creating notification channel: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/notifications")
+	c.Status(http.StatusCreated)
+}
+
+func (h *NotificationHandler) HandleSetChannelEnabled(c *gin.Context) {
+	channelID, err := strconv.Atoi(c.Param("channelId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	enabled := c.PostForm("enabled") == "true"
+	if err := h.notificationService.SetChannelEnabled(channelID, enabled); err != nil {
+		if errors.Is(err, services.ErrChannelNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error updating notification channel: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/notifications")
+	c.Status(http.StatusOK)
+}
+
+func (h *NotificationHandler) HandleDeleteChannel(c *gin.Context) {
+	channelID, err := strconv.Atoi(c.Param("channelId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.notificationService.DeleteChannel(channelID); err != nil {
+		if errors.Is(err, services.ErrChannelNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting notification channel: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/notifications")
+	c.Status(http.StatusOK)
+}
+
+// HandleTestChannel sends a test message and swaps in the outcome next to
+// the channel's test button.
+func (h *NotificationHandler) HandleTestChannel(c *gin.Context) {
+	channelID, err := strconv.Atoi(c.Param("channelId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	err = h.notificationService.SendTest(ctx, channelID)
+	if errors.Is(err, services.ErrChannelNotFound) {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	templ.Handler(pages.ChannelTestResult(err)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *NotificationHandler) HandleSetDigest(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	frequency, err := types.ParseDigestFrequency(c.PostForm("digest_frequency"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sections := []string{}
+	for _, raw := range c.PostFormArray("digest_sections") {
+		section, err := types.ParseDigestSection(raw)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		sections = append(sections, string(section))
+	}
+	// Every section ticked is stored as none, so new sections are included
+	if len(sections) == len(types.DigestSections) {
+		sections = []string{}
+	}
+
+	if err := h.notificationService.SetDigest(userID, frequency, sections); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error updating digest settings: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/notifications")
+	c.Status(http.StatusOK)
+}
+
+// HandleDigestPreview shows the user's digest as it would be emailed now.
+func (h *NotificationHandler) HandleDigestPreview(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.notificationService.GetUser(userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error fetching user: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	digest, err := h.digestService.BuildDigest(*user, time.Now())
+	if err != nil {
+		log.Printf("Error building digest: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(email.Digest(*digest)).ServeHTTP(c.Writer, c.Request)
+}
+
+// HandleSendDigest sends the user their digest now, even when it is empty,
+// and swaps in the outcome next to the send button.
+func (h *NotificationHandler) HandleSendDigest(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.notificationService.GetUser(userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error fetching user: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	queued, err := h.digestService.SendDigest(ctx, *user, time.Now(), true)
+	if err == nil && queued == 0 {
+		err = errors.New("no enabled channel takes the digest")
+	}
+	templ.Handler(pages.ChannelTestResult(err)).ServeHTTP(c.Writer, c.Request)
+}
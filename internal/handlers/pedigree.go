@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+)
+
+type PedigreeHandler struct {
+	pedigreeService *services.PedigreeService
+	plantService    *services.PlantService
+}
+
+func NewPedigreeHandler(pedigreeService *services.PedigreeService, plantService *services.PlantService) *PedigreeHandler {
+	return &PedigreeHandler{
+		pedigreeService: pedigreeService,
+		plantService:    plantService,
+	}
+}
+
+func (h *PedigreeHandler) HandlePlantInbreeding(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	inbreeding, err := h.pedigreeService.GetInbreeding(plantID)
+	if err != nil {
+		log.Printf("Error computing inbreeding: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PlantInbreeding(plantID, inbreeding)).ServeHTTP(c.Writer, c.Request)
+}
+
+// HandleRelatedness compares two plants picked with the a and b query
+// parameters. With only one picked the form is shown empty.
+func (h *PedigreeHandler) HandleRelatedness(c *gin.Context) {
+	plants, err := h.plantService.GetPlantOptions()
+	if err != nil {
+		log.Printf("Error fetching plant options: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	plantAID, _ := strconv.Atoi(c.Query("a"))
+	plantBID, _ := strconv.Atoi(c.Query("b"))
+
+	var relationship *types.Relationship
+	if plantAID != 0 && plantBID != 0 {
+		relationship, err = h.pedigreeService.GetRelationship(plantAID, plantBID)
+		if err != nil && !errors.Is(err, services.ErrPlantNotFound) {
+			log.Printf("Error computing relationship: %v", err)
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	templ.Handler(pages.Relatedness(plants, plantAID, plantBID, relationship)).ServeHTTP(c.Writer, c.Request)
+}
@@ -3,46 +3,44 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/a-h/templ"
 	"github.com/gin-gonic/gin"
 	"log"
 	"net/http"
+	"pepper-analytics-ai/internal/pedigree"
 	"pepper-analytics-ai/internal/services"
 	"pepper-analytics-ai/internal/types"
 	"pepper-analytics-ai/templates/pages"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type PlantHandler struct {
-	plantService *services.PlantService
-	fileService  *services.FileService
-	uploadDir    string
+	plantService   *services.PlantService
+	seedLotService *services.SeedLotService
+	varietyService *services.VarietyService
+	speciesService *services.SpeciesService
+	fileService    *services.FileService
+	uploadDir      string
 }
 
-func NewPlantHandler(plantService *services.PlantService, fileService *services.FileService) *PlantHandler {
+func NewPlantHandler(plantService *services.PlantService, seedLotService *services.SeedLotService, varietyService *services.VarietyService, speciesService *services.SpeciesService, fileService *services.FileService) *PlantHandler {
 	return &PlantHandler{
-		plantService: plantService,
-		fileService:  fileService,
-		uploadDir:    "uploads",
+		plantService:   plantService,
+		seedLotService: seedLotService,
+		varietyService: varietyService,
+		speciesService: speciesService,
+		fileService:    fileService,
+		uploadDir:      "uploads",
 	}
 }
 
 func (h *PlantHandler) HandlePlantList(c *gin.Context) {
-	// Get filter parameters
-	growthStageFilter := c.Query("growth_stage_filter")
-	speciesFilter := c.Query("species_filter")
-	crossFilter := c.Query("cross_filter")
-	harvestFilter := c.Query("harvest_filter")
-
 	// Get all plants with dates and filters
-	plants, err := h.plantService.GetPlantsWithFilters(
-		growthStageFilter,
-		speciesFilter,
-		crossFilter,
-		harvestFilter,
-	)
+	plants, err := h.plantService.GetPlantsWithFilters(plantFiltersFromQuery(c))
 	if err != nil {
 		log.Printf("Error fetching plants: %v", err)
 		c.Status(http.StatusInternalServerError)
@@ -58,14 +56,97 @@ func (h *PlantHandler) HandlePlantList(c *gin.Context) {
 		return
 	}
 
+	options, err := h.plantService.GetPlantFilterOptions()
+	if err != nil {
+		log.Printf("Error fetching filter options: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	alerts, err := h.plantService.GetOpenHealthAlerts()
+	if err != nil {
+		log.Printf("Error fetching health alerts: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
 	// Otherwise return the full page
-	if err := pages.Plant(plants).Render(context.Background(), c.Writer); err != nil {
+	if err := pages.Plant(plants, *options, alerts).Render(context.Background(), c.Writer); err != nil {
 		log.Printf("Error rendering template: %v", err)
 		c.Status(http.StatusInternalServerError)
 		return
 	}
 }
 
+// plantFiltersFromQuery reads the plant list filters that the grid forwards
+// with every request so the current view survives an update.
+func plantFiltersFromQuery(c *gin.Context) types.PlantFilters {
+	// A project that isn't a number is no filter at all
+	project, _ := strconv.Atoi(c.Query("project_filter"))
+	return types.PlantFilters{
+		GrowthStage: c.Query("growth_stage_filter"),
+		Species:     c.Query("species_filter"),
+		Cross:       c.Query("cross_filter"),
+		Season:      c.Query("season_filter"),
+		Generation:  c.Query("generation_filter"),
+		Project:     project,
+		Heat:        c.Query("heat_filter"),
+		Care:        c.Query("care_filter"),
+		Sort:        c.Query("sort"),
+	}
+}
+
+// parseGenerationForm validates the generation field. A blank value is
+// allowed and left for derivation from the parents.
+func parseGenerationForm(c *gin.Context) (sql.NullString, error) {
+	raw := c.PostForm("generation")
+	if strings.TrimSpace(raw) == "" {
+		return sql.NullString{}, nil
+	}
+
+	generation, err := types.ParseGeneration(raw)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: generation.String(), Valid: true}, nil
+}
+
+// applyDerivedGeneration fills in a blank generation from the plant's parents.
+func (h *PlantHandler) applyDerivedGeneration(plant *types.PlantWithDates) error {
+	if plant.Generation.Valid {
+		return nil
+	}
+
+	generation, ok, err := h.plantService.DeriveGeneration(plant)
+	if err != nil || !ok {
+		return err
+	}
+
+	plant.IsCross = true
+	plant.Generation = sql.NullString{String: generation.String(), Valid: true}
+	return nil
+}
+
+// applyVariety sets the plant's variety from the form. A blank choice
+// matches the variety from the plant name, except for crosses.
+func (h *PlantHandler) applyVariety(c *gin.Context, plant *types.PlantWithDates) error {
+	if id, err := strconv.Atoi(c.PostForm("variety_id")); err == nil {
+		plant.VarietyID = sql.NullInt64{Int64: int64(id), Valid: true}
+		return nil
+	}
+	if plant.IsCross {
+		plant.VarietyID = sql.NullInt64{}
+		return nil
+	}
+
+	varietyID, err := h.varietyService.ResolveVariety(plant.Name, plant.Species)
+	if err != nil {
+		return err
+	}
+	plant.VarietyID = varietyID
+	return nil
+}
+
 func (h *PlantHandler) HandleCreatePlant(c *gin.Context) {
 	log.Printf("Received form data: %+v", c.Request.Form)
 
@@ -90,10 +171,14 @@ func (h *PlantHandler) HandleCreatePlant(c *gin.Context) {
 		return
 	}
 
-	species, err := types.ParseSpecies(c.PostForm("species"))
+	species, err := h.speciesService.Resolve(c.PostForm("species"))
 	if err != nil {
-		log.Printf("Error parsing species: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		log.Printf("Error resolving species: %v", err)
+		if errors.Is(err, services.ErrUnknownSpecies) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve species"})
+		}
 		return
 	}
 
@@ -101,9 +186,11 @@ func (h *PlantHandler) HandleCreatePlant(c *gin.Context) {
 	isCross := c.PostForm("cross") == "Yes"
 	generation := sql.NullString{}
 	if isCross {
-		generation = sql.NullString{
-			String: c.PostForm("generation"),
-			Valid:  true,
+		generation, err = parseGenerationForm(c)
+		if err != nil {
+			log.Printf("Error parsing generation: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 	}
 
@@ -118,6 +205,57 @@ func (h *PlantHandler) HandleCreatePlant(c *gin.Context) {
 		Generation:   generation,
 	}
 
+	h.setParentsFromForm(c, plant)
+	if err := setCareIntervalsFromForm(c, plant); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Sowing from a seed lot takes the parents from the lot
+	var seedLot *types.SeedLot
+	seedsUsed := 1
+	if lotID, err := strconv.Atoi(c.PostForm("seed_lot_id")); err == nil {
+		seedLot, err = h.seedLotService.GetSeedLot(lotID)
+		if err != nil {
+			log.Printf("Error fetching seed lot: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if n, err := strconv.Atoi(c.PostForm("seeds_used")); err == nil {
+			seedsUsed = n
+		}
+		if seedsUsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "At least one seed must be used"})
+			return
+		}
+
+		plant.SeedParentID = sql.NullInt64{Int64: int64(seedLot.PlantID), Valid: true}
+		plant.SeedParentExt = sql.NullString{}
+		plant.PollenParentID = seedLot.PollenParentID
+		plant.PollenParentExt = seedLot.PollenParentExt
+	}
+
+	if err := h.plantService.ValidateParents(0, plant.SeedParentID, plant.PollenParentID); err != nil {
+		log.Printf("Error validating parents: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// An explicit "not a cross" sticks even when parents are given
+	if plant.IsCross {
+		if err := h.applyDerivedGeneration(plant); err != nil {
+			log.Printf("Error deriving generation: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to derive generation"})
+			return
+		}
+	}
+
+	if err := h.applyVariety(c, plant); err != nil {
+		log.Printf("Error resolving variety: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve variety"})
+		return
+	}
+
 	// Handle image upload
 	file, header, err := c.Request.FormFile("image")
 	if err == nil {
@@ -132,7 +270,16 @@ func (h *PlantHandler) HandleCreatePlant(c *gin.Context) {
 		plant.ImagePath = filePath
 	}
 
-	if err := h.plantService.CreatePlant(plant); err != nil {
+	if seedLot != nil {
+		err = h.plantService.SowPlant(plant, seedLot.ID, seedsUsed)
+	} else {
+		err = h.plantService.CreatePlant(plant)
+	}
+	if errors.Is(err, services.ErrNotEnoughSeeds) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		log.Printf("Error creating plant: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create plant"})
 		return
@@ -141,19 +288,8 @@ func (h *PlantHandler) HandleCreatePlant(c *gin.Context) {
 	// Set header to trigger modal close
 	c.Writer.Header().Set("HX-Trigger", "closeModal")
 
-	// Get any current filter values from the request
-	growthStageFilter := c.Query("growth_stage_filter")
-	speciesFilter := c.Query("species_filter")
-	crossFilter := c.Query("cross_filter")
-	harvestFilter := c.Query("harvest_filter")
-
 	// After successful creation, fetch all plants with dates and current filters
-	plants, err := h.plantService.GetPlantsWithFilters(
-		growthStageFilter,
-		speciesFilter,
-		crossFilter,
-		harvestFilter,
-	)
+	plants, err := h.plantService.GetPlantsWithFilters(plantFiltersFromQuery(c))
 	if err != nil {
 		log.Printf("Error fetching plants: %v", err)
 		c.Status(http.StatusInternalServerError)
@@ -164,7 +300,35 @@ func (h *PlantHandler) HandleCreatePlant(c *gin.Context) {
 	templ.Handler(pages.PlantsGrid(plants)).ServeHTTP(c.Writer, c.Request)
 }
 func (h *PlantHandler) HandleNewPlantForm(c *gin.Context) {
-	component := pages.NewPlantForm()
+	parents, err := h.plantService.GetPlantOptions()
+	if err != nil {
+		log.Printf("Error fetching parent options: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	seedLots, err := h.seedLotService.GetAvailableSeedLots()
+	if err != nil {
+		log.Printf("Error fetching seed lots: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	varieties, err := h.varietyService.GetVarieties()
+	if err != nil {
+		log.Printf("Error fetching varieties: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	species, err := h.speciesService.GetSpecies()
+	if err != nil {
+		log.Printf("Error fetching species: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	component := pages.NewPlantForm(parents, seedLots, varieties, species)
 	_ = component.Render(context.Background(), c.Writer)
 }
 
@@ -181,7 +345,28 @@ func (h *PlantHandler) HandleEditPlantForm(c *gin.Context) {
 		return
 	}
 
-	component := pages.EditPlantForm(*plant)
+	parents, err := h.plantService.GetPlantOptions()
+	if err != nil {
+		log.Printf("Error fetching parent options: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	varieties, err := h.varietyService.GetVarieties()
+	if err != nil {
+		log.Printf("Error fetching varieties: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	species, err := h.speciesService.GetSpecies()
+	if err != nil {
+		log.Printf("Error fetching species: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	component := pages.EditPlantForm(*plant, parents, varieties, species)
 	_ = component.Render(context.Background(), c.Writer)
 }
 
@@ -207,9 +392,17 @@ func (h *PlantHandler) HandleUpdatePlant(c *gin.Context) {
 	}
 	plant.PlantingDate = plantingDate
 
-	if species, err := types.ParseSpecies(c.PostForm("species")); err == nil {
-		plant.Species = species
+	species, err := h.speciesService.Resolve(c.PostForm("species"))
+	if err != nil {
+		log.Printf("Error resolving species: %v", err)
+		if errors.Is(err, services.ErrUnknownSpecies) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve species"})
+		}
+		return
 	}
+	plant.Species = species
 
 	if health, err := types.ParsePlantHealth(c.PostForm("health")); err == nil {
 		plant.Health = health
@@ -222,9 +415,11 @@ func (h *PlantHandler) HandleUpdatePlant(c *gin.Context) {
 	// Update cross status and generation
 	plant.IsCross = c.PostForm("cross") == "Yes"
 	if plant.IsCross {
-		plant.Generation = sql.NullString{
-			String: c.PostForm("generation"),
-			Valid:  true,
+		plant.Generation, err = parseGenerationForm(c)
+		if err != nil {
+			log.Printf("Error parsing generation: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 	} else {
 		plant.Generation = sql.NullString{}
@@ -232,6 +427,33 @@ func (h *PlantHandler) HandleUpdatePlant(c *gin.Context) {
 
 	plant.Notes = c.PostForm("notes")
 
+	h.setParentsFromForm(c, plant)
+	if err := setCareIntervalsFromForm(c, plant); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.plantService.ValidateParents(plant.ID, plant.SeedParentID, plant.PollenParentID); err != nil {
+		log.Printf("Error validating parents: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Only a plant kept as a cross gets a generation worked out, so one
+	// with parents can still be marked as not a cross
+	if plant.IsCross {
+		if err := h.applyDerivedGeneration(plant); err != nil {
+			log.Printf("Error deriving generation: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to derive generation"})
+			return
+		}
+	}
+
+	if err := h.applyVariety(c, plant); err != nil {
+		log.Printf("Error resolving variety: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve variety"})
+		return
+	}
+
 	// Handle new image upload
 	file, header, err := c.Request.FormFile("image")
 	if err == nil {
@@ -249,27 +471,31 @@ func (h *PlantHandler) HandleUpdatePlant(c *gin.Context) {
 		plant.ImagePath = filePath
 	}
 
-	if err := h.plantService.UpdatePlant(plant); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plant"})
+	stageChangedOn := time.Now()
+	if raw := c.PostForm("stage_changed_on"); raw != "" {
+		if stageChangedOn, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stage change date"})
+			return
+		}
+	}
+
+	if err := h.plantService.UpdatePlant(plant, stageChangedOn, c.PostForm("stage_reason"), c.PostForm("health_note")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrTransitionBeforeSowing),
+			errors.Is(err, services.ErrTransitionOutOfSequence):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Error updating plant: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plant"})
+		}
 		return
 	}
 
 	// Set header to trigger modal close
 	c.Writer.Header().Set("HX-Trigger", "closeModal")
 
-	// Get any current filter values from the request
-	growthStageFilter := c.Query("growth_stage_filter")
-	speciesFilter := c.Query("species_filter")
-	crossFilter := c.Query("cross_filter")
-	harvestFilter := c.Query("harvest_filter")
-
 	// After successful update, fetch all plants with dates and current filters
-	plants, err := h.plantService.GetPlantsWithFilters(
-		growthStageFilter,
-		speciesFilter,
-		crossFilter,
-		harvestFilter,
-	)
+	plants, err := h.plantService.GetPlantsWithFilters(plantFiltersFromQuery(c))
 	if err != nil {
 		log.Printf("Error fetching plants: %v", err)
 		c.Status(http.StatusInternalServerError)
@@ -306,6 +532,141 @@ func (h *PlantHandler) HandleDeletePlant(c *gin.Context) {
 	c.String(http.StatusOK, "")
 }
 
+// setCareIntervalsFromForm reads the plant's own watering and fertilizing
+// intervals. Blank ones fall back to the variety and then the stage.
+func setCareIntervalsFromForm(c *gin.Context, plant *types.PlantWithDates) error {
+	var err error
+	if plant.WateringInterval, err = parseCareInterval(c.PostForm("watering_interval_days")); err != nil {
+		return errors.New("Invalid watering interval")
+	}
+	if plant.FertilizingInterval, err = parseCareInterval(c.PostForm("fertilizing_interval_days")); err != nil {
+		return errors.New("Invalid fertilizing interval")
+	}
+	return nil
+}
+
+// setParentsFromForm reads the seed and pollen parent pickers. A parent is
+// either a plant in the system or a free-text external variety, never both.
+func (h *PlantHandler) setParentsFromForm(c *gin.Context, plant *types.PlantWithDates) {
+	plant.SeedParentID, plant.SeedParentExt = parseParentForm(c, "seed_parent")
+	plant.PollenParentID, plant.PollenParentExt = parseParentForm(c, "pollen_parent")
+}
+
+func parseParentForm(c *gin.Context, prefix string) (sql.NullInt64, sql.NullString) {
+	if id, err := strconv.ParseInt(c.PostForm(prefix+"_id"), 10, 64); err == nil {
+		return sql.NullInt64{Int64: id, Valid: true}, sql.NullString{}
+	}
+
+	external := strings.TrimSpace(c.PostForm(prefix + "_external"))
+	if external == "" {
+		return sql.NullInt64{}, sql.NullString{}
+	}
+	return sql.NullInt64{}, sql.NullString{String: external, Valid: true}
+}
+
+func (h *PlantHandler) HandleLineage(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	lineage, err := h.plantService.GetLineage(plantID)
+	if err != nil {
+		log.Printf("Error fetching lineage: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.Lineage(*lineage)).ServeHTTP(c.Writer, c.Request)
+}
+
+// HandlePedigreeDOT serves the plant's family tree as a Graphviz DOT file.
+func (h *PlantHandler) HandlePedigreeDOT(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	graph, err := h.buildPedigreeGraph(plantID)
+	if err != nil {
+		log.Printf("Error building pedigree graph: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	if graph == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=pedigree-%d.dot", plantID))
+	c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(graph.DOT()))
+}
+
+// HandlePedigreeSVG serves the plant's family tree laid out as SVG.
+func (h *PlantHandler) HandlePedigreeSVG(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	graph, err := h.buildPedigreeGraph(plantID)
+	if err != nil {
+		log.Printf("Error building pedigree graph: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	if graph == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", []byte(graph.SVG()))
+}
+
+// buildPedigreeGraph returns nil when the plant does not exist.
+func (h *PlantHandler) buildPedigreeGraph(plantID int) (*pedigree.Graph, error) {
+	roots, err := h.plantService.GetPedigreeNodes([]int{plantID})
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+
+	lineage, err := h.plantService.GetLineage(plantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Descendants may have a second parent outside the lineage
+	known := map[int]bool{plantID: true}
+	for _, node := range append(lineage.Ancestors, lineage.Descendants...) {
+		known[node.ID] = true
+	}
+	var missing []int
+	for _, node := range lineage.Descendants {
+		for _, parentID := range []sql.NullInt64{node.SeedParentID, node.PollenParentID} {
+			if parentID.Valid && !known[int(parentID.Int64)] {
+				known[int(parentID.Int64)] = true
+				missing = append(missing, int(parentID.Int64))
+			}
+		}
+	}
+
+	var coParents []types.PedigreeNode
+	if len(missing) > 0 {
+		if coParents, err = h.plantService.GetPedigreeNodes(missing); err != nil {
+			return nil, err
+		}
+	}
+
+	graph := pedigree.Build(roots[0], *lineage, coParents)
+	return &graph, nil
+}
+
 func (h *PlantHandler) HandleJournal(c *gin.Context) {
 	plantID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -471,31 +832,23 @@ func (h *PlantHandler) HandleUpdateJournalEntry(c *gin.Context) {
 	templ.Handler(pages.JournalEntry(*entry)).ServeHTTP(c.Writer, c.Request)
 }
 
-func (h *PlantHandler) HandleHarvestPlant(c *gin.Context) {
+// HandleFinishSeason ends a plant's season. Individual pickings are logged
+// separately as harvest events.
+func (h *PlantHandler) HandleFinishSeason(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.Status(http.StatusBadRequest)
 		return
 	}
 
-	if err := h.plantService.MarkPlantAsHarvested(id); err != nil {
-		log.Printf("Error marking plant as harvested: %v", err)
+	if err := h.plantService.MarkSeasonFinished(id); err != nil {
+		log.Printf("Error marking season as finished: %v", err)
 		c.Status(http.StatusInternalServerError)
 		return
 	}
 
 	// Get all plants with the current filters to maintain grid order
-	growthStageFilter := c.Query("growth_stage_filter")
-	speciesFilter := c.Query("species_filter")
-	crossFilter := c.Query("cross_filter")
-	harvestFilter := c.Query("harvest_filter")
-
-	plants, err := h.plantService.GetPlantsWithFilters(
-		growthStageFilter,
-		speciesFilter,
-		crossFilter,
-		harvestFilter,
-	)
+	plants, err := h.plantService.GetPlantsWithFilters(plantFiltersFromQuery(c))
 	if err != nil {
 		log.Printf("Error fetching plants: %v", err)
 		c.Status(http.StatusInternalServerError)
@@ -509,3 +862,184 @@ func (h *PlantHandler) HandleHarvestPlant(c *gin.Context) {
 		c.Status(http.StatusInternalServerError)
 	}
 }
+
+func (h *PlantHandler) HandleStageTimeline(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderStageTimeline(c, plantID)
+}
+
+func (h *PlantHandler) HandleAdvanceStage(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	on := time.Now()
+	if raw := c.PostForm("transitioned_on"); raw != "" {
+		if on, err = time.Parse("2006-01-02", raw); err != nil {
+			c.String(http.StatusBadRequest, "Invalid date")
+			return
+		}
+	}
+
+	if _, err := h.plantService.AdvanceStage(plantID, on); err != nil {
+		switch {
+		case errors.Is(err, services.ErrPlantNotFound):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, services.ErrFinalStage),
+			errors.Is(err, services.ErrTransitionBeforeSowing),
+			errors.Is(err, services.ErrTransitionOutOfSequence):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error advancing growth stage: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.renderStageTimeline(c, plantID)
+}
+
+func (h *PlantHandler) HandleMoveStage(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	stage, err := types.ParseGrowthStage(c.PostForm("to_stage"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	on := time.Now()
+	if raw := c.PostForm("transitioned_on"); raw != "" {
+		if on, err = time.Parse("2006-01-02", raw); err != nil {
+			c.String(http.StatusBadRequest, "Invalid date")
+			return
+		}
+	}
+
+	if err := h.plantService.MoveToStage(plantID, stage, on, c.PostForm("reason")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrPlantNotFound):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, services.ErrPlantGone),
+			errors.Is(err, services.ErrSameStage),
+			errors.Is(err, services.ErrTransitionBeforeSowing),
+			errors.Is(err, services.ErrTransitionOutOfSequence):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error moving growth stage: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.renderStageTimeline(c, plantID)
+}
+
+func (h *PlantHandler) renderStageTimeline(c *gin.Context, plantID int) {
+	plant, err := h.plantService.GetPlant(plantID)
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	transitions, err := h.plantService.GetStageTransitions(plantID)
+	if err != nil {
+		log.Printf("Error fetching stage transitions: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	durations, err := h.plantService.GetStageDurations(plantID)
+	if err != nil {
+		log.Printf("Error fetching stage durations: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.StageTimeline(*plant, transitions, *durations)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *PlantHandler) HandleHealthHistory(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderHealthHistory(c, plantID)
+}
+
+func (h *PlantHandler) HandleSetHealth(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	health, err := types.ParsePlantHealth(c.PostForm("health"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.plantService.SetHealth(plantID, health, c.PostForm("note")); err != nil {
+		if errors.Is(err, services.ErrPlantNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error setting plant health: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderHealthHistory(c, plantID)
+}
+
+func (h *PlantHandler) renderHealthHistory(c *gin.Context, plantID int) {
+	plant, err := h.plantService.GetPlant(plantID)
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	changes, err := h.plantService.GetHealthHistory(plantID)
+	if err != nil {
+		log.Printf("Error fetching health history: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.HealthHistory(*plant, changes)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *PlantHandler) HandleAcknowledgeHealthAlert(c *gin.Context) {
+	alertID, err := strconv.Atoi(c.Param("alertId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.plantService.AcknowledgeHealthAlert(alertID); err != nil {
+		if errors.Is(err, services.ErrHealthAlertNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error acknowledging health alert: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	// The alert is swapped out for this empty response
+	c.Status(http.StatusOK)
+}
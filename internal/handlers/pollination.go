@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PollinationHandler struct {
+	pollinationService *services.PollinationService
+	plantService       *services.PlantService
+}
+
+func NewPollinationHandler(pollinationService *services.PollinationService, plantService *services.PlantService) *PollinationHandler {
+	return &PollinationHandler{
+		pollinationService: pollinationService,
+		plantService:       plantService,
+	}
+}
+
+func (h *PollinationHandler) HandlePollinationOverview(c *gin.Context) {
+	open, err := h.pollinationService.GetOpenPollinations()
+	if err != nil {
+		log.Printf("Error fetching open pollinations: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	pairs, err := h.pollinationService.GetPairSuccessRates()
+	if err != nil {
+		log.Printf("Error fetching pair success rates: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	techniques, err := h.pollinationService.GetTechniqueSuccessRates()
+	if err != nil {
+		log.Printf("Error fetching technique success rates: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PollinationOverview(open, pairs, techniques)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *PollinationHandler) HandlePlantPollinations(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderPlantPollinations(c, plantID)
+}
+
+func (h *PollinationHandler) HandleCreatePollination(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	pollinationDate, err := time.Parse("2006-01-02", c.PostForm("pollination_date"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid pollination date")
+		return
+	}
+
+	method, err := types.ParseIsolationMethod(c.PostForm("isolation_method"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pollination := &types.Pollination{
+		PlantID:         plantID,
+		PollinationDate: pollinationDate,
+		FlowerTag:       nullString(c.PostForm("flower_tag")),
+		Emasculated:     c.PostForm("emasculated") == "on",
+		IsolationMethod: method,
+		Outcome:         types.PollinationPending,
+		Notes:           nullString(c.PostForm("notes")),
+	}
+	pollination.DonorPlantID, pollination.DonorExternal = parseParentForm(c, "donor")
+
+	if err := h.pollinationService.CreatePollination(pollination); err != nil {
+		log.Printf("Error creating pollination: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantPollinations(c, plantID)
+}
+
+func (h *PollinationHandler) HandleRecordOutcome(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	pollinationID, err := strconv.Atoi(c.Param("pollinationId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	outcome, err := types.ParsePollinationOutcome(c.PostForm("outcome"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.pollinationService.RecordOutcome(plantID, pollinationID, outcome, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, services.ErrPollinationNotFound):
+			c.Status(http.StatusNotFound)
+			return
+		case errors.Is(err, services.ErrOutcomeLocked):
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error recording pollination outcome: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantPollinations(c, plantID)
+}
+
+// HandleSaveSeed turns the pod of a successful pollination into a seed lot.
+// The seed count comes from an hx-prompt.
+func (h *PollinationHandler) HandleSaveSeed(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	pollinationID, err := strconv.Atoi(c.Param("pollinationId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	seedCount, err := strconv.Atoi(strings.TrimSpace(c.GetHeader("HX-Prompt")))
+	if err != nil || seedCount < 1 {
+		c.String(http.StatusBadRequest, "Invalid seed count")
+		return
+	}
+
+	if _, err := h.pollinationService.SaveSeed(plantID, pollinationID, seedCount, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, services.ErrPollinationNotFound):
+			c.Status(http.StatusNotFound)
+			return
+		case errors.Is(err, services.ErrPollinationNotSet), errors.Is(err, services.ErrSeedAlreadySaved):
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error saving pollination seed: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantPollinations(c, plantID)
+}
+
+func (h *PollinationHandler) HandleDeletePollination(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	pollinationID, err := strconv.Atoi(c.Param("pollinationId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.pollinationService.DeletePollination(plantID, pollinationID); err != nil {
+		log.Printf("Error deleting pollination: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantPollinations(c, plantID)
+}
+
+func (h *PollinationHandler) renderPlantPollinations(c *gin.Context, plantID int) {
+	pollinations, err := h.pollinationService.GetPlantPollinations(plantID)
+	if err != nil {
+		log.Printf("Error fetching pollinations: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	donors, err := h.plantService.GetPlantOptions()
+	if err != nil {
+		log.Printf("Error fetching donor options: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PlantPollinations(plantID, pollinations, donors)).ServeHTTP(c.Writer, c.Request)
+}
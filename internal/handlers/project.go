@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ProjectHandler struct {
+	projectService   *services.ProjectService
+	plantService     *services.PlantService
+	traitService     *services.TraitService
+	selectionService *services.SelectionService
+}
+
+func NewProjectHandler(projectService *services.ProjectService, plantService *services.PlantService, traitService *services.TraitService, selectionService *services.SelectionService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService:   projectService,
+		plantService:     plantService,
+		traitService:     traitService,
+		selectionService: selectionService,
+	}
+}
+
+func (h *ProjectHandler) HandleProjectList(c *gin.Context) {
+	projects, err := h.projectService.GetProjects()
+	if err != nil {
+		log.Printf("Error fetching projects: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.ProjectList(projects)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *ProjectHandler) HandleCreateProject(c *gin.Context) {
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" {
+		c.String(http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	startedAt := time.Now()
+	if raw := c.PostForm("started_at"); raw != "" {
+		var err error
+		if startedAt, err = time.Parse("2006-01-02", raw); err != nil {
+			c.String(http.StatusBadRequest, "Invalid start date")
+			return
+		}
+	}
+
+	project := &types.BreedingProject{
+		Name:      name,
+		Goal:      nullString(c.PostForm("goal")),
+		Status:    types.ProjectActive,
+		StartedAt: startedAt,
+	}
+
+	if err := h.projectService.CreateProject(project); err != nil {
+		log.Printf("Error creating project: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", fmt.Sprintf("/projects/%d", project.ID))
+	c.Status(http.StatusCreated)
+}
+
+func (h *ProjectHandler) HandleProjectDashboard(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	dashboard, plants, traits, err := h.loadDashboard(projectID)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			c.Redirect(http.StatusFound, "/projects")
+			return
+		}
+		log.Printf("Error loading project dashboard: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.ProjectDashboardPage(*dashboard, plants, traits)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *ProjectHandler) HandleUpdateProject(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	status, err := types.ParseProjectStatus(c.PostForm("status"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" {
+		c.String(http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	project := &types.BreedingProject{
+		ID:     projectID,
+		Name:   name,
+		Goal:   nullString(c.PostForm("goal")),
+		Status: status,
+	}
+	if err := h.projectService.UpdateProject(project); err != nil {
+		log.Printf("Error updating project: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderDashboard(c, projectID)
+}
+
+func (h *ProjectHandler) HandleDeleteProject(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.DeleteProject(projectID); err != nil {
+		log.Printf("Error deleting project: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/projects")
+	c.Status(http.StatusOK)
+}
+
+func (h *ProjectHandler) HandleAddPlant(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	plantID, err := strconv.Atoi(c.PostForm("plant_id"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid plant")
+		return
+	}
+
+	if err := h.projectService.AddPlant(projectID, plantID); err != nil {
+		log.Printf("Error adding plant to project: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderDashboard(c, projectID)
+}
+
+func (h *ProjectHandler) HandleAddDescendants(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.projectService.AddDescendants(projectID); err != nil {
+		log.Printf("Error adding descendants to project: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderDashboard(c, projectID)
+}
+
+func (h *ProjectHandler) HandleRemovePlant(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	plantID, err := strconv.Atoi(c.Param("plantId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.RemovePlant(projectID, plantID); err != nil {
+		log.Printf("Error removing plant from project: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderDashboard(c, projectID)
+}
+
+// HandleSetSelection records a keep/cull decision from the dashboard. The
+// reason comes from an hx-prompt and may be left blank.
+func (h *ProjectHandler) HandleSetSelection(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	plantID, err := strconv.Atoi(c.Param("plantId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	status, err := types.ParseSelectionStatus(c.PostForm("selection_status"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	decision := &types.SelectionDecision{
+		PlantID:   plantID,
+		Status:    status,
+		Reason:    nullString(c.GetHeader("HX-Prompt")),
+		DecidedAt: time.Now(),
+	}
+	if err := h.selectionService.RecordDecision(decision); err != nil {
+		log.Printf("Error recording selection decision: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderDashboard(c, projectID)
+}
+
+// HandleSetTargetTrait reads the target from a single select. Categorical
+// options carry "traitID:state", numeric options only the trait ID with the
+// range taken from min_value and max_value.
+func (h *ProjectHandler) HandleSetTargetTrait(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	target := &types.ProjectTargetTrait{
+		ProjectID: projectID,
+		Notes:     nullString(c.PostForm("notes")),
+	}
+
+	traitPart, statePart, categorical := strings.Cut(c.PostForm("target"), ":")
+	if target.TraitID, err = strconv.Atoi(traitPart); err != nil {
+		c.String(http.StatusBadRequest, "Invalid trait")
+		return
+	}
+	if categorical {
+		state, err := strconv.ParseInt(statePart, 10, 64)
+		if err != nil {
+			c.String(http.StatusBadRequest, "Invalid trait state")
+			return
+		}
+		target.StateValue = sql.NullInt64{Int64: state, Valid: true}
+	} else {
+		if target.MinValue, err = parseNullFloat(c.PostForm("min_value")); err != nil {
+			c.String(http.StatusBadRequest, "Invalid minimum value")
+			return
+		}
+		if target.MaxValue, err = parseNullFloat(c.PostForm("max_value")); err != nil {
+			c.String(http.StatusBadRequest, "Invalid maximum value")
+			return
+		}
+	}
+
+	if err := h.projectService.SetTargetTrait(target); err != nil {
+		log.Printf("Error setting target trait: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderDashboard(c, projectID)
+}
+
+func (h *ProjectHandler) HandleRemoveTargetTrait(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	traitID, err := strconv.Atoi(c.Param("traitId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.RemoveTargetTrait(projectID, traitID); err != nil {
+		log.Printf("Error removing target trait: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderDashboard(c, projectID)
+}
+
+func (h *ProjectHandler) loadDashboard(projectID int) (*types.ProjectDashboard, []types.PlantOption, []types.TraitDefinition, error) {
+	dashboard, err := h.projectService.GetDashboard(projectID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	plants, err := h.plantService.GetPlantOptions()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	traits, err := h.traitService.GetTraitDefinitions()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return dashboard, plants, traits, nil
+}
+
+func (h *ProjectHandler) renderDashboard(c *gin.Context, projectID int) {
+	dashboard, plants, traits, err := h.loadDashboard(projectID)
+	if err != nil {
+		log.Printf("Error loading project dashboard: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.ProjectDashboard(*dashboard, plants, traits)).ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SeedLotHandler struct {
+	seedLotService *services.SeedLotService
+	plantService   *services.PlantService
+}
+
+func NewSeedLotHandler(seedLotService *services.SeedLotService, plantService *services.PlantService) *SeedLotHandler {
+	return &SeedLotHandler{
+		seedLotService: seedLotService,
+		plantService:   plantService,
+	}
+}
+
+func (h *SeedLotHandler) HandleSeedInventory(c *gin.Context) {
+	groups, err := h.seedLotService.GetSeedLotsByVariety()
+	if err != nil {
+		log.Printf("Error fetching seed inventory: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.SeedInventory(groups)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *SeedLotHandler) HandlePlantSeedLots(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderPlantSeedLots(c, plantID)
+}
+
+func (h *SeedLotHandler) HandleCreateSeedLot(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	harvestDate, err := time.Parse("2006-01-02", c.PostForm("harvest_date"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid harvest date")
+		return
+	}
+
+	seedCount, err := strconv.Atoi(c.PostForm("seed_count"))
+	if err != nil || seedCount < 0 {
+		c.String(http.StatusBadRequest, "Invalid seed count")
+		return
+	}
+
+	lot := &types.SeedLot{
+		PlantID:          plantID,
+		SeedCount:        seedCount,
+		HarvestDate:      harvestDate,
+		StorageLocation:  nullString(c.PostForm("storage_location")),
+		GerminationNotes: nullString(c.PostForm("germination_notes")),
+	}
+	lot.PollenParentID, lot.PollenParentExt = parseParentForm(c, "pollen_parent")
+
+	if err := h.seedLotService.CreateSeedLot(lot); err != nil {
+		log.Printf("Error creating seed lot: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantSeedLots(c, plantID)
+}
+
+// HandleAdjustSeedCount corrects a lot's seed count from an hx-prompt value.
+func (h *SeedLotHandler) HandleAdjustSeedCount(c *gin.Context) {
+	lotID, err := strconv.Atoi(c.Param("lotId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	seedCount, err := strconv.Atoi(strings.TrimSpace(c.GetHeader("HX-Prompt")))
+	if err != nil || seedCount < 0 {
+		c.String(http.StatusBadRequest, "Invalid seed count")
+		return
+	}
+
+	lot, err := h.seedLotService.GetSeedLot(lotID)
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	lot.SeedCount = seedCount
+	if err := h.seedLotService.UpdateSeedLot(lot); err != nil {
+		log.Printf("Error updating seed lot: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.SeedLotRow(*lot)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *SeedLotHandler) HandleDeleteSeedLot(c *gin.Context) {
+	lotID, err := strconv.Atoi(c.Param("lotId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.seedLotService.DeleteSeedLot(lotID); err != nil {
+		if errors.Is(err, services.ErrSeedLotNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting seed lot: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *SeedLotHandler) renderPlantSeedLots(c *gin.Context, plantID int) {
+	lots, err := h.seedLotService.GetPlantSeedLots(plantID)
+	if err != nil {
+		log.Printf("Error fetching seed lots: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	parents, err := h.plantService.GetPlantOptions()
+	if err != nil {
+		log.Printf("Error fetching parent options: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PlantSeedLots(plantID, lots, parents)).ServeHTTP(c.Writer, c.Request)
+}
+
+func nullString(s string) sql.NullString {
+	s = strings.TrimSpace(s)
+	return sql.NullString{String: s, Valid: s != ""}
+}
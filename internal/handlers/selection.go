@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"time"
+)
+
+type SelectionHandler struct {
+	selectionService *services.SelectionService
+	plantService     *services.PlantService
+	traitService     *services.TraitService
+}
+
+func NewSelectionHandler(selectionService *services.SelectionService, plantService *services.PlantService, traitService *services.TraitService) *SelectionHandler {
+	return &SelectionHandler{
+		selectionService: selectionService,
+		plantService:     plantService,
+		traitService:     traitService,
+	}
+}
+
+func (h *SelectionHandler) HandlePlantSelection(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderPlantSelection(c, plantID)
+}
+
+// HandleRecordSelection stores a decision from the journal page. The cited
+// trait scores arrive as repeated trait_score_id fields.
+func (h *SelectionHandler) HandleRecordSelection(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	status, err := types.ParseSelectionStatus(c.PostForm("status"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	decision := &types.SelectionDecision{
+		PlantID:   plantID,
+		Status:    status,
+		Reason:    nullString(c.PostForm("reason")),
+		DecidedAt: time.Now(),
+	}
+	for _, raw := range c.PostFormArray("trait_score_id") {
+		scoreID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.String(http.StatusBadRequest, "Invalid trait score")
+			return
+		}
+		decision.TraitScoreIDs = append(decision.TraitScoreIDs, scoreID)
+	}
+
+	if err := h.selectionService.RecordDecision(decision); err != nil {
+		log.Printf("Error recording selection decision: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantSelection(c, plantID)
+}
+
+// HandleCullPlant culls a plant from the plant grid. Unlike deleting, the
+// plant stays on record with its pedigree and scores so the family's
+// segregation counts remain complete.
+func (h *SelectionHandler) HandleCullPlant(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	decision := &types.SelectionDecision{
+		PlantID:   plantID,
+		Status:    types.SelectionCull,
+		Reason:    nullString(c.GetHeader("HX-Prompt")),
+		DecidedAt: time.Now(),
+	}
+	if err := h.selectionService.RecordDecision(decision); err != nil {
+		if errors.Is(err, services.ErrPlantNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error culling plant: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	// Get all plants with the current filters to maintain grid order
+	plants, err := h.plantService.GetPlantsWithFilters(plantFiltersFromQuery(c))
+	if err != nil {
+		log.Printf("Error fetching plants: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/html")
+	if err := pages.PlantsGrid(plants).Render(context.Background(), c.Writer); err != nil {
+		log.Printf("Error rendering grid: %v", err)
+		c.Status(http.StatusInternalServerError)
+	}
+}
+
+func (h *SelectionHandler) renderPlantSelection(c *gin.Context, plantID int) {
+	decisions, err := h.selectionService.GetPlantDecisions(plantID)
+	if err != nil {
+		log.Printf("Error fetching selection decisions: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	scores, err := h.traitService.GetPlantScores(plantID)
+	if err != nil {
+		log.Printf("Error fetching trait scores: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PlantSelection(plantID, decisions, scores)).ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+)
+
+type SpeciesHandler struct {
+	speciesService *services.SpeciesService
+}
+
+func NewSpeciesHandler(speciesService *services.SpeciesService) *SpeciesHandler {
+	return &SpeciesHandler{speciesService: speciesService}
+}
+
+func (h *SpeciesHandler) HandleSpeciesList(c *gin.Context) {
+	species, err := h.speciesService.GetSpecies()
+	if err != nil {
+		log.Printf("Error fetching species: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.SpeciesPage(species)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *SpeciesHandler) HandleCreateSpecies(c *gin.Context) {
+	taxon := &types.Taxon{
+		Name:  types.Species(c.PostForm("name")),
+		Notes: nullString(c.PostForm("notes")),
+	}
+	if types.NormalizeSpeciesName(string(taxon.Name)) == "" {
+		c.String(http.StatusBadRequest, "Name is required")
+		return
+	}
+	if _, _, ok := types.SplitHybrid(string(taxon.Name)); ok {
+		c.String(http.StatusBadRequest, "Add hybrids by picking their parents")
+		return
+	}
+
+	if err := h.speciesService.CreateSpecies(taxon); err != nil {
+		if errors.Is(err, services.ErrDuplicateSpecies) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error creating species: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/species")
+	c.Status(http.StatusCreated)
+}
+
+func (h *SpeciesHandler) HandleCreateHybrid(c *gin.Context) {
+	parentAID, err := strconv.Atoi(c.PostForm("parent_a_id"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid parent A")
+		return
+	}
+	parentBID, err := strconv.Atoi(c.PostForm("parent_b_id"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid parent B")
+		return
+	}
+
+	if _, err := h.speciesService.CreateHybrid(parentAID, parentBID, nullString(c.PostForm("notes"))); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidHybrid),
+			errors.Is(err, services.ErrDuplicateSpecies),
+			errors.Is(err, services.ErrSpeciesNotFound):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error creating hybrid: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/species")
+	c.Status(http.StatusCreated)
+}
+
+func (h *SpeciesHandler) HandleAddSynonym(c *gin.Context) {
+	speciesID, err := strconv.Atoi(c.Param("speciesId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.speciesService.AddSynonym(speciesID, c.PostForm("synonym")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrSpeciesNotFound):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, services.ErrDuplicateSpecies),
+			errors.Is(err, services.ErrUnknownSpecies):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error adding synonym: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/species")
+	c.Status(http.StatusCreated)
+}
+
+func (h *SpeciesHandler) HandleDeleteSpecies(c *gin.Context) {
+	speciesID, err := strconv.Atoi(c.Param("speciesId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.speciesService.DeleteSpecies(speciesID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrSpeciesNotFound):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, services.ErrSpeciesInUse):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error deleting species: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/species")
+	c.Status(http.StatusOK)
+}
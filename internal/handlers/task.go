@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TaskHandler struct {
+	taskService    *services.TaskService
+	plantService   *services.PlantService
+	projectService *services.ProjectService
+}
+
+func NewTaskHandler(taskService *services.TaskService, plantService *services.PlantService, projectService *services.ProjectService) *TaskHandler {
+	return &TaskHandler{
+		taskService:    taskService,
+		plantService:   plantService,
+		projectService: projectService,
+	}
+}
+
+func (h *TaskHandler) HandleTaskList(c *gin.Context) {
+	// The plant cards link here to show the tasks on one plant
+	plantID, _ := strconv.Atoi(c.Query("plant_id"))
+
+	tasks, err := h.taskService.GetOpenTasks(plantID)
+	if err != nil {
+		log.Printf("Error fetching tasks: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	completions, err := h.taskService.GetRecentCompletions(20)
+	if err != nil {
+		log.Printf("Error fetching task completions: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	plants, err := h.plantService.GetPlantOptions()
+	if err != nil {
+		log.Printf("Error fetching plants: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	projects, err := h.projectService.GetProjects()
+	if err != nil {
+		log.Printf("Error fetching projects: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.TaskList(tasks, completions, plants, projects, plantID)).ServeHTTP(c.Writer, c.Request)
+}
+
+// parseRepeat turns the repeat picker into a rule. "custom" takes the rule
+// typed in the RRULE field.
+func parseRepeat(c *gin.Context) (sql.NullString, error) {
+	rule := c.PostForm("repeat")
+	if rule == "custom" {
+		rule = c.PostForm("rrule")
+	}
+	if strings.TrimSpace(rule) == "" {
+		return sql.NullString{}, nil
+	}
+	recurrence, err := types.ParseRecurrence(rule)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: recurrence.String(), Valid: true}, nil
+}
+
+func (h *TaskHandler) HandleCreateTask(c *gin.Context) {
+	title := strings.TrimSpace(c.PostForm("title"))
+	if title == "" {
+		c.String(http.StatusBadRequest, "Title is required")
+		return
+	}
+
+	dueDate, err := time.Parse("2006-01-02", c.PostForm("due_date"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid due date")
+		return
+	}
+
+	task := &types.Task{
+		Title:       title,
+		Description: nullString(c.PostForm("description")),
+		EntryType:   c.PostForm("entry_type"),
+		DueDate:     dueDate,
+	}
+	if task.RRule, err = parseRepeat(c); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if task.ProjectID, err = parseNullInt(c.PostForm("project_id")); err != nil {
+		c.String(http.StatusBadRequest, "Invalid project")
+		return
+	}
+
+	var plantIDs []int
+	for _, raw := range c.PostFormArray("plant_ids") {
+		plantID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.String(http.StatusBadRequest, "Invalid plant")
+			return
+		}
+		plantIDs = append(plantIDs, plantID)
+	}
+
+	if err := h.taskService.CreateTask(task, plantIDs); err != nil {
+		switch {
+		case errors.Is(err, services.ErrTaskHasNoPlants):
+			c.String(http.StatusBadRequest, "Pick at least one plant or a project")
+		case errors.Is(err, services.ErrInvalidEntryType):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error creating task: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/tasks")
+	c.Status(http.StatusCreated)
+}
+
+func (h *TaskHandler) HandleCompleteTask(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	completedOn := time.Now()
+	if raw := c.PostForm("completed_on"); raw != "" {
+		if completedOn, err = time.Parse("2006-01-02", raw); err != nil {
+			c.String(http.StatusBadRequest, "Invalid date")
+			return
+		}
+	}
+
+	occurrences, err := strconv.Atoi(c.PostForm("occurrences"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid occurrence")
+		return
+	}
+
+	if err := h.taskService.CompleteTask(taskID, occurrences, completedOn, c.PostForm("note")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrTaskNotFound):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, services.ErrTaskDone),
+			errors.Is(err, services.ErrTaskHasNoPlants):
+			c.String(http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error completing task: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/tasks")
+	c.Status(http.StatusOK)
+}
+
+func (h *TaskHandler) HandleDeleteTask(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.taskService.DeleteTask(taskID); err != nil {
+		if errors.Is(err, services.ErrTaskNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting task: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/tasks")
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type TraitHandler struct {
+	traitService *services.TraitService
+}
+
+func NewTraitHandler(traitService *services.TraitService) *TraitHandler {
+	return &TraitHandler{
+		traitService: traitService,
+	}
+}
+
+func (h *TraitHandler) HandleTraitDefinitions(c *gin.Context) {
+	traits, err := h.traitService.GetTraitDefinitions()
+	if err != nil {
+		log.Printf("Error fetching trait definitions: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.TraitDefinitions(traits)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *TraitHandler) HandleCreateTraitDefinition(c *gin.Context) {
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" {
+		c.String(http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	group, err := types.ParseTraitGroup(c.PostForm("trait_group"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scale, err := types.ParseTraitScale(c.PostForm("scale_type"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	trait := &types.TraitDefinition{
+		Code:        traitCode(name),
+		Name:        name,
+		TraitGroup:  group,
+		ScaleType:   scale,
+		Description: nullString(c.PostForm("description")),
+	}
+
+	switch scale {
+	case types.TraitScaleCategorical:
+		trait.States, err = parseTraitStates(c.PostForm("states"))
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+	case types.TraitScaleNumeric:
+		trait.Unit = nullString(c.PostForm("unit"))
+		if trait.MinValue, err = parseNullFloat(c.PostForm("min_value")); err != nil {
+			c.String(http.StatusBadRequest, "Invalid minimum value")
+			return
+		}
+		if trait.MaxValue, err = parseNullFloat(c.PostForm("max_value")); err != nil {
+			c.String(http.StatusBadRequest, "Invalid maximum value")
+			return
+		}
+	}
+
+	if err := h.traitService.CreateTraitDefinition(trait); err != nil {
+		if errors.Is(err, services.ErrInvalidTraitValue) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error creating trait definition: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderTraitList(c)
+}
+
+func (h *TraitHandler) HandleDeleteTraitDefinition(c *gin.Context) {
+	traitID, err := strconv.Atoi(c.Param("traitId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.traitService.DeleteTraitDefinition(traitID); err != nil {
+		log.Printf("Error deleting trait definition: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderTraitList(c)
+}
+
+func (h *TraitHandler) HandlePlantTraits(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.renderPlantTraits(c, plantID)
+}
+
+// HandleScorePlant stores the scoring form. Each trait has a trait_<id> field;
+// blank fields are left unscored.
+func (h *TraitHandler) HandleScorePlant(c *gin.Context) {
+	plantID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	observedDate, err := time.Parse("2006-01-02", c.PostForm("observed_date"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid observation date")
+		return
+	}
+
+	traits, err := h.traitService.GetTraitDefinitions()
+	if err != nil {
+		log.Printf("Error fetching trait definitions: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	var scores []types.TraitScore
+	for _, trait := range traits {
+		raw := strings.TrimSpace(c.PostForm(fmt.Sprintf("trait_%d", trait.ID)))
+		if raw == "" {
+			continue
+		}
+
+		score := types.TraitScore{TraitID: trait.ID, ObservedDate: observedDate}
+		if trait.ScaleType == types.TraitScaleCategorical {
+			value, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				c.String(http.StatusBadRequest, fmt.Sprintf("Invalid value for %s", trait.Name))
+				return
+			}
+			score.StateValue = sql.NullInt64{Int64: value, Valid: true}
+		} else {
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				c.String(http.StatusBadRequest, fmt.Sprintf("Invalid value for %s", trait.Name))
+				return
+			}
+			score.NumericValue = sql.NullFloat64{Float64: value, Valid: true}
+		}
+		scores = append(scores, score)
+	}
+
+	if err := h.traitService.ScorePlant(plantID, scores); err != nil {
+		if errors.Is(err, services.ErrInvalidTraitValue) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error scoring plant: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPlantTraits(c, plantID)
+}
+
+func (h *TraitHandler) renderTraitList(c *gin.Context) {
+	traits, err := h.traitService.GetTraitDefinitions()
+	if err != nil {
+		log.Printf("Error fetching trait definitions: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.TraitDefinitionList(traits)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *TraitHandler) renderPlantTraits(c *gin.Context, plantID int) {
+	traits, err := h.traitService.GetTraitDefinitions()
+	if err != nil {
+		log.Printf("Error fetching trait definitions: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	scores, err := h.traitService.GetPlantScores(plantID)
+	if err != nil {
+		log.Printf("Error fetching trait scores: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.PlantTraits(plantID, traits, scores)).ServeHTTP(c.Writer, c.Request)
+}
+
+// parseTraitStates reads one "value = label" pair per line, e.g. "8 = Red".
+func parseTraitStates(text string) ([]types.TraitState, error) {
+	var states []types.TraitState
+	seen := make(map[int]bool)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		value, label, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid state %q, expected value = label", line)
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid state value %q", strings.TrimSpace(value))
+		}
+		if seen[v] {
+			return nil, fmt.Errorf("duplicate state value %d", v)
+		}
+		seen[v] = true
+		states = append(states, types.TraitState{Value: v, Label: strings.TrimSpace(label)})
+	}
+	return states, nil
+}
+
+// traitCode derives a stable code from a trait name, e.g. "Fruit colour" ->
+// "fruit_colour".
+func traitCode(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+func parseNullFloat(s string) (sql.NullFloat64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return sql.NullFloat64{}, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return sql.NullFloat64{}, err
+	}
+	return sql.NullFloat64{Float64: f, Valid: true}, nil
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/a-h/templ"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/templates/pages"
+	"strconv"
+	"strings"
+)
+
+type VarietyHandler struct {
+	varietyService *services.VarietyService
+	speciesService *services.SpeciesService
+	plantService   *services.PlantService
+	harvestService *services.HarvestService
+}
+
+func NewVarietyHandler(varietyService *services.VarietyService, speciesService *services.SpeciesService, plantService *services.PlantService, harvestService *services.HarvestService) *VarietyHandler {
+	return &VarietyHandler{
+		varietyService: varietyService,
+		speciesService: speciesService,
+		plantService:   plantService,
+		harvestService: harvestService,
+	}
+}
+
+func (h *VarietyHandler) HandleVarietyList(c *gin.Context) {
+	varieties, err := h.varietyService.GetVarieties()
+	if err != nil {
+		log.Printf("Error fetching varieties: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	species, err := h.speciesService.GetSpecies()
+	if err != nil {
+		log.Printf("Error fetching species: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.VarietyList(varieties, species)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *VarietyHandler) HandleCreateVariety(c *gin.Context) {
+	variety, err := h.varietyFromForm(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.varietyService.CreateVariety(variety); err != nil {
+		if errors.Is(err, services.ErrDuplicateVariety) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error creating variety: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", fmt.Sprintf("/varieties/%d", variety.ID))
+	c.Status(http.StatusCreated)
+}
+
+func (h *VarietyHandler) HandleVariety(c *gin.Context) {
+	varietyID, err := strconv.Atoi(c.Param("varietyId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	variety, err := h.varietyService.GetVariety(varietyID)
+	if err != nil {
+		if errors.Is(err, services.ErrVarietyNotFound) {
+			c.Redirect(http.StatusFound, "/varieties")
+			return
+		}
+		log.Printf("Error fetching variety: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	plants, err := h.varietyService.GetVarietyPlants(varietyID)
+	if err != nil {
+		log.Printf("Error fetching variety plants: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	yield, err := h.harvestService.GetVarietyYield(varietyID)
+	if err != nil {
+		log.Printf("Error fetching variety yield: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	durations, err := h.plantService.GetVarietyStageDurations(varietyID)
+	if err != nil {
+		log.Printf("Error fetching variety stage durations: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	species, err := h.speciesService.GetSpecies()
+	if err != nil {
+		log.Printf("Error fetching species: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	templ.Handler(pages.VarietyPage(*variety, plants, *yield, *durations, species)).ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *VarietyHandler) HandleUpdateVariety(c *gin.Context) {
+	varietyID, err := strconv.Atoi(c.Param("varietyId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	variety, err := h.varietyFromForm(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	variety.ID = varietyID
+
+	if err := h.varietyService.UpdateVariety(variety); err != nil {
+		switch {
+		case errors.Is(err, services.ErrDuplicateVariety):
+			c.String(http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrVarietyNotFound):
+			c.Status(http.StatusNotFound)
+		default:
+			log.Printf("Error updating variety: %v", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", fmt.Sprintf("/varieties/%d", varietyID))
+	c.Status(http.StatusOK)
+}
+
+func (h *VarietyHandler) HandleDeleteVariety(c *gin.Context) {
+	varietyID, err := strconv.Atoi(c.Param("varietyId"))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.varietyService.DeleteVariety(varietyID); err != nil {
+		if errors.Is(err, services.ErrVarietyNotFound) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting variety: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Set("HX-Redirect", "/varieties")
+	c.Status(http.StatusOK)
+}
+
+// varietyFromForm reads the catalog fields shared by the create and edit forms.
+func (h *VarietyHandler) varietyFromForm(c *gin.Context) (*types.Variety, error) {
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" {
+		return nil, errors.New("Name is required")
+	}
+
+	variety := &types.Variety{
+		Name:           name,
+		Species:        nullString(c.PostForm("species")),
+		Origin:         nullString(c.PostForm("origin")),
+		PodDescription: nullString(c.PostForm("pod_description")),
+		SeedSource:     nullString(c.PostForm("seed_source")),
+	}
+
+	if variety.Species.Valid {
+		species, err := h.speciesService.Resolve(variety.Species.String)
+		if err != nil {
+			return nil, err
+		}
+		variety.Species.String = string(species)
+	}
+
+	var err error
+	if variety.TypicalSHU, err = parseNullInt(c.PostForm("typical_shu")); err != nil || variety.TypicalSHU.Int64 < 0 {
+		return nil, errors.New("Invalid typical SHU")
+	}
+	if variety.DaysToMaturity, err = parseNullInt(c.PostForm("days_to_maturity")); err != nil || (variety.DaysToMaturity.Valid && variety.DaysToMaturity.Int64 < 1) {
+		return nil, errors.New("Invalid days to maturity")
+	}
+	if variety.WateringInterval, err = parseCareInterval(c.PostForm("watering_interval_days")); err != nil {
+		return nil, errors.New("Invalid watering interval")
+	}
+	if variety.FertilizingInterval, err = parseCareInterval(c.PostForm("fertilizing_interval_days")); err != nil {
+		return nil, errors.New("Invalid fertilizing interval")
+	}
+	return variety, nil
+}
+
+func parseNullInt(s string) (sql.NullInt64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return sql.NullInt64{}, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+	return sql.NullInt64{Int64: n, Valid: true}, nil
+}
+
+// parseCareInterval reads a watering or fertilizing interval in days. Blank
+// falls back to the next default and 0 takes the plant off the schedule.
+func parseCareInterval(s string) (sql.NullInt64, error) {
+	days, err := parseNullInt(s)
+	if err != nil || days.Int64 < 0 {
+		return sql.NullInt64{}, errors.New("invalid care interval")
+	}
+	return days, nil
+}
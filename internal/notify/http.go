@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// do sends an HTTP request and treats any status outside 2xx as a failure,
+// quoting the start of the response so the delivery log shows the reason.
+func do(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
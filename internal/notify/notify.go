@@ -0,0 +1,126 @@
+// Package notify sends reminders and alerts out of the app: email over SMTP,
+// push notifications through ntfy or Gotify, and JSON webhooks that Slack and
+// Discord accept. Every driver takes the address of the server it talks to,
+// so a local SMTP sink or an httptest server can stand in for the real one.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"os"
+	"pepper-analytics-ai/internal/types"
+	"pepper-analytics-ai/internal/utils"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTarget      = errors.New("invalid channel target")
+	ErrSMTPNotConfigured  = errors.New("SMTP is not configured")
+	ErrUnknownChannelKind = errors.New("unknown channel kind")
+)
+
+// Message is one notification. HTML is optional and only used by email; the
+// other drivers send Body. URL links back to the page the message is about.
+type Message struct {
+	Event string
+	Title string
+	Body  string
+	HTML  string
+	URL   string
+}
+
+// Notifier delivers a message over one channel.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config holds the settings shared by all channels.
+type Config struct {
+	SMTP SMTPConfig
+	// BaseURL is where the app is reachable, used to build links in
+	// messages. Messages go out without links when it is empty.
+	BaseURL string
+}
+
+// NewDefaultConfig reads the notification settings from the environment.
+// All of them are optional; email channels need SMTP_HOST.
+func NewDefaultConfig() Config {
+	return Config{
+		SMTP: SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     utils.GetEnvAsInt("SMTP_PORT", 587),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		},
+		BaseURL: strings.TrimRight(os.Getenv("APP_URL"), "/"),
+	}
+}
+
+// Link turns an app path into an absolute URL, or "" without a base URL.
+func (c Config) Link(path string) string {
+	if c.BaseURL == "" {
+		return ""
+	}
+	return c.BaseURL + path
+}
+
+// New builds the notifier for a channel. target is the recipient address for
+// email, the topic URL for ntfy, the server URL for Gotify and the endpoint
+// for webhooks. token is sent as the channel's credential where it has one.
+// A nil client uses one with a 10 second timeout.
+func New(kind types.ChannelKind, target, token string, config Config, client *http.Client) (Notifier, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	target = strings.TrimSpace(target)
+
+	switch kind {
+	case types.ChannelSMTP:
+		if config.SMTP.Host == "" {
+			return nil, ErrSMTPNotConfigured
+		}
+		address, err := mail.ParseAddress(target)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+		}
+		return &SMTP{Config: config.SMTP, To: address.Address}, nil
+	case types.ChannelNtfy, types.ChannelGotify:
+		if err := checkURL(target); err != nil {
+			return nil, err
+		}
+		format := FormatNtfy
+		if kind == types.ChannelGotify {
+			format = FormatGotify
+		}
+		return &Push{URL: target, Token: token, Format: format, Client: client}, nil
+	case types.ChannelWebhook:
+		if err := checkURL(target); err != nil {
+			return nil, err
+		}
+		return &Webhook{URL: target, Token: token, Client: client}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownChannelKind, kind)
+	}
+}
+
+func checkURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q is not an http(s) URL", ErrInvalidTarget, target)
+	}
+	return nil
+}
+
+// textBody is the message as plain text, with its link on the last line.
+func textBody(msg Message) string {
+	if msg.URL == "" {
+		return msg.Body
+	}
+	return strings.TrimRight(msg.Body, "\n") + "\n\n" + msg.URL
+}
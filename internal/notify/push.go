@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// PushFormat is the API a push server speaks.
+type PushFormat string
+
+const (
+	FormatNtfy   PushFormat = "ntfy"
+	FormatGotify PushFormat = "gotify"
+)
+
+// Push sends a push notification through ntfy or Gotify. For ntfy, URL is
+// the topic URL and Token an optional access token. For Gotify, URL is the
+// server and Token the application token.
+type Push struct {
+	URL    string
+	Token  string
+	Format PushFormat
+	Client *http.Client
+}
+
+func (p *Push) Send(ctx context.Context, msg Message) error {
+	var req *http.Request
+	var err error
+	if p.Format == FormatGotify {
+		req, err = p.gotifyRequest(ctx, msg)
+	} else {
+		req, err = p.ntfyRequest(ctx, msg)
+	}
+	if err != nil {
+		return fmt.Errorf("error building push request: %w", err)
+	}
+	return do(p.Client, req)
+}
+
+// ntfyRequest publishes the body to the topic with the title and link in
+// headers. Headers must be ASCII, so the title is RFC 2047 encoded, which
+// ntfy decodes.
+func (p *Push) ntfyRequest(ctx context.Context, msg Message) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, strings.NewReader(msg.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Title", mime.QEncoding.Encode("utf-8", msg.Title))
+	if msg.URL != "" {
+		req.Header.Set("Click", msg.URL)
+	}
+	if msg.Event != "" {
+		req.Header.Set("Tags", msg.Event)
+	}
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+	return req, nil
+}
+
+func (p *Push) gotifyRequest(ctx context.Context, msg Message) (*http.Request, error) {
+	payload := map[string]interface{}{
+		"title":    msg.Title,
+		"message":  textBody(msg),
+		"priority": 5,
+	}
+	if msg.URL != "" {
+		payload["extras"] = map[string]interface{}{
+			"client::notification": map[string]interface{}{
+				"click": map[string]string{"url": msg.URL},
+			},
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(p.URL, "/")+"/message", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", p.Token)
+	return req, nil
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// recordedRequest is what a test server saw of the one request it got.
+type recordedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// newRecordingServer answers every request with status and body and keeps
+// the last request it saw.
+func newRecordingServer(t *testing.T, status int, body string) (*httptest.Server, *recordedRequest) {
+	t.Helper()
+	got := &recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.method = r.Method
+		got.path = r.URL.Path
+		got.header = r.Header.Clone()
+		got.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, got
+}
+
+func TestPushNtfy(t *testing.T) {
+	server, got := newRecordingServer(t, http.StatusOK, "{}")
+	push := &Push{URL: server.URL + "/garden", Token: "tk_secret", Format: FormatNtfy, Client: server.Client()}
+
+	err := push.Send(context.Background(), Message{
+		Event: "harvest_ready",
+		Title: "Ají ready",
+		Body:  "Aji Charapita has ripe pods.",
+		URL:   "https://garden.example.com/plants/4",
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if got.method != http.MethodPost || got.path != "/garden" {
+		t.Errorf("request = %s %s, want POST /garden", got.method, got.path)
+	}
+	if string(got.body) != "Aji Charapita has ripe pods." {
+		t.Errorf("body = %q", got.body)
+	}
+	title, err := new(mime.WordDecoder).DecodeHeader(got.header.Get("Title"))
+	if err != nil || title != "Ají ready" {
+		t.Errorf("Title = %q (%q), %v", title, got.header.Get("Title"), err)
+	}
+	headers := map[string]string{
+		"Authorization": "Bearer tk_secret",
+		"Click":         "https://garden.example.com/plants/4",
+		"Tags":          "harvest_ready",
+	}
+	for key, want := range headers {
+		if v := got.header.Get(key); v != want {
+			t.Errorf("%s = %q, want %q", key, v, want)
+		}
+	}
+}
+
+func TestPushNtfyWithoutToken(t *testing.T) {
+	server, got := newRecordingServer(t, http.StatusOK, "{}")
+	push := &Push{URL: server.URL + "/garden", Format: FormatNtfy, Client: server.Client()}
+
+	if err := push.Send(context.Background(), Message{Title: "t", Body: "b"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	for _, key := range []string{"Authorization", "Click", "Tags"} {
+		if v := got.header.Get(key); v != "" {
+			t.Errorf("%s = %q, want it unset", key, v)
+		}
+	}
+}
+
+func TestPushGotify(t *testing.T) {
+	server, got := newRecordingServer(t, http.StatusOK, "{}")
+	push := &Push{URL: server.URL + "/", Token: "app_token", Format: FormatGotify, Client: server.Client()}
+
+	err := push.Send(context.Background(), Message{
+		Title: "Overdue tasks",
+		Body:  "2 tasks are overdue.",
+		URL:   "https://garden.example.com/tasks",
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if got.method != http.MethodPost || got.path != "/message" {
+		t.Errorf("request = %s %s, want POST /message", got.method, got.path)
+	}
+	if key := got.header.Get("X-Gotify-Key"); key != "app_token" {
+		t.Errorf("X-Gotify-Key = %q", key)
+	}
+	if ct := got.header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	var payload struct {
+		Title    string `json:"title"`
+		Message  string `json:"message"`
+		Priority int    `json:"priority"`
+		Extras   struct {
+			Notification struct {
+				Click struct {
+					URL string `json:"url"`
+				} `json:"click"`
+			} `json:"client::notification"`
+		} `json:"extras"`
+	}
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatalf("payload %s: %v", got.body, err)
+	}
+	if payload.Title != "Overdue tasks" || payload.Priority != 5 {
+		t.Errorf("payload = %+v", payload)
+	}
+	if payload.Message != "2 tasks are overdue.\n\nhttps://garden.example.com/tasks" {
+		t.Errorf("message = %q", payload.Message)
+	}
+	if payload.Extras.Notification.Click.URL != "https://garden.example.com/tasks" {
+		t.Errorf("click url = %q", payload.Extras.Notification.Click.URL)
+	}
+}
+
+func TestPushErrorStatus(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusUnauthorized, `{"error":"unauthorized"}`)
+
+	for _, format := range []PushFormat{FormatNtfy, FormatGotify} {
+		push := &Push{URL: server.URL, Token: "wrong", Format: format, Client: server.Client()}
+		err := push.Send(context.Background(), Message{Title: "t", Body: "b"})
+		if err == nil {
+			t.Errorf("%s: Send succeeded on a 401", format)
+			continue
+		}
+		if !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "unauthorized") {
+			t.Errorf("%s: error %q does not quote the response", format, err)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSink is a minimal SMTP server that accepts one message and hands its
+// envelope and data back on a channel.
+type smtpSink struct {
+	listener net.Listener
+	messages chan sinkMessage
+}
+
+type sinkMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &smtpSink{listener: listener, messages: make(chan sinkMessage, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go sink.serve()
+	return sink
+}
+
+func (s *smtpSink) config() SMTPConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return SMTPConfig{Host: addr.IP.String(), Port: addr.Port, From: "garden@example.com"}
+}
+
+func (s *smtpSink) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 sink ready")
+
+	var msg sinkMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250-sink")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			msg.from = envelopeAddress(line[len("MAIL FROM:"):])
+			reply("250 ok")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			msg.to = append(msg.to, envelopeAddress(line[len("RCPT TO:"):]))
+			reply("250 ok")
+		case command == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			msg.data = data.String()
+			s.messages <- msg
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// envelopeAddress takes the address out of "<a@b> BODY=8BITMIME".
+func envelopeAddress(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if end := strings.Index(arg, ">"); end >= 0 {
+		arg = arg[:end]
+	}
+	return strings.TrimPrefix(arg, "<")
+}
+
+func (s *smtpSink) receive(t *testing.T) sinkMessage {
+	t.Helper()
+	select {
+	case msg := <-s.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+		return sinkMessage{}
+	}
+}
+
+func TestSMTPSendPlain(t *testing.T) {
+	sink := newSMTPSink(t)
+	smtp := &SMTP{Config: sink.config(), To: "grower@example.com"}
+
+	err := smtp.Send(context.Background(), Message{
+		Title: "Chili ready — pick now",
+		Body:  "Habanero #2 has ripe pods.",
+		URL:   "https://garden.example.com/plants/2",
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	got := sink.receive(t)
+	if got.from != "garden@example.com" || len(got.to) != 1 || got.to[0] != "grower@example.com" {
+		t.Errorf("envelope = %s -> %v", got.from, got.to)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatalf("ReadMessage error: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Chili ready — pick now" {
+		t.Errorf("Subject = %q, %v", subject, err)
+	}
+	if ct := msg.Header.Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if cte := msg.Header.Get("Content-Transfer-Encoding"); cte != "quoted-printable" {
+		t.Errorf("Content-Transfer-Encoding = %q", cte)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Habanero #2 has ripe pods.\n\nhttps://garden.example.com/plants/2"
+	if strings.TrimRight(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n") != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
+
+func TestSMTPSendMultipart(t *testing.T) {
+	sink := newSMTPSink(t)
+	smtp := &SMTP{Config: sink.config(), To: "grower@example.com"}
+
+	html := `<p style="color: #b22222">Weekly digest: ` + strings.Repeat("pods ", 30) + `</p>`
+	err := smtp.Send(context.Background(), Message{
+		Title: "Weekly digest",
+		Body:  "3 harvests, 42 pods",
+		HTML:  html,
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	data := sink.receive(t).data
+	if !strings.Contains(data, `style=3D"color: #b22222"`) {
+		t.Error("HTML part is not quoted-printable encoded")
+	}
+	_, body, _ := strings.Cut(data, "\r\n\r\n")
+	for _, line := range strings.Split(body, "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("line longer than 76 characters: %q", line)
+		}
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadMessage error: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v", msg.Header.Get("Content-Type"), err)
+	}
+
+	// The multipart reader undoes quoted-printable on its own
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	want := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", "3 harvests, 42 pods"},
+		{"text/html; charset=utf-8", html},
+	}
+	for _, w := range want {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart error: %v", err)
+		}
+		if ct := part.Header.Get("Content-Type"); ct != w.contentType {
+			t.Errorf("part Content-Type = %q, want %q", ct, w.contentType)
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimRight(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n"); got != w.body {
+			t.Errorf("part body = %q, want %q", got, w.body)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expected two parts, got more: %v", err)
+	}
+}
+
+func TestSMTPSendConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	smtp := &SMTP{Config: SMTPConfig{Host: "127.0.0.1", Port: port}, To: "grower@example.com"}
+	if err := smtp.Send(context.Background(), Message{Title: "t", Body: "b"}); err == nil {
+		t.Error("Send succeeded with no server on port " + strconv.Itoa(port))
+	}
+}
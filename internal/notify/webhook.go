@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// discordContentLimit is the most characters Discord takes in a message.
+const discordContentLimit = 2000
+
+// Webhook posts the message as JSON. The payload carries "text" for Slack
+// and "content" for Discord next to the message's own fields, so the same
+// channel works for either or for a receiver of your own.
+type Webhook struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+type webhookPayload struct {
+	Text    string `json:"text"`
+	Content string `json:"content"`
+	Event   string `json:"event,omitempty"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	URL     string `json:"url,omitempty"`
+}
+
+func (w *Webhook) Send(ctx context.Context, msg Message) error {
+	text := msg.Title + "\n" + textBody(msg)
+	content := []rune(text)
+	if len(content) > discordContentLimit {
+		content = append(content[:discordContentLimit-1], '…')
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Text:    text,
+		Content: string(content),
+		Event:   msg.Event,
+		Title:   msg.Title,
+		Body:    msg.Body,
+		URL:     msg.URL,
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.Token)
+	}
+	return do(w.Client, req)
+}
@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWebhookSend(t *testing.T) {
+	server, got := newRecordingServer(t, http.StatusNoContent, "")
+	webhook := &Webhook{URL: server.URL + "/hooks/garden", Token: "hook_secret", Client: server.Client()}
+
+	err := webhook.Send(context.Background(), Message{
+		Event: "health_decline",
+		Title: "Plant declining",
+		Body:  "Scotch Bonnet #1 went from Good to Poor.",
+		URL:   "https://garden.example.com/plants/1",
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if got.method != http.MethodPost || got.path != "/hooks/garden" {
+		t.Errorf("request = %s %s, want POST /hooks/garden", got.method, got.path)
+	}
+	if auth := got.header.Get("Authorization"); auth != "Bearer hook_secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if ct := got.header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatalf("payload %s: %v", got.body, err)
+	}
+	text := "Plant declining\nScotch Bonnet #1 went from Good to Poor.\n\nhttps://garden.example.com/plants/1"
+	want := webhookPayload{
+		Text:    text,
+		Content: text,
+		Event:   "health_decline",
+		Title:   "Plant declining",
+		Body:    "Scotch Bonnet #1 went from Good to Poor.",
+		URL:     "https://garden.example.com/plants/1",
+	}
+	if payload != want {
+		t.Errorf("payload = %+v, want %+v", payload, want)
+	}
+}
+
+func TestWebhookWithoutToken(t *testing.T) {
+	server, got := newRecordingServer(t, http.StatusOK, "ok")
+	webhook := &Webhook{URL: server.URL, Client: server.Client()}
+
+	if err := webhook.Send(context.Background(), Message{Title: "t", Body: "b"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if auth := got.header.Get("Authorization"); auth != "" {
+		t.Errorf("Authorization = %q, want it unset", auth)
+	}
+}
+
+func TestWebhookTruncatesDiscordContent(t *testing.T) {
+	server, got := newRecordingServer(t, http.StatusOK, "")
+	webhook := &Webhook{URL: server.URL, Client: server.Client()}
+
+	body := strings.Repeat("🌶", discordContentLimit)
+	if err := webhook.Send(context.Background(), Message{Title: "Digest", Body: body}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if n := utf8.RuneCountInString(payload.Content); n != discordContentLimit {
+		t.Errorf("content is %d characters, want %d", n, discordContentLimit)
+	}
+	if !strings.HasSuffix(payload.Content, "…") {
+		t.Error("truncated content does not end in an ellipsis")
+	}
+	if payload.Text != "Digest\n"+body {
+		t.Error("text was truncated too")
+	}
+}
+
+func TestWebhookErrorStatus(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusForbidden, "nope\n")
+	webhook := &Webhook{URL: server.URL, Client: server.Client()}
+
+	err := webhook.Send(context.Background(), Message{Title: "t", Body: "b"})
+	if err == nil {
+		t.Fatal("Send succeeded on a 403")
+	}
+	if err.Error() != "403 Forbidden: nope" {
+		t.Errorf("error = %q, want %q", err, "403 Forbidden: nope")
+	}
+}
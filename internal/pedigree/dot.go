@@ -0,0 +1,87 @@
+package pedigree
+
+import (
+	"fmt"
+	"pepper-analytics-ai/internal/types"
+	"strings"
+)
+
+func dotEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+func (n Node) lines() []string {
+	lines := []string{n.Name}
+	var details []string
+	if n.Generation != "" {
+		details = append(details, n.Generation)
+	}
+	if n.Species != "" {
+		details = append(details, n.Species)
+	}
+	if len(details) > 0 {
+		lines = append(lines, strings.Join(details, " · "))
+	}
+	if n.External {
+		lines = append(lines, "external")
+	} else if n.Removed {
+		lines = append(lines, "removed")
+	}
+	return lines
+}
+
+// DOT writes the graph in Graphviz DOT, ranked top to bottom from the oldest
+// ancestors down.
+func (g Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph pedigree {\n")
+	b.WriteString("    rankdir=TB;\n")
+	b.WriteString("    node [shape=box, style=\"rounded,filled\", fillcolor=white, fontname=\"Helvetica\"];\n")
+	b.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	for _, n := range g.Nodes {
+		lines := n.lines()
+		for i, line := range lines {
+			lines[i] = dotEscape(line)
+		}
+		attrs := []string{`label="` + strings.Join(lines, `\n`) + `"`}
+		switch {
+		case n.Root:
+			attrs = append(attrs, "fillcolor=\"#fde2c8\"", "penwidth=2")
+		case n.External:
+			attrs = append(attrs, "style=\"rounded,dashed\"")
+		case n.Removed:
+			attrs = append(attrs, "fontcolor=gray40", "color=gray60")
+		}
+		fmt.Fprintf(&b, "    %s [%s];\n", dotQuote(n.Key), strings.Join(attrs, ", "))
+	}
+
+	for _, e := range g.Edges {
+		style := "solid"
+		if e.Role == types.ParentRolePollen {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "    %s -> %s [label=%s, style=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(string(e.Role)), style)
+	}
+
+	// Keep each generation level on one rank
+	levels := make(map[int][]string)
+	var order []int
+	for _, n := range g.Nodes {
+		if _, ok := levels[n.Level]; !ok {
+			order = append(order, n.Level)
+		}
+		levels[n.Level] = append(levels[n.Level], dotQuote(n.Key))
+	}
+	for _, level := range order {
+		fmt.Fprintf(&b, "    { rank=same; %s; }\n", strings.Join(levels[level], "; "))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,179 @@
+// Package pedigree turns a plant's recorded lineage into a family tree graph
+// and renders it as Graphviz DOT or as a self-contained SVG.
+package pedigree
+
+import (
+	"fmt"
+	"pepper-analytics-ai/internal/types"
+	"sort"
+	"strings"
+)
+
+// Node is a plant in the family tree. External parents, recorded only by
+// name, get a node of their own keyed by that name.
+type Node struct {
+	Key        string
+	PlantID    int
+	Name       string
+	Generation string
+	Species    string
+	External   bool
+	Removed    bool
+	Root       bool
+	Level      int
+}
+
+// Edge points from a parent to its offspring.
+type Edge struct {
+	From string
+	To   string
+	Role types.ParentRole
+}
+
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+func plantKey(id int) string {
+	return fmt.Sprintf("p%d", id)
+}
+
+func externalKey(name string) string {
+	return "x:" + strings.ToLower(strings.TrimSpace(name))
+}
+
+// Build assembles the graph around root. Ancestors are placed on the levels
+// above the root and descendants below it. coParents are the other parents
+// of descendants that are not part of the lineage themselves, such as the
+// plant the root was crossed with.
+func Build(root types.PedigreeNode, lineage types.Lineage, coParents []types.PedigreeNode) Graph {
+	var g Graph
+	index := make(map[string]int)
+
+	addPlant := func(p types.PedigreeNode, level int) {
+		key := plantKey(p.ID)
+		if _, ok := index[key]; ok {
+			return
+		}
+		index[key] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, Node{
+			Key:        key,
+			PlantID:    p.ID,
+			Name:       p.Name,
+			Generation: p.Generation.String,
+			Species:    types.ShortSpecies(p.Species),
+			Removed:    p.DeletedAt != nil,
+			Root:       p.ID == root.ID,
+			Level:      level,
+		})
+	}
+
+	addPlant(root, 0)
+	for _, p := range lineage.Ancestors {
+		addPlant(p, -p.Depth)
+	}
+	for _, p := range lineage.Descendants {
+		addPlant(p, p.Depth)
+	}
+
+	// Co-parents sit one level above their highest offspring
+	levels := make(map[int]int)
+	for _, p := range lineage.Descendants {
+		for _, parentID := range []int{int(p.SeedParentID.Int64), int(p.PollenParentID.Int64)} {
+			if l, ok := levels[parentID]; !ok || p.Depth-1 < l {
+				levels[parentID] = p.Depth - 1
+			}
+		}
+	}
+	for _, p := range coParents {
+		addPlant(p, levels[p.ID])
+	}
+
+	all := append([]types.PedigreeNode{root}, lineage.Ancestors...)
+	all = append(all, lineage.Descendants...)
+	for _, p := range all {
+		child := plantKey(p.ID)
+		level := g.Nodes[index[child]].Level
+		g.addParentEdge(index, child, level, p.SeedParentID.Int64, p.SeedParentID.Valid, p.SeedParentExt.String, types.ParentRoleSeed)
+		g.addParentEdge(index, child, level, p.PollenParentID.Int64, p.PollenParentID.Valid, p.PollenParentExt.String, types.ParentRolePollen)
+	}
+
+	g.order()
+	return g
+}
+
+func (g *Graph) addParentEdge(index map[string]int, child string, level int, parentID int64, hasParent bool, external string, role types.ParentRole) {
+	var parent string
+	switch {
+	case hasParent:
+		parent = plantKey(int(parentID))
+		if _, ok := index[parent]; !ok {
+			return
+		}
+	case strings.TrimSpace(external) != "":
+		parent = externalKey(external)
+		if i, ok := index[parent]; ok {
+			if g.Nodes[i].Level > level-1 {
+				g.Nodes[i].Level = level - 1
+			}
+		} else {
+			index[parent] = len(g.Nodes)
+			g.Nodes = append(g.Nodes, Node{
+				Key:      parent,
+				Name:     strings.TrimSpace(external),
+				External: true,
+				Level:    level - 1,
+			})
+		}
+	default:
+		return
+	}
+	g.Edges = append(g.Edges, Edge{From: parent, To: child, Role: role})
+}
+
+// order sorts nodes by level and, within a level, by the average position of
+// their neighbours on the level above, which keeps most edges from crossing.
+func (g *Graph) order() {
+	sort.SliceStable(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].Level < g.Nodes[j].Level
+	})
+
+	parents := make(map[string][]string)
+	for _, e := range g.Edges {
+		parents[e.To] = append(parents[e.To], e.From)
+	}
+
+	position := make(map[string]float64)
+	for start := 0; start < len(g.Nodes); {
+		end := start
+		for end < len(g.Nodes) && g.Nodes[end].Level == g.Nodes[start].Level {
+			end++
+		}
+		layer := g.Nodes[start:end]
+
+		weight := make(map[string]float64, len(layer))
+		for i, n := range layer {
+			var sum float64
+			var count int
+			for _, p := range parents[n.Key] {
+				if pos, ok := position[p]; ok {
+					sum += pos
+					count++
+				}
+			}
+			if count > 0 {
+				weight[n.Key] = sum / float64(count)
+			} else {
+				weight[n.Key] = float64(i)
+			}
+		}
+		sort.SliceStable(layer, func(i, j int) bool {
+			return weight[layer[i].Key] < weight[layer[j].Key]
+		})
+		for i, n := range layer {
+			position[n.Key] = float64(i) - float64(len(layer)-1)/2
+		}
+		start = end
+	}
+}
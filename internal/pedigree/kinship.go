@@ -0,0 +1,118 @@
+package pedigree
+
+import "math"
+
+// Parents holds the recorded parents of a plant. Zero means unknown, which
+// includes open pollination and parents known only by an external name;
+// unknown parents are treated as unrelated, non-inbred founders.
+type Parents struct {
+	Seed   int
+	Pollen int
+}
+
+// Calculator computes coancestry from a pedigree with the recursive
+// (tabular) method. Results are memoized, so one Calculator should be reused
+// for many queries over the same pedigree.
+type Calculator struct {
+	parents map[int]Parents
+	order   map[int]int
+	kinship map[[2]int]float64
+}
+
+func NewCalculator(parents map[int]Parents) *Calculator {
+	return &Calculator{
+		parents: parents,
+		order:   make(map[int]int),
+		kinship: make(map[[2]int]float64),
+	}
+}
+
+// Inbreeding is Wright's inbreeding coefficient F of a plant: the kinship of
+// its two parents. A selfed plant has the same plant on both sides, so one
+// round of selfing from a non-inbred parent gives F = 0.5.
+func (c *Calculator) Inbreeding(id int) float64 {
+	return c.inbreeding(id, make(map[int]bool))
+}
+
+// Kinship is the coefficient of coancestry of two plants: the probability
+// that alleles drawn at random from each are identical by descent. It is
+// also the inbreeding coefficient their offspring would have.
+func (c *Calculator) Kinship(a, b int) float64 {
+	return c.coancestry(a, b, make(map[int]bool))
+}
+
+// inbreeding and coancestry carry the plants being expanded in visiting. A
+// plant met again on its own path is part of a cyclic pedigree and is
+// treated as a founder, as in generationOrder.
+func (c *Calculator) inbreeding(id int, visiting map[int]bool) float64 {
+	if visiting[id] {
+		return 0
+	}
+	visiting[id] = true
+	defer delete(visiting, id)
+
+	p := c.parents[id]
+	return c.coancestry(p.Seed, p.Pollen, visiting)
+}
+
+func (c *Calculator) coancestry(a, b int, visiting map[int]bool) float64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	if a == b {
+		return (1 + c.inbreeding(a, visiting)) / 2
+	}
+
+	key := [2]int{min(a, b), max(a, b)}
+	if f, ok := c.kinship[key]; ok {
+		return f
+	}
+
+	// Expand the younger plant, which cannot be an ancestor of the other
+	if c.generationOrder(a, nil) < c.generationOrder(b, nil) {
+		a, b = b, a
+	}
+	if visiting[a] {
+		return 0
+	}
+	visiting[a] = true
+	defer delete(visiting, a)
+
+	p := c.parents[a]
+	f := (c.coancestry(p.Seed, b, visiting) + c.coancestry(p.Pollen, b, visiting)) / 2
+	c.kinship[key] = f
+	return f
+}
+
+// Relationship is Wright's coefficient of relationship r between two plants,
+// their kinship scaled by their own inbreeding. Full sibs from non-inbred
+// parents have r = 0.5.
+func (c *Calculator) Relationship(a, b int) float64 {
+	denominator := math.Sqrt((1 + c.Inbreeding(a)) * (1 + c.Inbreeding(b)))
+	return 2 * c.Kinship(a, b) / denominator
+}
+
+// generationOrder is the length of the longest path from a founder to the
+// plant. A parent always has a lower order than its offspring. visiting
+// guards against a cyclic pedigree, whose plants are treated as founders.
+func (c *Calculator) generationOrder(id int, visiting map[int]bool) int {
+	if id == 0 {
+		return -1
+	}
+	if o, ok := c.order[id]; ok {
+		return o
+	}
+	if visiting == nil {
+		visiting = make(map[int]bool)
+	}
+	if visiting[id] {
+		return 0
+	}
+	visiting[id] = true
+
+	p := c.parents[id]
+	o := 1 + max(c.generationOrder(p.Seed, visiting), c.generationOrder(p.Pollen, visiting))
+	delete(visiting, id)
+	c.order[id] = o
+	return o
+}
@@ -0,0 +1,62 @@
+package pedigree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculator(t *testing.T) {
+	// 1, 2 and 5 are founders. 3 and 4 are full sibs from 1 x 2, 6 is a half
+	// sib of 3 from 1 x 5, 7 is 1 selfed, 8 is 7 selfed, 9 is 3 x 4 and 10 is
+	// 3 backcrossed to 1.
+	c := NewCalculator(map[int]Parents{
+		3:  {Seed: 1, Pollen: 2},
+		4:  {Seed: 1, Pollen: 2},
+		6:  {Seed: 1, Pollen: 5},
+		7:  {Seed: 1, Pollen: 1},
+		8:  {Seed: 7, Pollen: 7},
+		9:  {Seed: 3, Pollen: 4},
+		10: {Seed: 3, Pollen: 1},
+	})
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "founder inbreeding", got: c.Inbreeding(1), want: 0},
+		{name: "unrelated founders", got: c.Kinship(1, 2), want: 0},
+		{name: "unknown parent", got: c.Kinship(1, 0), want: 0},
+		{name: "self kinship of founder", got: c.Kinship(1, 1), want: 0.5},
+		{name: "parent and offspring", got: c.Kinship(1, 3), want: 0.25},
+		{name: "full sibs", got: c.Kinship(3, 4), want: 0.25},
+		{name: "half sibs", got: c.Kinship(3, 6), want: 0.125},
+		{name: "S1 inbreeding", got: c.Inbreeding(7), want: 0.5},
+		{name: "S2 inbreeding", got: c.Inbreeding(8), want: 0.75},
+		{name: "sib mating", got: c.Inbreeding(9), want: 0.25},
+		{name: "backcross", got: c.Inbreeding(10), want: 0.25},
+		{name: "full sib relationship", got: c.Relationship(3, 4), want: 0.5},
+		{name: "parent relationship", got: c.Relationship(1, 3), want: 0.5},
+	}
+
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestCalculatorCycle(t *testing.T) {
+	// A mis-entered pedigree where 1 and 2 are each other's parents
+	c := NewCalculator(map[int]Parents{
+		1: {Seed: 2, Pollen: 3},
+		2: {Seed: 1, Pollen: 3},
+		4: {Seed: 1, Pollen: 2},
+	})
+
+	for _, f := range []float64{c.Inbreeding(1), c.Inbreeding(4), c.Kinship(1, 2), c.Kinship(4, 4)} {
+		if math.IsNaN(f) || f < 0 || f > 1 {
+			t.Errorf("got %v, want a coefficient between 0 and 1", f)
+		}
+	}
+}
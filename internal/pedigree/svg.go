@@ -0,0 +1,132 @@
+package pedigree
+
+import (
+	"fmt"
+	"html"
+	"pepper-analytics-ai/internal/types"
+	"strings"
+)
+
+const (
+	nodeWidth  = 180
+	nodeHeight = 58
+	nodeGapX   = 30
+	levelGapY  = 80
+	margin     = 20
+	lineHeight = 15
+)
+
+type point struct {
+	x, y float64
+}
+
+// layout places nodes on a grid: one row per level, each row centred. Nodes
+// are expected in the order produced by Build.
+func (g Graph) layout() (map[string]point, float64, float64) {
+	rows := make(map[int][]string)
+	var levels []int
+	for _, n := range g.Nodes {
+		if _, ok := rows[n.Level]; !ok {
+			levels = append(levels, n.Level)
+		}
+		rows[n.Level] = append(rows[n.Level], n.Key)
+	}
+
+	widest := 0
+	for _, row := range rows {
+		if len(row) > widest {
+			widest = len(row)
+		}
+	}
+	width := float64(widest*nodeWidth + (widest-1)*nodeGapX + 2*margin)
+	height := float64(len(levels)*nodeHeight + (len(levels)-1)*levelGapY + 2*margin)
+
+	positions := make(map[string]point, len(g.Nodes))
+	for i, level := range levels {
+		row := rows[level]
+		rowWidth := float64(len(row)*nodeWidth + (len(row)-1)*nodeGapX)
+		x := (width - rowWidth) / 2
+		y := float64(margin + i*(nodeHeight+levelGapY))
+		for _, key := range row {
+			positions[key] = point{x, y}
+			x += nodeWidth + nodeGapX
+		}
+	}
+	return positions, width, height
+}
+
+// SVG renders the graph as a standalone SVG document without calling out to
+// Graphviz.
+func (g Graph) SVG() string {
+	positions, width, height := g.layout()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		width, height, width, height)
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#6c757d"/></marker></defs>` + "\n")
+
+	for _, e := range g.Edges {
+		from, to := positions[e.From], positions[e.To]
+		x1, y1 := from.x+nodeWidth/2, from.y+nodeHeight
+		x2, y2 := to.x+nodeWidth/2, to.y
+		// Offset the two edges of a selfing so both stay visible
+		if e.Role == types.ParentRolePollen {
+			x1 += 12
+			x2 += 12
+		}
+		midY := (y1 + y2) / 2
+		dash := ""
+		if e.Role == types.ParentRolePollen {
+			dash = ` stroke-dasharray="5,4"`
+		}
+		fmt.Fprintf(&b, `<path d="M %.1f %.1f C %.1f %.1f, %.1f %.1f, %.1f %.1f" fill="none" stroke="#6c757d" stroke-width="1.5"%s marker-end="url(#arrow)"/>`+"\n",
+			x1, y1, x1, midY, x2, midY, x2, y2, dash)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="10" fill="#6c757d" text-anchor="middle">%s</text>`+"\n",
+			(x1+x2)/2, midY-3, html.EscapeString(string(e.Role)))
+	}
+
+	for _, n := range g.Nodes {
+		p := positions[n.Key]
+		fill, stroke, strokeWidth, dash, textColor := "#ffffff", "#495057", "1", "", "#212529"
+		switch {
+		case n.Root:
+			fill, strokeWidth = "#fde2c8", "2"
+		case n.External:
+			dash = ` stroke-dasharray="4,3"`
+		case n.Removed:
+			stroke, textColor = "#adb5bd", "#6c757d"
+		}
+
+		if n.PlantID != 0 {
+			fmt.Fprintf(&b, `<a href="/plants/%d/journal">`, n.PlantID)
+		}
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%d" height="%d" rx="8" fill="%s" stroke="%s" stroke-width="%s"%s/>`,
+			p.x, p.y, nodeWidth, nodeHeight, fill, stroke, strokeWidth, dash)
+
+		lines := n.lines()
+		top := p.y + nodeHeight/2 - float64(len(lines)-1)*lineHeight/2 + 4
+		for i, line := range lines {
+			size, weight := 11, "normal"
+			if i == 0 {
+				size, weight = 13, "bold"
+			}
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%d" font-weight="%s" fill="%s" text-anchor="middle">%s</text>`,
+				p.x+nodeWidth/2, top+float64(i*lineHeight), size, weight, textColor, html.EscapeString(truncate(line, 26)))
+		}
+		if n.PlantID != 0 {
+			b.WriteString(`</a>`)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
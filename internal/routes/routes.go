@@ -4,11 +4,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	"pepper-analytics-ai/internal/handlers"
+	"pepper-analytics-ai/internal/notify"
 	"pepper-analytics-ai/internal/services"
+	"pepper-analytics-ai/templates/email"
 )
 
 type RouterConfig struct {
-	DB *sqlx.DB
+	DB     *sqlx.DB
+	Notify notify.Config
 }
 
 func SetupRouter(config RouterConfig) (*gin.Engine, error) {
@@ -17,9 +20,43 @@ func SetupRouter(config RouterConfig) (*gin.Engine, error) {
 
 	// Initialize services
 	plantService := services.NewPlantService(config.DB)
+	seedLotService := services.NewSeedLotService(config.DB)
+	harvestService := services.NewHarvestService(config.DB)
+	pollinationService := services.NewPollinationService(config.DB)
+	traitService := services.NewTraitService(config.DB)
+	projectService := services.NewProjectService(config.DB)
+	selectionService := services.NewSelectionService(config.DB)
+	pedigreeService := services.NewPedigreeService(config.DB)
+	genotypeService := services.NewGenotypeService(config.DB)
+	heatService := services.NewHeatService(config.DB)
+	varietyService := services.NewVarietyService(config.DB)
+	speciesService := services.NewSpeciesService(config.DB)
+	crossService := services.NewCrossService(config.DB, traitService, pedigreeService, genotypeService)
+	analyticsService := services.NewAnalyticsService(config.DB, plantService, traitService)
+	taskService := services.NewTaskService(config.DB, plantService)
+	notificationService := services.NewNotificationService(config.DB, config.Notify)
+	schedulerService := services.NewSchedulerService(config.DB)
+	digestService := services.NewDigestService(plantService, taskService, harvestService, notificationService, email.RenderDigest)
 	fileService := services.NewFileService("/uploads")
 
-	plantHandler := handlers.NewPlantHandler(plantService, fileService)
+	plantHandler := handlers.NewPlantHandler(plantService, seedLotService, varietyService, speciesService, fileService)
+	seedLotHandler := handlers.NewSeedLotHandler(seedLotService, plantService)
+	harvestHandler := handlers.NewHarvestHandler(harvestService, fileService)
+	pollinationHandler := handlers.NewPollinationHandler(pollinationService, plantService)
+	traitHandler := handlers.NewTraitHandler(traitService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, traitService)
+	projectHandler := handlers.NewProjectHandler(projectService, plantService, traitService, selectionService)
+	selectionHandler := handlers.NewSelectionHandler(selectionService, plantService, traitService)
+	pedigreeHandler := handlers.NewPedigreeHandler(pedigreeService, plantService)
+	crossHandler := handlers.NewCrossHandler(crossService, plantService)
+	genotypeHandler := handlers.NewGenotypeHandler(genotypeService)
+	heatHandler := handlers.NewHeatHandler(heatService, harvestService, plantService)
+	varietyHandler := handlers.NewVarietyHandler(varietyService, speciesService, plantService, harvestService)
+	speciesHandler := handlers.NewSpeciesHandler(speciesService)
+	germinationHandler := handlers.NewGerminationHandler(plantService, seedLotService, varietyService)
+	taskHandler := handlers.NewTaskHandler(taskService, plantService, projectService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, digestService)
+	jobHandler := handlers.NewJobHandler(schedulerService)
 
 	// Static files
 	router.LoadHTMLGlob("templates/**/*")
@@ -41,7 +78,17 @@ func SetupRouter(config RouterConfig) (*gin.Engine, error) {
 	router.GET("/plants/:id/edit", plantHandler.HandleEditPlantForm)
 	router.PUT("/plants/:id", plantHandler.HandleUpdatePlant)
 	router.DELETE("/plants/:id", plantHandler.HandleDeletePlant)
-	router.PUT("/plants/:id/harvest", plantHandler.HandleHarvestPlant)
+	router.PUT("/plants/:id/finish-season", plantHandler.HandleFinishSeason)
+	router.GET("/plants/:id/lineage", plantHandler.HandleLineage)
+	router.GET("/plants/:id/pedigree.dot", plantHandler.HandlePedigreeDOT)
+	router.GET("/plants/:id/pedigree.svg", plantHandler.HandlePedigreeSVG)
+	router.GET("/plants/:id/inbreeding", pedigreeHandler.HandlePlantInbreeding)
+	router.GET("/plants/:id/stages", plantHandler.HandleStageTimeline)
+	router.POST("/plants/:id/stages/advance", plantHandler.HandleAdvanceStage)
+	router.POST("/plants/:id/stages/move", plantHandler.HandleMoveStage)
+	router.GET("/plants/:id/health", plantHandler.HandleHealthHistory)
+	router.POST("/plants/:id/health", plantHandler.HandleSetHealth)
+	router.PUT("/health-alerts/:alertId/acknowledge", plantHandler.HandleAcknowledgeHealthAlert)
 
 	// routes.go
 	router.GET("/plants/:id/journal", plantHandler.HandleJournal)
@@ -51,6 +98,125 @@ func SetupRouter(config RouterConfig) (*gin.Engine, error) {
 	router.GET("/plants/:id/journal/:entryId/edit", plantHandler.HandleEditJournalEntry)
 	router.PUT("/plants/:id/journal/:entryId", plantHandler.HandleUpdateJournalEntry)
 
+	// Harvest log routes
+	router.GET("/plants/:id/harvests", harvestHandler.HandlePlantHarvests)
+	router.POST("/plants/:id/harvests", harvestHandler.HandleCreateHarvest)
+	router.DELETE("/plants/:id/harvests/:harvestId", harvestHandler.HandleDeleteHarvest)
+
+	// Pollination routes
+	router.GET("/pollinations", pollinationHandler.HandlePollinationOverview)
+	router.GET("/plants/:id/pollinations", pollinationHandler.HandlePlantPollinations)
+	router.POST("/plants/:id/pollinations", pollinationHandler.HandleCreatePollination)
+	router.PUT("/plants/:id/pollinations/:pollinationId/outcome", pollinationHandler.HandleRecordOutcome)
+	router.POST("/plants/:id/pollinations/:pollinationId/seed-lot", pollinationHandler.HandleSaveSeed)
+	router.DELETE("/plants/:id/pollinations/:pollinationId", pollinationHandler.HandleDeletePollination)
+
+	// Cross planner routes
+	router.GET("/crosses", crossHandler.HandleCrossPlanner)
+	router.POST("/crosses", crossHandler.HandleCreatePlannedCross)
+	router.GET("/crosses/predict", crossHandler.HandlePredictCross)
+	router.GET("/crosses/:crossId", crossHandler.HandlePlannedCross)
+	router.DELETE("/crosses/:crossId", crossHandler.HandleDeletePlannedCross)
+	router.GET("/crosses/:crossId/progress", crossHandler.HandleCrossProgress)
+	router.PUT("/crosses/:crossId/status", crossHandler.HandleUpdateCrossStatus)
+	router.POST("/crosses/:crossId/pollinations", crossHandler.HandleLinkPollination)
+	router.DELETE("/crosses/:crossId/pollinations/:pollinationId", crossHandler.HandleUnlinkPollination)
+
+	// Seed inventory routes
+	router.GET("/seed-lots", seedLotHandler.HandleSeedInventory)
+	router.PUT("/seed-lots/:lotId/count", seedLotHandler.HandleAdjustSeedCount)
+	router.DELETE("/seed-lots/:lotId", seedLotHandler.HandleDeleteSeedLot)
+	router.GET("/plants/:id/seed-lots", seedLotHandler.HandlePlantSeedLots)
+	router.POST("/plants/:id/seed-lots", seedLotHandler.HandleCreateSeedLot)
+
+	// Germination trial routes
+	router.GET("/germination", germinationHandler.HandleTrialList)
+	router.POST("/germination", germinationHandler.HandleCreateTrial)
+	router.GET("/germination/:trialId", germinationHandler.HandleTrial)
+	router.DELETE("/germination/:trialId", germinationHandler.HandleDeleteTrial)
+	router.POST("/germination/:trialId/counts", germinationHandler.HandleRecordCount)
+	router.DELETE("/germination/:trialId/counts/:countId", germinationHandler.HandleDeleteCount)
+	router.POST("/germination/:trialId/promote", germinationHandler.HandlePromoteSeedlings)
+
+	// Task routes
+	router.GET("/tasks", taskHandler.HandleTaskList)
+	router.POST("/tasks", taskHandler.HandleCreateTask)
+	router.POST("/tasks/:taskId/complete", taskHandler.HandleCompleteTask)
+	router.DELETE("/tasks/:taskId", taskHandler.HandleDeleteTask)
+
+	// Notification routes
+	router.GET("/notifications", notificationHandler.HandleNotifications)
+	router.POST("/notifications/users", notificationHandler.HandleCreateUser)
+	router.PUT("/notifications/users/:userId/quiet-hours", notificationHandler.HandleSetQuietHours)
+	router.DELETE("/notifications/users/:userId", notificationHandler.HandleDeleteUser)
+	router.POST("/notifications/users/:userId/channels", notificationHandler.HandleCreateChannel)
+	router.PUT("/notifications/users/:userId/digest", notificationHandler.HandleSetDigest)
+	router.GET("/notifications/users/:userId/digest", notificationHandler.HandleDigestPreview)
+	router.POST("/notifications/users/:userId/digest/send", notificationHandler.HandleSendDigest)
+	router.PUT("/notifications/channels/:channelId/enabled", notificationHandler.HandleSetChannelEnabled)
+	router.POST("/notifications/channels/:channelId/test", notificationHandler.HandleTestChannel)
+	router.DELETE("/notifications/channels/:channelId", notificationHandler.HandleDeleteChannel)
+
+	// Background job admin routes
+	router.GET("/admin/jobs", jobHandler.HandleJobList)
+	router.PUT("/admin/jobs/:name/schedule", jobHandler.HandleSetSchedule)
+	router.PUT("/admin/jobs/:name/enabled", jobHandler.HandleSetEnabled)
+	router.POST("/admin/jobs/:name/run", jobHandler.HandleRunNow)
+
+	// Phenotype trait routes
+	router.GET("/traits", traitHandler.HandleTraitDefinitions)
+	router.POST("/traits", traitHandler.HandleCreateTraitDefinition)
+	router.DELETE("/traits/:traitId", traitHandler.HandleDeleteTraitDefinition)
+	router.GET("/plants/:id/traits", traitHandler.HandlePlantTraits)
+	router.POST("/plants/:id/traits", traitHandler.HandleScorePlant)
+
+	// Genotype routes
+	router.GET("/plants/:id/genotypes", genotypeHandler.HandlePlantGenotypes)
+	router.POST("/plants/:id/genotypes", genotypeHandler.HandleRecordGenotype)
+	router.DELETE("/plants/:id/genotypes/:genotypeId", genotypeHandler.HandleDeleteGenotype)
+
+	// Heat test routes
+	router.GET("/plants/:id/heat", heatHandler.HandlePlantHeat)
+	router.POST("/plants/:id/heat", heatHandler.HandleCreateHeatTest)
+	router.DELETE("/plants/:id/heat/:testId", heatHandler.HandleDeleteHeatTest)
+
+	// Selection routes
+	router.GET("/plants/:id/selection", selectionHandler.HandlePlantSelection)
+	router.POST("/plants/:id/selection", selectionHandler.HandleRecordSelection)
+	router.PUT("/plants/:id/cull", selectionHandler.HandleCullPlant)
+
+	// Variety catalog routes
+	router.GET("/varieties", varietyHandler.HandleVarietyList)
+	router.POST("/varieties", varietyHandler.HandleCreateVariety)
+	router.GET("/varieties/:varietyId", varietyHandler.HandleVariety)
+	router.PUT("/varieties/:varietyId", varietyHandler.HandleUpdateVariety)
+	router.DELETE("/varieties/:varietyId", varietyHandler.HandleDeleteVariety)
+
+	// Species taxonomy routes
+	router.GET("/species", speciesHandler.HandleSpeciesList)
+	router.POST("/species", speciesHandler.HandleCreateSpecies)
+	router.POST("/species/hybrids", speciesHandler.HandleCreateHybrid)
+	router.POST("/species/:speciesId/synonyms", speciesHandler.HandleAddSynonym)
+	router.DELETE("/species/:speciesId", speciesHandler.HandleDeleteSpecies)
+
+	// Breeding project routes
+	router.GET("/projects", projectHandler.HandleProjectList)
+	router.POST("/projects", projectHandler.HandleCreateProject)
+	router.GET("/projects/:projectId", projectHandler.HandleProjectDashboard)
+	router.PUT("/projects/:projectId", projectHandler.HandleUpdateProject)
+	router.DELETE("/projects/:projectId", projectHandler.HandleDeleteProject)
+	router.POST("/projects/:projectId/plants", projectHandler.HandleAddPlant)
+	router.POST("/projects/:projectId/descendants", projectHandler.HandleAddDescendants)
+	router.DELETE("/projects/:projectId/plants/:plantId", projectHandler.HandleRemovePlant)
+	router.PUT("/projects/:projectId/plants/:plantId/selection", projectHandler.HandleSetSelection)
+	router.POST("/projects/:projectId/traits", projectHandler.HandleSetTargetTrait)
+	router.DELETE("/projects/:projectId/traits/:traitId", projectHandler.HandleRemoveTargetTrait)
+
+	// Analytics routes
+	router.GET("/analytics/segregation", analyticsHandler.HandleSegregation)
+	router.GET("/analytics/relatedness", pedigreeHandler.HandleRelatedness)
+	router.GET("/analytics/heat", heatHandler.HandleHeatDistribution)
+
 	// 404 handler
 	router.NoRoute(plantHandler.HandlePlantList) // Redirects all unknown routes to plant list
 
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pepper-analytics-ai/internal/stats"
+	"pepper-analytics-ai/internal/types"
+	"sort"
+)
+
+var (
+	ErrTraitNotCategorical = errors.New("segregation analysis needs categorical traits")
+	ErrClassCountMismatch  = errors.New("observed classes do not match the ratio")
+	ErrClassAssignment     = errors.New("class assignment does not match the ratio")
+)
+
+// AnalyticsService runs breeding analyses over the plants and phenotype
+// scores kept by PlantService and TraitService.
+type AnalyticsService struct {
+	db           *sqlx.DB
+	plantService *PlantService
+	traitService *TraitService
+}
+
+func NewAnalyticsService(db *sqlx.DB, plantService *PlantService, traitService *TraitService) *AnalyticsService {
+	return &AnalyticsService{
+		db:           db,
+		plantService: plantService,
+		traitService: traitService,
+	}
+}
+
+// GetCrossFamilies lists parent pairs with more than one living offspring,
+// best scored families first.
+func (s *AnalyticsService) GetCrossFamilies() ([]types.CrossFamily, error) {
+	query := `
+        SELECT LEAST(p.seed_parent_id, p.pollen_parent_id) as parent_a_id,
+               GREATEST(p.seed_parent_id, p.pollen_parent_id) as parent_b_id,
+               a.name as parent_a_name,
+               b.name as parent_b_name,
+               MIN(p.generation) as generation,
+               COUNT(*) as offspring_count,
+               COUNT(*) FILTER (WHERE EXISTS (
+                   SELECT 1 FROM trait_scores ts
+                   WHERE ts.plant_id = p.id AND ts.deleted_at IS NULL
+               )) as scored_count
+        FROM plants p
+        JOIN plants a ON a.id = LEAST(p.seed_parent_id, p.pollen_parent_id)
+        JOIN plants b ON b.id = GREATEST(p.seed_parent_id, p.pollen_parent_id)
+        WHERE p.deleted_at IS NULL
+        AND p.seed_parent_id IS NOT NULL
+        AND p.pollen_parent_id IS NOT NULL
+        GROUP BY 1, 2, 3, 4
+        HAVING COUNT(*) > 1
+        ORDER BY scored_count DESC, offspring_count DESC, parent_a_name
+    `
+	var families []types.CrossFamily
+	if err := s.db.Select(&families, query); err != nil {
+		return nil, fmt.Errorf("error fetching cross families: %w", err)
+	}
+	return families, nil
+}
+
+type plantTraitClass struct {
+	PlantID int    `db:"plant_id"`
+	Label   string `db:"label"`
+}
+
+// getFamilyTraitClasses returns the latest class of a categorical trait for
+// each scored offspring of a family, keyed by plant.
+func (s *AnalyticsService) getFamilyTraitClasses(parentAID, parentBID, traitID int) (map[int]string, error) {
+	query := `
+        SELECT DISTINCT ON (ts.plant_id)
+               ts.plant_id,
+               COALESCE(st.label, ts.state_value::text) as label
+        FROM trait_scores ts
+        JOIN plants p ON ts.plant_id = p.id
+        LEFT JOIN trait_states st ON st.trait_id = ts.trait_id AND st.value = ts.state_value
+        WHERE ts.trait_id = $1
+        AND ts.state_value IS NOT NULL
+        AND ts.deleted_at IS NULL
+        AND p.deleted_at IS NULL
+        AND LEAST(p.seed_parent_id, p.pollen_parent_id) = $2
+        AND GREATEST(p.seed_parent_id, p.pollen_parent_id) = $3
+        ORDER BY ts.plant_id, ts.observed_date DESC, ts.id DESC
+    `
+	var rows []plantTraitClass
+	if err := s.db.Select(&rows, query, traitID, parentAID, parentBID); err != nil {
+		return nil, fmt.Errorf("error fetching family trait classes: %w", err)
+	}
+
+	classes := make(map[int]string, len(rows))
+	for _, row := range rows {
+		classes[row.PlantID] = row.Label
+	}
+	return classes, nil
+}
+
+// AnalyzeSegregation tallies the phenotype classes of a family and tests them
+// against the expected ratio with a chi-square goodness-of-fit test. With a
+// second trait the classes are the combinations of both, as for a dihybrid
+// 9:3:3:1.
+//
+// Observed classes take the ratio parts given in query.Parts. Without them
+// classes are matched to the ratio by size, the most common class taking the
+// largest part, as a suggestion only: that always flatters the fit, so the
+// test is left out and the result flagged. When the
+// number of observed classes differs from the ratio the tally is still
+// returned along with ErrClassCountMismatch, and an assignment that does not
+// use each part once returns it along with ErrClassAssignment.
+func (s *AnalyticsService) AnalyzeSegregation(query types.SegregationQuery) (*types.SegregationResult, error) {
+	offspring, err := s.plantService.GetFamilyOffspring(query.ParentAID, query.ParentBID)
+	if err != nil {
+		return nil, err
+	}
+	if len(offspring) == 0 {
+		return nil, ErrPlantNotFound
+	}
+
+	result := &types.SegregationResult{
+		Family: types.CrossFamily{
+			ParentAID:      query.ParentAID,
+			ParentBID:      query.ParentBID,
+			OffspringCount: len(offspring),
+		},
+		Ratio: query.Ratio,
+	}
+	if err := s.setFamilyNames(&result.Family); err != nil {
+		return nil, err
+	}
+
+	traitIDs := []int{query.TraitID}
+	if query.SecondTraitID != 0 {
+		traitIDs = append(traitIDs, query.SecondTraitID)
+	}
+
+	var perTrait []map[int]string
+	for _, traitID := range traitIDs {
+		trait, err := s.traitService.GetTraitDefinition(traitID)
+		if err != nil {
+			return nil, err
+		}
+		if trait.ScaleType != types.TraitScaleCategorical {
+			return nil, fmt.Errorf("%w: %s is numeric", ErrTraitNotCategorical, trait.Name)
+		}
+		result.Traits = append(result.Traits, trait.Name)
+
+		classes, err := s.getFamilyTraitClasses(query.ParentAID, query.ParentBID, traitID)
+		if err != nil {
+			return nil, err
+		}
+		perTrait = append(perTrait, classes)
+	}
+
+	counts := make(map[string]int)
+	for _, plant := range offspring {
+		var labels []string
+		for _, classes := range perTrait {
+			label, ok := classes[plant.ID]
+			if !ok {
+				break
+			}
+			labels = append(labels, label)
+		}
+		if len(labels) != len(perTrait) {
+			result.Unscored++
+			continue
+		}
+
+		label := labels[0]
+		for _, l := range labels[1:] {
+			label += " / " + l
+		}
+		counts[label]++
+		result.Total++
+	}
+
+	for label, count := range counts {
+		result.Classes = append(result.Classes, types.SegregationClass{Label: label, Observed: count})
+	}
+	sort.Slice(result.Classes, func(i, j int) bool {
+		if result.Classes[i].Observed != result.Classes[j].Observed {
+			return result.Classes[i].Observed > result.Classes[j].Observed
+		}
+		return result.Classes[i].Label < result.Classes[j].Label
+	})
+
+	if len(result.Classes) != len(query.Ratio) {
+		return result, fmt.Errorf("%w: found %d classes for a %d-class ratio",
+			ErrClassCountMismatch, len(result.Classes), len(query.Ratio))
+	}
+
+	parts := append(types.SegregationRatio(nil), query.Ratio...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(parts)))
+	for i := range result.Classes {
+		result.Classes[i].Part = parts[i]
+	}
+	// Matching classes to parts by size always fits as well as possible, so
+	// the test waits for the grower to say which class takes which part. An
+	// even ratio such as 1:1 has nothing to choose.
+	if len(query.Parts) == 0 {
+		result.AssignedBySize = parts[0] != parts[len(parts)-1]
+		if result.AssignedBySize {
+			return result, nil
+		}
+	} else if err := assignParts(result.Classes, query.Parts, parts); err != nil {
+		return result, err
+	}
+
+	var partTotal float64
+	for _, part := range parts {
+		partTotal += part
+	}
+
+	observed := make([]int, len(result.Classes))
+	expected := make([]float64, len(result.Classes))
+	for i := range result.Classes {
+		result.Classes[i].Expected = float64(result.Total) * result.Classes[i].Part / partTotal
+		observed[i] = result.Classes[i].Observed
+		expected[i] = result.Classes[i].Expected
+	}
+
+	result.ChiSquare = stats.ChiSquare(observed, expected)
+	result.DegreesOfFreedom = len(result.Classes) - 1
+	result.PValue = stats.ChiSquarePValue(result.ChiSquare, result.DegreesOfFreedom)
+	return result, nil
+}
+
+// assignParts gives each class the ratio part picked for it. The picks must
+// use every part of the ratio exactly once; sortedParts is the ratio sorted
+// largest first. Classes are left untouched when the picks do not fit.
+func assignParts(classes []types.SegregationClass, picks map[string]float64, sortedParts types.SegregationRatio) error {
+	picked := make([]float64, 0, len(classes))
+	for _, class := range classes {
+		part, ok := picks[class.Label]
+		if !ok {
+			return fmt.Errorf("%w: no part picked for %s", ErrClassAssignment, class.Label)
+		}
+		picked = append(picked, part)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(picked)))
+	for i := range picked {
+		if picked[i] != sortedParts[i] {
+			return fmt.Errorf("%w: each part of the ratio must be picked once", ErrClassAssignment)
+		}
+	}
+
+	for i := range classes {
+		classes[i].Part = picks[classes[i].Label]
+	}
+	return nil
+}
+
+// setFamilyNames looks up the parent names directly, since the parents of a
+// finished generation are often already deleted.
+func (s *AnalyticsService) setFamilyNames(family *types.CrossFamily) error {
+	query := `
+        SELECT a.name as parent_a_name, b.name as parent_b_name
+        FROM plants a, plants b
+        WHERE a.id = $1 AND b.id = $2
+    `
+	if err := s.db.Get(family, query, family.ParentAID, family.ParentBID); err != nil {
+		return fmt.Errorf("error fetching family parents: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pepper-analytics-ai/internal/genetics"
+	"pepper-analytics-ai/internal/types"
+)
+
+var (
+	ErrPlannedCrossNotFound = errors.New("planned cross not found")
+	ErrPollinationMismatch  = errors.New("pollination does not match the planned cross parents")
+)
+
+type CrossService struct {
+	db              *sqlx.DB
+	traitService    *TraitService
+	pedigreeService *PedigreeService
+	genotypeService *GenotypeService
+}
+
+func NewCrossService(db *sqlx.DB, traitService *TraitService, pedigreeService *PedigreeService, genotypeService *GenotypeService) *CrossService {
+	return &CrossService{
+		db:              db,
+		traitService:    traitService,
+		pedigreeService: pedigreeService,
+		genotypeService: genotypeService,
+	}
+}
+
+const plannedCrossSelect = `
+        SELECT pc.*,
+               sp.name as seed_parent_name,
+               pp.name as pollen_parent_name,
+               bp.name as project_name,
+               (
+                   SELECT COUNT(*)
+                   FROM pollinations po
+                   WHERE po.planned_cross_id = pc.id AND po.deleted_at IS NULL
+               ) as pollination_count
+        FROM planned_crosses pc
+        JOIN plants sp ON pc.seed_parent_id = sp.id
+        JOIN plants pp ON pc.pollen_parent_id = pp.id
+        LEFT JOIN breeding_projects bp ON pc.project_id = bp.id AND bp.deleted_at IS NULL
+`
+
+// GetPlannedCrosses lists planned crosses, open ones first.
+func (s *CrossService) GetPlannedCrosses() ([]types.PlannedCross, error) {
+	query := plannedCrossSelect + `
+        WHERE pc.deleted_at IS NULL
+        ORDER BY pc.status IN ('Completed', 'Abandoned'), pc.created_at DESC
+    `
+	var crosses []types.PlannedCross
+	if err := s.db.Select(&crosses, query); err != nil {
+		return nil, fmt.Errorf("error fetching planned crosses: %w", err)
+	}
+	return crosses, nil
+}
+
+func (s *CrossService) GetPlannedCross(id int) (*types.PlannedCross, error) {
+	query := plannedCrossSelect + `
+        WHERE pc.id = $1 AND pc.deleted_at IS NULL
+    `
+	var cross types.PlannedCross
+	if err := s.db.Get(&cross, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPlannedCrossNotFound
+		}
+		return nil, fmt.Errorf("error fetching planned cross: %w", err)
+	}
+	return &cross, nil
+}
+
+func (s *CrossService) CreatePlannedCross(cross *types.PlannedCross) error {
+	query := `
+        INSERT INTO planned_crosses (seed_parent_id, pollen_parent_id, project_id, status, notes)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at, updated_at
+    `
+	err := s.db.QueryRow(
+		query,
+		cross.SeedParentID,
+		cross.PollenParentID,
+		cross.ProjectID,
+		cross.Status,
+		cross.Notes,
+	).Scan(&cross.ID, &cross.CreatedAt, &cross.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating planned cross: %w", err)
+	}
+	return nil
+}
+
+func (s *CrossService) UpdateStatus(id int, status types.CrossStatus) error {
+	query := `
+        UPDATE planned_crosses
+        SET status = $1,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND deleted_at IS NULL
+    `
+	result, err := s.db.Exec(query, status, id)
+	if err != nil {
+		return fmt.Errorf("error updating planned cross status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrPlannedCrossNotFound
+	}
+	return nil
+}
+
+func (s *CrossService) DeletePlannedCross(id int) error {
+	query := `UPDATE planned_crosses SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	result, err := s.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting planned cross: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrPlannedCrossNotFound
+	}
+	return nil
+}
+
+// GetCrossPollinations lists the pollinations carried out for a planned cross.
+func (s *CrossService) GetCrossPollinations(crossID int) ([]types.Pollination, error) {
+	query := pollinationSelect + `
+        WHERE po.planned_cross_id = $1 AND po.deleted_at IS NULL
+        ORDER BY po.pollination_date, po.id
+    `
+	var pollinations []types.Pollination
+	if err := s.db.Select(&pollinations, query, crossID); err != nil {
+		return nil, fmt.Errorf("error fetching cross pollinations: %w", err)
+	}
+	return pollinations, nil
+}
+
+// GetCandidatePollinations lists unlinked pollinations between the cross'
+// parents in the planned direction.
+func (s *CrossService) GetCandidatePollinations(cross *types.PlannedCross) ([]types.Pollination, error) {
+	query := pollinationSelect + `
+        WHERE po.plant_id = $1
+        AND po.donor_plant_id = $2
+        AND po.planned_cross_id IS NULL
+        AND po.deleted_at IS NULL
+        ORDER BY po.pollination_date DESC, po.id DESC
+    `
+	var pollinations []types.Pollination
+	if err := s.db.Select(&pollinations, query, cross.SeedParentID, cross.PollenParentID); err != nil {
+		return nil, fmt.Errorf("error fetching candidate pollinations: %w", err)
+	}
+	return pollinations, nil
+}
+
+// LinkPollination records a pollination as carried out for a planned cross
+// and moves a cross that was only planned on to attempted.
+func (s *CrossService) LinkPollination(crossID, pollinationID int) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE pollinations po
+        SET planned_cross_id = pc.id,
+            updated_at = CURRENT_TIMESTAMP
+        FROM planned_crosses pc
+        WHERE po.id = $1
+        AND pc.id = $2
+        AND po.plant_id = pc.seed_parent_id
+        AND po.donor_plant_id = pc.pollen_parent_id
+        AND po.deleted_at IS NULL
+        AND pc.deleted_at IS NULL
+    `
+	result, err := tx.Exec(query, pollinationID, crossID)
+	if err != nil {
+		return fmt.Errorf("error linking pollination: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrPollinationMismatch
+	}
+
+	query = `
+        UPDATE planned_crosses
+        SET status = 'Attempted',
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'Planned'
+    `
+	if _, err := tx.Exec(query, crossID); err != nil {
+		return fmt.Errorf("error updating planned cross status: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *CrossService) UnlinkPollination(crossID, pollinationID int) error {
+	query := `
+        UPDATE pollinations
+        SET planned_cross_id = NULL,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND planned_cross_id = $2
+    `
+	if _, err := s.db.Exec(query, pollinationID, crossID); err != nil {
+		return fmt.Errorf("error unlinking pollination: %w", err)
+	}
+	return nil
+}
+
+// GetCrossOffspring lists the plants sown from seed saved off the cross'
+// pollinations.
+func (s *CrossService) GetCrossOffspring(crossID int) ([]types.PlantOption, error) {
+	query := `
+        SELECT p.id, p.name, p.species, p.generation
+        FROM plants p
+        JOIN seed_lots sl ON p.seed_lot_id = sl.id
+        JOIN pollinations po ON sl.pollination_id = po.id
+        WHERE po.planned_cross_id = $1 AND p.deleted_at IS NULL
+        ORDER BY p.name, p.id
+    `
+	var offspring []types.PlantOption
+	if err := s.db.Select(&offspring, query, crossID); err != nil {
+		return nil, fmt.Errorf("error fetching cross offspring: %w", err)
+	}
+	return offspring, nil
+}
+
+// PredictCross works out the expected F1 and F2 of crossing two plants, or
+// of selfing one when both IDs are the same.
+func (s *CrossService) PredictCross(seedParentID, pollenParentID int) (*types.CrossPrediction, error) {
+	relationship, err := s.pedigreeService.GetRelationship(seedParentID, pollenParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	prediction := &types.CrossPrediction{
+		SeedParent:   relationship.PlantA,
+		PollenParent: relationship.PlantB,
+		Relationship: relationship,
+	}
+	if prediction.SeedScores, err = s.traitService.GetPlantScores(seedParentID); err != nil {
+		return nil, err
+	}
+	if prediction.PollenScores, err = s.traitService.GetPlantScores(pollenParentID); err != nil {
+		return nil, err
+	}
+
+	seedGenotypes, err := s.genotypeService.GetEffectiveGenotypes(seedParentID)
+	if err != nil {
+		return nil, err
+	}
+	pollenGenotypes, err := s.genotypeService.GetEffectiveGenotypes(pollenParentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, locus := range types.KnownLoci {
+		prediction.Loci = append(prediction.Loci, genetics.Predict(
+			locus,
+			seedGenotypes[locus.Symbol],
+			pollenGenotypes[locus.Symbol],
+			prediction.IsSelf(),
+		))
+	}
+	prediction.F1Classes = genetics.Classes(prediction.Loci, false)
+	prediction.F2Classes = genetics.Classes(prediction.Loci, true)
+	return prediction, nil
+}
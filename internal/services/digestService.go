@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"pepper-analytics-ai/internal/notify"
+	"pepper-analytics-ai/internal/types"
+	"time"
+)
+
+// digestJournalLimit caps the journal section so a busy week does not bury
+// the rest of the digest.
+const digestJournalLimit = 30
+
+// DigestRenderer turns a digest into a message with its title, plain text
+// and HTML.
+type DigestRenderer func(ctx context.Context, digest types.Digest) (notify.Message, error)
+
+// DigestService gathers the garden digest from the other services and sends
+// it to users over their notification channels.
+type DigestService struct {
+	plantService        *PlantService
+	taskService         *TaskService
+	harvestService      *HarvestService
+	notificationService *NotificationService
+	render              DigestRenderer
+}
+
+func NewDigestService(plantService *PlantService, taskService *TaskService, harvestService *HarvestService, notificationService *NotificationService, render DigestRenderer) *DigestService {
+	return &DigestService{
+		plantService:        plantService,
+		taskService:         taskService,
+		harvestService:      harvestService,
+		notificationService: notificationService,
+		render:              render,
+	}
+}
+
+// BuildDigest puts together the user's digest as of the given time. Journal
+// entries go back over the user's digest period, one day for a daily digest
+// or when digests are off, so a preview still shows something.
+func (s *DigestService) BuildDigest(user types.User, asOf time.Time) (*types.Digest, error) {
+	digest := &types.Digest{
+		UserName:  user.Name,
+		Frequency: user.DigestFrequency,
+		AsOf:      asOf,
+		Since:     asOf.AddDate(0, 0, -user.DigestFrequency.Days()),
+		BaseURL:   s.notificationService.BaseURL(),
+		Sections:  types.DigestSections,
+	}
+	if len(user.DigestSections) > 0 {
+		digest.Sections = nil
+		for _, raw := range user.DigestSections {
+			if section, err := types.ParseDigestSection(raw); err == nil {
+				digest.Sections = append(digest.Sections, section)
+			}
+		}
+	}
+
+	var err error
+	if digest.Has(types.DigestCare) {
+		if digest.Overdue, err = s.plantService.GetOverduePlants(asOf); err != nil {
+			return nil, err
+		}
+	}
+	if digest.Has(types.DigestHealth) {
+		if digest.HealthAlerts, err = s.plantService.GetOpenHealthAlerts(); err != nil {
+			return nil, err
+		}
+	}
+	if digest.Has(types.DigestTasks) {
+		if digest.Tasks, err = s.taskService.GetDueTasks(asOf.AddDate(0, 0, types.DigestTaskDays)); err != nil {
+			return nil, err
+		}
+	}
+	if digest.Has(types.DigestJournal) {
+		if digest.JournalEntries, err = s.plantService.GetRecentJournalEntries(digest.Since, digestJournalLimit); err != nil {
+			return nil, err
+		}
+	}
+	if digest.Has(types.DigestHarvests) {
+		if digest.Harvests, err = s.harvestService.GetYieldSince(asOf.AddDate(0, 0, -types.DigestHarvestDays)); err != nil {
+			return nil, err
+		}
+	}
+	return digest, nil
+}
+
+// GetDigestUsers lists the users who get the digest at the given frequency.
+func (s *DigestService) GetDigestUsers(frequency types.DigestFrequency) ([]types.User, error) {
+	users, err := s.notificationService.GetUsers()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching digest users: %w", err)
+	}
+
+	var recipients []types.User
+	for _, user := range users {
+		if user.DigestFrequency == frequency {
+			recipients = append(recipients, user)
+		}
+	}
+	return recipients, nil
+}
+
+// SendDigest sends the user their digest as of the given time. A digest
+// with nothing in it is not sent unless force is set. It returns how many
+// channels the digest was queued for.
+func (s *DigestService) SendDigest(ctx context.Context, user types.User, asOf time.Time, force bool) (int, error) {
+	digest, err := s.BuildDigest(user, asOf)
+	if err != nil {
+		return 0, err
+	}
+	if digest.IsEmpty() && !force {
+		return 0, nil
+	}
+
+	msg, err := s.render(ctx, *digest)
+	if err != nil {
+		return 0, err
+	}
+	msg.Event = string(types.NotifyDigest)
+	msg.URL = s.notificationService.Link("/")
+	return s.notificationService.NotifyUser(ctx, user.ID, types.NotifyDigest, msg)
+}
+
+// SendDigests sends the digest to everyone who gets it at the given
+// frequency, and returns how many users it went to.
+func (s *DigestService) SendDigests(ctx context.Context, frequency types.DigestFrequency, asOf time.Time) (int, error) {
+	users, err := s.GetDigestUsers(frequency)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, user := range users {
+		queued, err := s.SendDigest(ctx, user, asOf, false)
+		if err != nil {
+			return sent, fmt.Errorf("error sending digest to %s: %w", user.Name, err)
+		}
+		if queued > 0 {
+			sent++
+		}
+	}
+	return sent, nil
+}
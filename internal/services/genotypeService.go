@@ -0,0 +1,248 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pepper-analytics-ai/internal/genetics"
+	"pepper-analytics-ai/internal/types"
+)
+
+var (
+	ErrGenotypeNotFound = errors.New("genotype call not found")
+	ErrUnknownLocus     = errors.New("unknown locus")
+)
+
+type GenotypeService struct {
+	db *sqlx.DB
+}
+
+func NewGenotypeService(db *sqlx.DB) *GenotypeService {
+	return &GenotypeService{db: db}
+}
+
+func (s *GenotypeService) RecordCall(call *types.GenotypeCall) error {
+	if _, ok := types.FindLocus(call.Locus); !ok {
+		return ErrUnknownLocus
+	}
+
+	query := `
+        INSERT INTO plant_genotypes (plant_id, locus, genotype, source, called_at, notes)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at, updated_at
+    `
+	err := s.db.QueryRow(
+		query,
+		call.PlantID,
+		call.Locus,
+		call.Genotype,
+		call.Source,
+		call.CalledAt,
+		call.Notes,
+	).Scan(&call.ID, &call.CreatedAt, &call.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error recording genotype call: %w", err)
+	}
+	return nil
+}
+
+func (s *GenotypeService) DeleteCall(plantID, id int) error {
+	query := `
+        UPDATE plant_genotypes
+        SET deleted_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND plant_id = $2 AND deleted_at IS NULL
+    `
+	result, err := s.db.Exec(query, id, plantID)
+	if err != nil {
+		return fmt.Errorf("error deleting genotype call: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrGenotypeNotFound
+	}
+	return nil
+}
+
+// GetPlantGenotypes describes a plant at each of the known loci, including
+// what its parents and offspring imply.
+func (s *GenotypeService) GetPlantGenotypes(plantID int) ([]types.LocusGenotype, error) {
+	query := `
+        SELECT id, name, seed_parent_id, pollen_parent_id
+        FROM plants
+        WHERE id = $1
+        OR seed_parent_id = $1
+        OR pollen_parent_id = $1
+        OR id = (SELECT seed_parent_id FROM plants WHERE id = $1)
+        OR id = (SELECT pollen_parent_id FROM plants WHERE id = $1)
+    `
+	var relatives []struct {
+		ID             int           `db:"id"`
+		Name           string        `db:"name"`
+		SeedParentID   sql.NullInt64 `db:"seed_parent_id"`
+		PollenParentID sql.NullInt64 `db:"pollen_parent_id"`
+	}
+	if err := s.db.Select(&relatives, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching relatives: %w", err)
+	}
+
+	ids := make([]int, 0, len(relatives))
+	names := make(map[int]string, len(relatives))
+	var parentIDs []int
+	var offspringIDs []int
+	found := false
+	for _, relative := range relatives {
+		ids = append(ids, relative.ID)
+		names[relative.ID] = relative.Name
+		if relative.ID == plantID {
+			found = true
+			if relative.SeedParentID.Valid && relative.PollenParentID.Valid {
+				parentIDs = []int{int(relative.SeedParentID.Int64), int(relative.PollenParentID.Int64)}
+			} else if relative.SeedParentID.Valid {
+				parentIDs = []int{int(relative.SeedParentID.Int64)}
+			} else if relative.PollenParentID.Valid {
+				parentIDs = []int{int(relative.PollenParentID.Int64)}
+			}
+			continue
+		}
+		if int(relative.SeedParentID.Int64) == plantID || int(relative.PollenParentID.Int64) == plantID {
+			offspringIDs = append(offspringIDs, relative.ID)
+		}
+	}
+	if !found {
+		return nil, ErrPlantNotFound
+	}
+
+	calls, err := s.getLatestCalls(ids)
+	if err != nil {
+		return nil, err
+	}
+	phenotypes, err := s.getPhenotypeGenotypes(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// known prefers a definite call over what the phenotype shows.
+	known := func(id int, locus string) types.Genotype {
+		if call, ok := calls[id][locus]; ok && call.Genotype != types.GenotypeUnknown {
+			return call.Genotype
+		}
+		if genotype, ok := phenotypes[id][locus]; ok {
+			return genotype
+		}
+		return types.GenotypeUnknown
+	}
+
+	genotypes := make([]types.LocusGenotype, 0, len(types.KnownLoci))
+	for _, locus := range types.KnownLoci {
+		genotype := types.LocusGenotype{
+			Locus:     locus,
+			Phenotype: types.GenotypeUnknown,
+			Inferred:  types.GenotypeUnknown,
+		}
+		if phenotype, ok := phenotypes[plantID][locus.Symbol]; ok {
+			genotype.Phenotype = phenotype
+		}
+		if call, ok := calls[plantID][locus.Symbol]; ok {
+			genotype.Call = &call
+		}
+
+		parents := make([]genetics.Relative, 0, len(parentIDs))
+		for _, id := range parentIDs {
+			parents = append(parents, genetics.Relative{Name: names[id], Genotype: known(id, locus.Symbol)})
+		}
+		offspring := make([]genetics.Relative, 0, len(offspringIDs))
+		for _, id := range offspringIDs {
+			offspring = append(offspring, genetics.Relative{Name: names[id], Genotype: known(id, locus.Symbol)})
+		}
+
+		genotype.Inferred, genotype.Reason, genotype.Conflict = genetics.InferFromRelatives(
+			locus, known(plantID, locus.Symbol), parents, offspring)
+		genotypes = append(genotypes, genotype)
+	}
+	return genotypes, nil
+}
+
+// GetEffectiveGenotypes returns the best available genotype of a plant at
+// each known locus, keyed by locus symbol.
+func (s *GenotypeService) GetEffectiveGenotypes(plantID int) (map[string]types.Genotype, error) {
+	genotypes, err := s.GetPlantGenotypes(plantID)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := make(map[string]types.Genotype, len(genotypes))
+	for _, genotype := range genotypes {
+		effective[genotype.Locus.Symbol] = genotype.Effective()
+	}
+	return effective, nil
+}
+
+// getLatestCalls returns the current call per plant and locus.
+func (s *GenotypeService) getLatestCalls(plantIDs []int) (map[int]map[string]types.GenotypeCall, error) {
+	query := `
+        SELECT DISTINCT ON (plant_id, locus) *
+        FROM plant_genotypes
+        WHERE plant_id = ANY($1) AND deleted_at IS NULL
+        ORDER BY plant_id, locus, called_at DESC, id DESC
+    `
+	var rows []types.GenotypeCall
+	if err := s.db.Select(&rows, query, pq.Array(plantIDs)); err != nil {
+		return nil, fmt.Errorf("error fetching genotype calls: %w", err)
+	}
+
+	calls := make(map[int]map[string]types.GenotypeCall)
+	for _, call := range rows {
+		if calls[call.PlantID] == nil {
+			calls[call.PlantID] = make(map[string]types.GenotypeCall)
+		}
+		calls[call.PlantID][call.Locus] = call
+	}
+	return calls, nil
+}
+
+// getPhenotypeGenotypes reads the known loci from the plants' latest
+// scores of the traits that show them.
+func (s *GenotypeService) getPhenotypeGenotypes(plantIDs []int) (map[int]map[string]types.Genotype, error) {
+	codes := make([]string, 0, len(types.KnownLoci))
+	for _, locus := range types.KnownLoci {
+		codes = append(codes, locus.TraitCode)
+	}
+
+	query := `
+        SELECT DISTINCT ON (ts.plant_id, ts.trait_id)
+               ts.plant_id, ts.trait_id, ts.state_value, td.code as trait_code
+        FROM trait_scores ts
+        JOIN trait_definitions td ON ts.trait_id = td.id
+        WHERE ts.plant_id = ANY($1)
+        AND td.code = ANY($2)
+        AND ts.deleted_at IS NULL
+        AND td.deleted_at IS NULL
+        ORDER BY ts.plant_id, ts.trait_id, ts.observed_date DESC, ts.id DESC
+    `
+	var scores []types.TraitScore
+	if err := s.db.Select(&scores, query, pq.Array(plantIDs), pq.Array(codes)); err != nil {
+		return nil, fmt.Errorf("error fetching phenotype scores: %w", err)
+	}
+
+	byPlant := make(map[int][]types.TraitScore)
+	for _, score := range scores {
+		byPlant[score.PlantID] = append(byPlant[score.PlantID], score)
+	}
+
+	genotypes := make(map[int]map[string]types.Genotype, len(byPlant))
+	for plantID, plantScores := range byPlant {
+		genotypes[plantID] = make(map[string]types.Genotype, len(types.KnownLoci))
+		for _, locus := range types.KnownLoci {
+			if genotype := genetics.InferGenotype(locus, plantScores); genotype != types.GenotypeUnknown {
+				genotypes[plantID][locus.Symbol] = genotype
+			}
+		}
+	}
+	return genotypes, nil
+}
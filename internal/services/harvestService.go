@@ -0,0 +1,175 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pepper-analytics-ai/internal/types"
+	"time"
+)
+
+var (
+	ErrHarvestNotFound = errors.New("harvest not found")
+)
+
+type HarvestService struct {
+	db *sqlx.DB
+}
+
+func NewHarvestService(db *sqlx.DB) *HarvestService {
+	return &HarvestService{db: db}
+}
+
+func (s *HarvestService) GetPlantHarvests(plantID int) ([]types.Harvest, error) {
+	query := `
+        SELECT *
+        FROM harvests
+        WHERE plant_id = $1 AND deleted_at IS NULL
+        ORDER BY harvest_date DESC, id DESC
+    `
+	var harvests []types.Harvest
+	if err := s.db.Select(&harvests, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching harvests: %w", err)
+	}
+	return harvests, nil
+}
+
+// CreateHarvest logs a picking. When seeds were saved from the pods a seed
+// lot is created for them in the same transaction.
+func (s *HarvestService) CreateHarvest(harvest *types.Harvest, savedSeeds int) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO harvests (
+            plant_id, harvest_date, pod_count, total_weight_grams,
+            quality_grade, image_path, notes
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id, created_at, updated_at
+    `
+	err = tx.QueryRow(
+		query,
+		harvest.PlantID,
+		harvest.HarvestDate,
+		harvest.PodCount,
+		harvest.TotalWeightGrams,
+		harvest.QualityGrade,
+		harvest.ImagePath,
+		harvest.Notes,
+	).Scan(&harvest.ID, &harvest.CreatedAt, &harvest.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating harvest: %w", err)
+	}
+
+	if savedSeeds > 0 {
+		lot := &types.SeedLot{
+			PlantID:     harvest.PlantID,
+			SeedCount:   savedSeeds,
+			HarvestDate: harvest.HarvestDate,
+			HarvestID:   sql.NullInt64{Int64: int64(harvest.ID), Valid: true},
+		}
+		if err := insertSeedLot(tx, lot); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *HarvestService) DeleteHarvest(plantID, harvestID int) error {
+	query := `
+        UPDATE harvests
+        SET deleted_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND plant_id = $2 AND deleted_at IS NULL
+    `
+	result, err := s.db.Exec(query, harvestID, plantID)
+	if err != nil {
+		return fmt.Errorf("error deleting harvest: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrHarvestNotFound
+	}
+	return nil
+}
+
+func (s *HarvestService) GetPlantYield(plantID int) (*types.YieldTotals, error) {
+	query := `
+        SELECT COUNT(*) as harvest_count,
+               COALESCE(SUM(pod_count), 0) as pod_count,
+               COALESCE(SUM(total_weight_grams), 0) as total_weight_grams
+        FROM harvests
+        WHERE plant_id = $1 AND deleted_at IS NULL
+    `
+	var totals types.YieldTotals
+	if err := s.db.Get(&totals, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching plant yield: %w", err)
+	}
+	return &totals, nil
+}
+
+// GetVarietyYield sums the harvests of every plant grown from a variety.
+func (s *HarvestService) GetVarietyYield(varietyID int) (*types.YieldTotals, error) {
+	return s.varietyYield(`p.variety_id = $1`, varietyID)
+}
+
+// GetPlantVarietyYield sums the harvests of every plant of the given plant's
+// variety. A plant without a variety only counts its own harvests.
+func (s *HarvestService) GetPlantVarietyYield(plantID int) (*types.YieldTotals, error) {
+	return s.varietyYield(`EXISTS (
+            SELECT 1 FROM plants t
+            WHERE t.id = $1
+            AND (p.variety_id = t.variety_id OR (t.variety_id IS NULL AND p.id = t.id))
+        )`, plantID)
+}
+
+// varietyYield sums the harvests of the living plants matching plantFilter,
+// a condition on plants p with one argument.
+func (s *HarvestService) varietyYield(plantFilter string, arg int) (*types.YieldTotals, error) {
+	query := `
+        SELECT COUNT(h.id) as harvest_count,
+               COALESCE(SUM(h.pod_count), 0) as pod_count,
+               COALESCE(SUM(h.total_weight_grams), 0) as total_weight_grams
+        FROM harvests h
+        JOIN plants p ON h.plant_id = p.id
+        WHERE ` + plantFilter + `
+        AND p.deleted_at IS NULL
+        AND h.deleted_at IS NULL
+    `
+	var totals types.YieldTotals
+	if err := s.db.Get(&totals, query, arg); err != nil {
+		return nil, fmt.Errorf("error fetching variety yield: %w", err)
+	}
+	return &totals, nil
+}
+
+// GetYieldSince sums each plant's harvests from the given day on, biggest
+// pickings first.
+func (s *HarvestService) GetYieldSince(since time.Time) ([]types.PlantYield, error) {
+	query := `
+        SELECT p.id as plant_id, p.name as plant_name,
+               COUNT(h.id) as harvest_count,
+               COALESCE(SUM(h.pod_count), 0) as pod_count,
+               COALESCE(SUM(h.total_weight_grams), 0) as total_weight_grams
+        FROM harvests h
+        JOIN plants p ON h.plant_id = p.id
+        WHERE h.harvest_date >= $1::date
+        AND h.deleted_at IS NULL
+        AND p.deleted_at IS NULL
+        GROUP BY p.id, p.name
+        ORDER BY total_weight_grams DESC, pod_count DESC, p.name
+    `
+	var yields []types.PlantYield
+	if err := s.db.Select(&yields, query, since); err != nil {
+		return nil, fmt.Errorf("error fetching recent yield: %w", err)
+	}
+	return yields, nil
+}
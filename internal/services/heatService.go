@@ -0,0 +1,204 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"math"
+	"pepper-analytics-ai/internal/types"
+)
+
+var (
+	ErrHeatTestNotFound = errors.New("heat test not found")
+)
+
+type HeatService struct {
+	db *sqlx.DB
+}
+
+func NewHeatService(db *sqlx.DB) *HeatService {
+	return &HeatService{db: db}
+}
+
+// heatSHUExpr is a test's lab result in SHU, converted from capsaicinoid
+// content when no SHU figure was given.
+var heatSHUExpr = fmt.Sprintf("COALESCE(ht.shu, ht.capsaicinoids_mg_g * %d)", types.SHUPerCapsaicinoidMg)
+
+func (s *HeatService) GetPlantHeatTests(plantID int) ([]types.HeatTest, error) {
+	query := `
+        SELECT ht.*, h.harvest_date
+        FROM heat_tests ht
+        LEFT JOIN harvests h ON ht.harvest_id = h.id
+        WHERE ht.plant_id = $1 AND ht.deleted_at IS NULL
+        ORDER BY ht.tested_at DESC, ht.id DESC
+    `
+	var tests []types.HeatTest
+	if err := s.db.Select(&tests, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching heat tests: %w", err)
+	}
+	return tests, nil
+}
+
+func (s *HeatService) GetPlantHeatSummary(plantID int) (*types.HeatSummary, error) {
+	query := `
+        SELECT COUNT(*) as test_count,
+               AVG(ht.panel_score) as average_score,
+               MIN(ht.panel_score) as min_score,
+               MAX(ht.panel_score) as max_score,
+               AVG(` + heatSHUExpr + `) as average_shu,
+               COUNT(` + heatSHUExpr + `) as lab_count
+        FROM heat_tests ht
+        WHERE ht.plant_id = $1 AND ht.deleted_at IS NULL
+    `
+	var summary types.HeatSummary
+	if err := s.db.Get(&summary, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching heat summary: %w", err)
+	}
+	return &summary, nil
+}
+
+// CreateHeatTest records a tasting. A linked harvest must be one of the
+// plant's own.
+func (s *HeatService) CreateHeatTest(test *types.HeatTest) error {
+	if test.HarvestID.Valid {
+		var exists bool
+		err := s.db.Get(&exists, `
+            SELECT EXISTS (
+                SELECT 1 FROM harvests
+                WHERE id = $1 AND plant_id = $2 AND deleted_at IS NULL
+            )
+        `, test.HarvestID, test.PlantID)
+		if err != nil {
+			return fmt.Errorf("error checking heat test harvest: %w", err)
+		}
+		if !exists {
+			return ErrHarvestNotFound
+		}
+	}
+
+	query := `
+        INSERT INTO heat_tests (
+            plant_id, harvest_id, tested_at, panel_score, shu,
+            capsaicinoids_mg_g, tester, ripeness, notes
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING id, created_at, updated_at
+    `
+	err := s.db.QueryRow(
+		query,
+		test.PlantID,
+		test.HarvestID,
+		test.TestedAt,
+		test.PanelScore,
+		test.SHU,
+		test.Capsaicinoids,
+		test.Tester,
+		test.Ripeness,
+		test.Notes,
+	).Scan(&test.ID, &test.CreatedAt, &test.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating heat test: %w", err)
+	}
+	return nil
+}
+
+func (s *HeatService) DeleteHeatTest(plantID, id int) error {
+	query := `
+        UPDATE heat_tests
+        SET deleted_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND plant_id = $2 AND deleted_at IS NULL
+    `
+	result, err := s.db.Exec(query, id, plantID)
+	if err != nil {
+		return fmt.Errorf("error deleting heat test: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrHeatTestNotFound
+	}
+	return nil
+}
+
+// GetSiblingDistribution groups the tested plants of a generation into
+// sibling families by their parents and counts them by average panel
+// score. Reciprocal crosses count as one family.
+func (s *HeatService) GetSiblingDistribution(generation string) ([]types.HeatDistribution, error) {
+	query := `
+        SELECT p.seed_parent_id,
+               p.pollen_parent_id,
+               sp.name as seed_parent_name,
+               pp.name as pollen_parent_name,
+               AVG(ht.panel_score) as average_score
+        FROM plants p
+        JOIN heat_tests ht ON ht.plant_id = p.id AND ht.deleted_at IS NULL
+        LEFT JOIN plants sp ON p.seed_parent_id = sp.id
+        LEFT JOIN plants pp ON p.pollen_parent_id = pp.id
+        WHERE p.generation = $1 AND p.deleted_at IS NULL
+        GROUP BY p.id, sp.name, pp.name
+        ORDER BY sp.name, pp.name
+    `
+	var rows []struct {
+		SeedParentID     sql.NullInt64  `db:"seed_parent_id"`
+		PollenParentID   sql.NullInt64  `db:"pollen_parent_id"`
+		SeedParentName   sql.NullString `db:"seed_parent_name"`
+		PollenParentName sql.NullString `db:"pollen_parent_name"`
+		AverageScore     float64        `db:"average_score"`
+	}
+	if err := s.db.Select(&rows, query, generation); err != nil {
+		return nil, fmt.Errorf("error fetching sibling heat: %w", err)
+	}
+
+	var distributions []types.HeatDistribution
+	index := make(map[[2]int64]int)
+	sums := make(map[int]float64)
+	for _, row := range rows {
+		a, b := row.SeedParentID.Int64, row.PollenParentID.Int64
+		nameA, nameB := row.SeedParentName, row.PollenParentName
+		if b != 0 && (a == 0 || b < a) {
+			a, b = b, a
+			nameA, nameB = nameB, nameA
+		}
+
+		key := [2]int64{a, b}
+		i, ok := index[key]
+		if !ok {
+			i = len(distributions)
+			index[key] = i
+			distributions = append(distributions, types.HeatDistribution{
+				Label:  siblingFamilyLabel(a, b, nameA, nameB),
+				Counts: make([]int, len(types.HeatScale)),
+				Min:    row.AverageScore,
+				Max:    row.AverageScore,
+			})
+		}
+
+		d := &distributions[i]
+		d.Plants++
+		d.Counts[int(math.Round(row.AverageScore))]++
+		d.Min = min(d.Min, row.AverageScore)
+		d.Max = max(d.Max, row.AverageScore)
+		sums[i] += row.AverageScore
+	}
+
+	for i := range distributions {
+		distributions[i].Mean = sums[i] / float64(distributions[i].Plants)
+	}
+	return distributions, nil
+}
+
+func siblingFamilyLabel(a, b int64, nameA, nameB sql.NullString) string {
+	switch {
+	case a == 0:
+		return "Unknown parents"
+	case b == 0:
+		return nameA.String + " x unknown"
+	case a == b:
+		return nameA.String + " (self)"
+	default:
+		return nameA.String + " x " + nameB.String
+	}
+}
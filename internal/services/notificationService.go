@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+	"net/http"
+	"pepper-analytics-ai/internal/notify"
+	"pepper-analytics-ai/internal/types"
+	"time"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrChannelNotFound = errors.New("notification channel not found")
+)
+
+// maxDeliveryAttempts is how often a delivery is tried before it is given
+// up on. It stays in the log with its last error.
+const maxDeliveryAttempts = 5
+
+// deliveryClaimTimeout is how long a delivery claimed by DeliverPending is
+// held back from other runs. A claim left behind by a run that died is
+// taken over once it has timed out.
+const deliveryClaimTimeout = 10 * time.Minute
+
+// NotificationService keeps the users, their channels and the outbox of
+// messages to them. Messages are queued per channel and sent by
+// DeliverPending, which holds them back during a user's quiet hours.
+type NotificationService struct {
+	db     *sqlx.DB
+	config notify.Config
+	client *http.Client
+}
+
+func NewNotificationService(db *sqlx.DB, config notify.Config) *NotificationService {
+	return &NotificationService{
+		db:     db,
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Link turns an app path into an absolute URL for a message, or "" when the
+// app's URL is not configured.
+func (s *NotificationService) Link(path string) string {
+	return s.config.Link(path)
+}
+
+// BaseURL is where the app is reachable, or "" when it is not configured.
+func (s *NotificationService) BaseURL() string {
+	return s.config.BaseURL
+}
+
+func (s *NotificationService) GetUser(id int) (*types.User, error) {
+	var user types.User
+	err := s.db.Get(&user, `SELECT * FROM users WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("error fetching user: %w", err)
+	}
+	return &user, nil
+}
+
+func (s *NotificationService) GetUsers() ([]types.User, error) {
+	var users []types.User
+	err := s.db.Select(&users, `SELECT * FROM users WHERE deleted_at IS NULL ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching users: %w", err)
+	}
+	return users, nil
+}
+
+func (s *NotificationService) CreateUser(user *types.User) error {
+	query := `
+        INSERT INTO users (name, quiet_start, quiet_end, timezone)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at, updated_at
+    `
+	err := s.db.QueryRow(query, user.Name, user.Start, user.End, user.Timezone).
+		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating user: %w", err)
+	}
+	return nil
+}
+
+// SetQuietHours replaces a user's quiet window. Unset start and end times
+// turn quiet hours off.
+func (s *NotificationService) SetQuietHours(userID int, quiet types.QuietHours) error {
+	query := `
+        UPDATE users
+        SET quiet_start = $1, quiet_end = $2, timezone = $3, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $4 AND deleted_at IS NULL
+    `
+	result, err := s.db.Exec(query, quiet.Start, quiet.End, quiet.Timezone, userID)
+	if err != nil {
+		return fmt.Errorf("error updating quiet hours: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// SetDigest sets how often a user gets the digest and which sections it
+// has. No sections means all of them.
+func (s *NotificationService) SetDigest(userID int, frequency types.DigestFrequency, sections []string) error {
+	query := `
+        UPDATE users
+        SET digest_frequency = $1, digest_sections = $2, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $3 AND deleted_at IS NULL
+    `
+	result, err := s.db.Exec(query, frequency, pq.StringArray(sections), userID)
+	if err != nil {
+		return fmt.Errorf("error updating digest settings: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteUser removes the user and switches off their channels.
+func (s *NotificationService) DeleteUser(id int) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+
+	if _, err := tx.Exec(`UPDATE notification_channels SET enabled = false WHERE user_id = $1`, id); err != nil {
+		return fmt.Errorf("error disabling channels: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetChannels lists the channels of all live users.
+func (s *NotificationService) GetChannels() ([]types.NotificationChannel, error) {
+	query := `
+        SELECT nc.*
+        FROM notification_channels nc
+        JOIN users u ON nc.user_id = u.id AND u.deleted_at IS NULL
+        ORDER BY nc.user_id, nc.kind, nc.id
+    `
+	var channels []types.NotificationChannel
+	if err := s.db.Select(&channels, query); err != nil {
+		return nil, fmt.Errorf("error fetching notification channels: %w", err)
+	}
+	return channels, nil
+}
+
+// CreateChannel adds a channel for a user after checking its driver can be
+// built from the target, so a typo shows up now rather than at send time.
+func (s *NotificationService) CreateChannel(channel *types.NotificationChannel) error {
+	if _, err := notify.New(channel.Kind, channel.Target, channel.Token.String, s.config, s.client); err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO notification_channels (user_id, kind, target, token, events)
+        SELECT u.id, $2, $3, $4, $5
+        FROM users u
+        WHERE u.id = $1 AND u.deleted_at IS NULL
+        RETURNING id, enabled, created_at
+    `
+	err := s.db.QueryRow(
+		query,
+		channel.UserID,
+		channel.Kind,
+		channel.Target,
+		channel.Token,
+		channel.Events,
+	).Scan(&channel.ID, &channel.Enabled, &channel.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("error creating notification channel: %w", err)
+	}
+	return nil
+}
+
+func (s *NotificationService) SetChannelEnabled(id int, enabled bool) error {
+	result, err := s.db.Exec(`UPDATE notification_channels SET enabled = $1 WHERE id = $2`, enabled, id)
+	if err != nil {
+		return fmt.Errorf("error updating notification channel: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrChannelNotFound
+	}
+	return nil
+}
+
+// DeleteChannel removes a channel along with its delivery log.
+func (s *NotificationService) DeleteChannel(id int) error {
+	result, err := s.db.Exec(`DELETE FROM notification_channels WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting notification channel: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrChannelNotFound
+	}
+	return nil
+}
+
+// Notify queues the message for every enabled channel that wants the event
+// and sends what it can straight away. It returns how many channels the
+// message was queued for.
+func (s *NotificationService) Notify(ctx context.Context, event types.NotificationEvent, msg notify.Message) (int, error) {
+	return s.queue(ctx, 0, event, msg)
+}
+
+// NotifyUser is Notify for the channels of one user.
+func (s *NotificationService) NotifyUser(ctx context.Context, userID int, event types.NotificationEvent, msg notify.Message) (int, error) {
+	return s.queue(ctx, userID, event, msg)
+}
+
+// queue adds deliveries for the channels of userID, or of everyone when it
+// is 0, and then sends them.
+func (s *NotificationService) queue(ctx context.Context, userID int, event types.NotificationEvent, msg notify.Message) (int, error) {
+	query := `
+        INSERT INTO notification_deliveries (channel_id, event, title, body, html, url)
+        SELECT nc.id, $1::text, $2, $3, NULLIF($4, ''), NULLIF($5, '')
+        FROM notification_channels nc
+        JOIN users u ON nc.user_id = u.id AND u.deleted_at IS NULL
+        WHERE nc.enabled
+        AND (cardinality(nc.events) = 0 OR $1::text = ANY(nc.events))
+        AND ($6 = 0 OR u.id = $6)
+    `
+	result, err := s.db.Exec(query, event, msg.Title, msg.Body, msg.HTML, msg.URL, userID)
+	if err != nil {
+		return 0, fmt.Errorf("error queueing notification: %w", err)
+	}
+	queued, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if queued > 0 {
+		if _, err := s.DeliverPending(ctx, time.Now()); err != nil {
+			return int(queued), err
+		}
+	}
+	return int(queued), nil
+}
+
+// pendingDelivery is a queued message with what is needed to send it.
+type pendingDelivery struct {
+	types.NotificationDelivery
+	types.QuietHours
+	Token sql.NullString `db:"channel_token"`
+}
+
+// DeliverPending sends the queued messages of users outside their quiet
+// hours at now. Failed sends stay queued for the next run until they reach
+// maxDeliveryAttempts. It returns how many messages went out.
+//
+// Deliveries are claimed before they are sent, so runs that overlap, such
+// as the scheduled job and a message queued from a request, never send the
+// same one twice.
+func (s *NotificationService) DeliverPending(ctx context.Context, now time.Time) (int, error) {
+	query := `
+        WITH claimed AS (
+            UPDATE notification_deliveries
+            SET claimed_at = CURRENT_TIMESTAMP
+            WHERE id IN (
+                SELECT d.id
+                FROM notification_deliveries d
+                JOIN notification_channels nc ON d.channel_id = nc.id AND nc.enabled
+                JOIN users u ON nc.user_id = u.id AND u.deleted_at IS NULL
+                WHERE d.sent_at IS NULL AND d.attempts < $1
+                AND (d.claimed_at IS NULL OR d.claimed_at < CURRENT_TIMESTAMP - $2 * INTERVAL '1 second')
+                FOR UPDATE OF d SKIP LOCKED
+            )
+            RETURNING *
+        )
+        SELECT d.*, nc.kind as channel_kind, nc.target as channel_target, nc.token as channel_token,
+               u.name as user_name, u.quiet_start, u.quiet_end, u.timezone
+        FROM claimed d
+        JOIN notification_channels nc ON d.channel_id = nc.id
+        JOIN users u ON nc.user_id = u.id
+        ORDER BY d.created_at, d.id
+    `
+	var pending []pendingDelivery
+	if err := s.db.Select(&pending, query, maxDeliveryAttempts, deliveryClaimTimeout.Seconds()); err != nil {
+		return 0, fmt.Errorf("error claiming pending notifications: %w", err)
+	}
+
+	// Whatever is not sent below goes back in the queue for the next run
+	var unsent []int64
+	defer func() {
+		if len(unsent) == 0 {
+			return
+		}
+		query := `UPDATE notification_deliveries SET claimed_at = NULL WHERE id = ANY($1)`
+		if _, err := s.db.Exec(query, pq.Array(unsent)); err != nil {
+			log.Printf("Warning: Error releasing notification claims: %v", err)
+		}
+	}()
+
+	sent := 0
+	for i, delivery := range pending {
+		if err := ctx.Err(); err != nil {
+			for _, rest := range pending[i:] {
+				unsent = append(unsent, int64(rest.ID))
+			}
+			return sent, err
+		}
+		if delivery.QuietHours.Contains(now) {
+			unsent = append(unsent, int64(delivery.ID))
+			continue
+		}
+
+		err := s.send(ctx, delivery.ChannelKind, delivery.ChannelTarget, delivery.Token.String, notify.Message{
+			Event: string(delivery.Event),
+			Title: delivery.Title,
+			Body:  delivery.Body,
+			HTML:  delivery.HTML.String,
+			URL:   delivery.URL.String,
+		})
+		if err != nil {
+			log.Printf("Warning: Error sending %s notification to %s: %v", delivery.ChannelKind, delivery.UserName, err)
+			query := `UPDATE notification_deliveries SET attempts = attempts + 1, last_error = $1, claimed_at = NULL WHERE id = $2`
+			if _, err := s.db.Exec(query, err.Error(), delivery.ID); err != nil {
+				return sent, fmt.Errorf("error recording failed notification: %w", err)
+			}
+			continue
+		}
+
+		query := `UPDATE notification_deliveries SET attempts = attempts + 1, last_error = NULL, sent_at = CURRENT_TIMESTAMP WHERE id = $1`
+		if _, err := s.db.Exec(query, delivery.ID); err != nil {
+			return sent, fmt.Errorf("error recording sent notification: %w", err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// SendTest sends a test message over one channel right away, ignoring quiet
+// hours, and returns the driver's error if it fails.
+func (s *NotificationService) SendTest(ctx context.Context, channelID int) error {
+	var channel types.NotificationChannel
+	err := s.db.Get(&channel, `SELECT * FROM notification_channels WHERE id = $1`, channelID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrChannelNotFound
+		}
+		return fmt.Errorf("error fetching notification channel: %w", err)
+	}
+
+	return s.send(ctx, channel.Kind, channel.Target, channel.Token.String, notify.Message{
+		Title: "Test notification",
+		Body:  "This channel is set up to receive notifications from Pepper Analytics.",
+		URL:   s.Link("/notifications"),
+	})
+}
+
+func (s *NotificationService) send(ctx context.Context, kind types.ChannelKind, target, token string, msg notify.Message) error {
+	notifier, err := notify.New(kind, target, token, s.config, s.client)
+	if err != nil {
+		return err
+	}
+	return notifier.Send(ctx, msg)
+}
+
+// GetRecentDeliveries lists the newest queued and sent messages.
+func (s *NotificationService) GetRecentDeliveries(limit int) ([]types.NotificationDelivery, error) {
+	query := `
+        SELECT d.*, nc.kind as channel_kind, nc.target as channel_target, u.name as user_name
+        FROM notification_deliveries d
+        JOIN notification_channels nc ON d.channel_id = nc.id
+        JOIN users u ON nc.user_id = u.id
+        ORDER BY d.created_at DESC, d.id DESC
+        LIMIT $1
+    `
+	var deliveries []types.NotificationDelivery
+	if err := s.db.Select(&deliveries, query, limit); err != nil {
+		return nil, fmt.Errorf("error fetching notification deliveries: %w", err)
+	}
+	return deliveries, nil
+}
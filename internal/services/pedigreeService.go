@@ -0,0 +1,104 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pepper-analytics-ai/internal/pedigree"
+	"pepper-analytics-ai/internal/types"
+)
+
+// PedigreeService computes inbreeding and relatedness from the recorded
+// seed and pollen parents.
+type PedigreeService struct {
+	db *sqlx.DB
+}
+
+func NewPedigreeService(db *sqlx.DB) *PedigreeService {
+	return &PedigreeService{db: db}
+}
+
+// NewCalculator loads the ancestry of the given plants and returns a
+// calculator over it. Parents beyond maxPedigreeDepth generations are
+// treated as unknown.
+func (s *PedigreeService) NewCalculator(plantIDs ...int) (*pedigree.Calculator, error) {
+	query := `
+        WITH RECURSIVE ancestry(id, depth) AS (
+            SELECT id, 0
+            FROM plants
+            WHERE id = ANY($1)
+            UNION
+            SELECT unnest(ARRAY[p.seed_parent_id, p.pollen_parent_id]), a.depth + 1
+            FROM plants p
+            JOIN ancestry a ON p.id = a.id
+            WHERE a.depth < $2
+        )
+        SELECT id, seed_parent_id, pollen_parent_id
+        FROM plants
+        WHERE id IN (SELECT id FROM ancestry)
+    `
+	var rows []struct {
+		ID             int           `db:"id"`
+		SeedParentID   sql.NullInt64 `db:"seed_parent_id"`
+		PollenParentID sql.NullInt64 `db:"pollen_parent_id"`
+	}
+	if err := s.db.Select(&rows, query, pq.Array(plantIDs), maxPedigreeDepth); err != nil {
+		return nil, fmt.Errorf("error loading pedigree: %w", err)
+	}
+
+	parents := make(map[int]pedigree.Parents, len(rows))
+	for _, row := range rows {
+		parents[row.ID] = pedigree.Parents{
+			Seed:   int(row.SeedParentID.Int64),
+			Pollen: int(row.PollenParentID.Int64),
+		}
+	}
+	return pedigree.NewCalculator(parents), nil
+}
+
+// GetInbreeding returns Wright's inbreeding coefficient of a plant.
+func (s *PedigreeService) GetInbreeding(plantID int) (float64, error) {
+	calc, err := s.NewCalculator(plantID)
+	if err != nil {
+		return 0, err
+	}
+	return calc.Inbreeding(plantID), nil
+}
+
+// GetRelationship compares two plants, including how inbred their offspring
+// would be.
+func (s *PedigreeService) GetRelationship(plantAID, plantBID int) (*types.Relationship, error) {
+	query := `
+        SELECT id, name, species, generation
+        FROM plants
+        WHERE id = ANY($1)
+    `
+	var plants []types.PlantOption
+	if err := s.db.Select(&plants, query, pq.Array([]int{plantAID, plantBID})); err != nil {
+		return nil, fmt.Errorf("error fetching plants: %w", err)
+	}
+
+	relationship := &types.Relationship{}
+	for _, plant := range plants {
+		if plant.ID == plantAID {
+			relationship.PlantA = plant
+		}
+		if plant.ID == plantBID {
+			relationship.PlantB = plant
+		}
+	}
+	if relationship.PlantA.ID == 0 || relationship.PlantB.ID == 0 {
+		return nil, ErrPlantNotFound
+	}
+
+	calc, err := s.NewCalculator(plantAID, plantBID)
+	if err != nil {
+		return nil, err
+	}
+	relationship.InbreedingA = calc.Inbreeding(plantAID)
+	relationship.InbreedingB = calc.Inbreeding(plantBID)
+	relationship.Kinship = calc.Kinship(plantAID, plantBID)
+	relationship.Coefficient = calc.Relationship(plantAID, plantBID)
+	return relationship, nil
+}
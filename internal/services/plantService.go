@@ -2,10 +2,13 @@ package services
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"log"
 	"pepper-analytics-ai/internal/types"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -87,10 +90,16 @@ func (s *PlantService) GetPlant(id int) (*types.PlantWithDates, error) {
                lw.last_watered_at,
                lf.last_fertilized_at,
                p.is_cross,
-               p.generation
+               p.generation,
+               sp.name as seed_parent_name,
+               pp.name as pollen_parent_name,
+               v.name as variety_name
         FROM plants p
         LEFT JOIN LastWatering lw ON p.id = lw.plant_id
         LEFT JOIN LastFertilizing lf ON p.id = lf.plant_id
+        LEFT JOIN plants sp ON p.seed_parent_id = sp.id
+        LEFT JOIN plants pp ON p.pollen_parent_id = pp.id
+        LEFT JOIN varieties v ON p.variety_id = v.id AND v.deleted_at IS NULL
         WHERE p.id = $1 AND p.deleted_at IS NULL
     `
 	var plant types.PlantWithDates
@@ -105,11 +114,62 @@ func (s *PlantService) GetPlant(id int) (*types.PlantWithDates, error) {
 }
 
 func (s *PlantService) CreatePlant(plant *types.PlantWithDates) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertPlant(tx, plant); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SowPlant creates a plant from a seed lot, taking the seeds out of the lot
+// in the same transaction. The lot is recorded as the plant's origin.
+func (s *PlantService) SowPlant(plant *types.PlantWithDates, seedLotID int, seedsUsed int) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := consumeSeeds(tx, seedLotID, seedsUsed); err != nil {
+		return err
+	}
+
+	plant.SeedLotID = sql.NullInt64{Int64: int64(seedLotID), Valid: true}
+	if err := insertPlant(tx, plant); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// queryRower is satisfied by both *sqlx.DB and *sqlx.Tx.
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+type plantWriter interface {
+	queryRower
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertPlant adds a plant and records the stage it was entered at. A plant
+// entered as seed is dated from sowing, any later stage from today since
+// that is when it was seen at that stage.
+func insertPlant(q plantWriter, plant *types.PlantWithDates) error {
 	query := `
         INSERT INTO plants (
             name, species, health, growth_stage, planting_date, 
-            image_path, notes, is_cross, generation
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            image_path, notes, is_cross, generation,
+            seed_parent_id, pollen_parent_id, seed_parent_external, pollen_parent_external,
+            seed_lot_id, variety_id, germination_trial_id,
+            watering_interval_days, fertilizing_interval_days
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
         RETURNING id, created_at, updated_at
     `
 
@@ -118,7 +178,7 @@ func (s *PlantService) CreatePlant(plant *types.PlantWithDates) error {
 		plant.Generation = sql.NullString{}
 	}
 
-	return s.db.QueryRow(
+	err := q.QueryRow(
 		query,
 		plant.Name,
 		plant.Species,
@@ -129,11 +189,69 @@ func (s *PlantService) CreatePlant(plant *types.PlantWithDates) error {
 		plant.Notes,
 		plant.IsCross,
 		plant.Generation,
+		plant.SeedParentID,
+		plant.PollenParentID,
+		plant.SeedParentExt,
+		plant.PollenParentExt,
+		plant.SeedLotID,
+		plant.VarietyID,
+		plant.GerminationTrialID,
+		plant.WateringInterval,
+		plant.FertilizingInterval,
 	).Scan(&plant.ID, &plant.CreatedAt, &plant.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating plant: %w", err)
+	}
+
+	enteredOn := time.Now()
+	if plant.GrowthStage == types.GrowthStageSeed || plant.PlantingDate.After(enteredOn) {
+		enteredOn = plant.PlantingDate
+	}
+	if err := recordStageTransition(q, plant.ID, "", plant.GrowthStage, enteredOn, types.StageSourceCreated, ""); err != nil {
+		return err
+	}
+	return recordHealthChange(q, plant.ID, "", plant.Health, sql.NullInt64{})
 }
 
-func (s *PlantService) UpdatePlant(plant *types.PlantWithDates) error {
+// UpdatePlant saves the edit form. A changed growth stage is recorded as a
+// transition on stageChangedOn with stageReason, and a changed health in the
+// health history, with healthNote kept as a journal entry when given.
+func (s *PlantService) UpdatePlant(plant *types.PlantWithDates, stageChangedOn time.Time, stageReason, healthNote string) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous struct {
+		GrowthStage types.GrowthStage `db:"growth_stage"`
+		Health      types.PlantHealth `db:"health"`
+		LastChange  sql.NullTime      `db:"last_change"`
+	}
 	query := `
+        SELECT p.growth_stage, COALESCE(p.health, '') as health,
+               (SELECT MAX(t.transitioned_on) FROM plant_stage_transitions t WHERE t.plant_id = p.id) as last_change
+        FROM plants p
+        WHERE p.id = $1 AND p.deleted_at IS NULL
+        FOR UPDATE
+    `
+	err = tx.Get(&previous, query, plant.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrPlantNotFound
+		}
+		return fmt.Errorf("error fetching plant stage: %w", err)
+	}
+
+	// The edit may move the sow date too, so the change is checked against
+	// the new one
+	if plant.GrowthStage != previous.GrowthStage {
+		if err := checkStageDate(stageChangedOn, plant.PlantingDate, previous.LastChange); err != nil {
+			return err
+		}
+	}
+
+	query = `
         UPDATE plants 
         SET name = $1, 
             species = $2, 
@@ -144,11 +262,18 @@ func (s *PlantService) UpdatePlant(plant *types.PlantWithDates) error {
             planting_date = $7, 
             is_cross = $8,
             generation = $9,
+            seed_parent_id = $10,
+            pollen_parent_id = $11,
+            seed_parent_external = $12,
+            pollen_parent_external = $13,
+            variety_id = $14,
+            watering_interval_days = $15,
+            fertilizing_interval_days = $16,
             updated_at = CURRENT_TIMESTAMP
-        WHERE id = $10 AND deleted_at IS NULL
+        WHERE id = $17 AND deleted_at IS NULL
         RETURNING created_at, updated_at`
 
-	err := s.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		plant.Name,
 		plant.Species,
@@ -159,6 +284,13 @@ func (s *PlantService) UpdatePlant(plant *types.PlantWithDates) error {
 		plant.PlantingDate,
 		plant.IsCross,
 		plant.Generation,
+		plant.SeedParentID,
+		plant.PollenParentID,
+		plant.SeedParentExt,
+		plant.PollenParentExt,
+		plant.VarietyID,
+		plant.WateringInterval,
+		plant.FertilizingInterval,
 		plant.ID,
 	).Scan(&plant.CreatedAt, &plant.UpdatedAt)
 
@@ -169,7 +301,19 @@ func (s *PlantService) UpdatePlant(plant *types.PlantWithDates) error {
 		return err
 	}
 
-	return nil
+	if plant.GrowthStage != previous.GrowthStage {
+		if err := recordStageTransition(tx, plant.ID, previous.GrowthStage, plant.GrowthStage, stageChangedOn, types.StageSourceEdit, stageReason); err != nil {
+			return err
+		}
+	}
+
+	if plant.Health != previous.Health {
+		if err := changeHealth(tx, plant.ID, previous.Health, plant.Health, healthNote); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (s *PlantService) DeletePlant(id int) error {
@@ -205,7 +349,41 @@ func (s *PlantService) GetJournalEntries(plantID int) ([]types.JournalEntry, err
 	}
 	return entries, nil
 }
+
+// GetRecentJournalEntries lists the newest entries across all live plants
+// written on or after the given day.
+func (s *PlantService) GetRecentJournalEntries(since time.Time, limit int) ([]types.RecentJournalEntry, error) {
+	query := `
+        SELECT j.id, j.plant_id, j.title, j.entry_type, j.description, j.image_path,
+               j.entry_date, j.created_at, j.updated_at, p.name as plant_name
+        FROM journal_entries j
+        JOIN plants p ON j.plant_id = p.id
+        WHERE j.entry_date >= $1::date AND p.deleted_at IS NULL
+        ORDER BY j.entry_date DESC, j.id DESC
+        LIMIT $2
+    `
+	var entries []types.RecentJournalEntry
+	if err := s.db.Select(&entries, query, since, limit); err != nil {
+		return nil, fmt.Errorf("error fetching recent journal entries: %w", err)
+	}
+	return entries, nil
+}
+
 func (s *PlantService) CreateJournalEntry(entry *types.JournalEntry) error {
+	return s.CreateJournalEntryTx(s.db, entry)
+}
+
+// CreateJournalEntryTx writes a journal entry as part of the caller's
+// transaction.
+func (s *PlantService) CreateJournalEntryTx(q plantWriter, entry *types.JournalEntry) error {
+	if err := insertJournalEntry(q, entry); err != nil {
+		log.Printf("Error creating journal entry: %v", err)
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+	return nil
+}
+
+func insertJournalEntry(q queryRower, entry *types.JournalEntry) error {
 	query := `
         INSERT INTO journal_entries (
             plant_id, title, entry_type, description, 
@@ -216,7 +394,7 @@ func (s *PlantService) CreateJournalEntry(entry *types.JournalEntry) error {
         RETURNING id, created_at, updated_at
     `
 
-	err := s.db.QueryRow(
+	return q.QueryRow(
 		query,
 		entry.PlantID,
 		entry.Title,
@@ -225,13 +403,6 @@ func (s *PlantService) CreateJournalEntry(entry *types.JournalEntry) error {
 		entry.ImagePath,
 		entry.EntryDate,
 	).Scan(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
-
-	if err != nil {
-		log.Printf("Error creating journal entry: %v", err)
-		return fmt.Errorf("failed to create journal entry: %w", err)
-	}
-
-	return nil
 }
 
 func (s *PlantService) GetLastWateringDate(plantID int) (*time.Time, error) {
@@ -375,7 +546,7 @@ func (s *PlantService) UpdateJournalEntry(entry *types.JournalEntry) error {
 	).Scan(&entry.CreatedAt, &entry.UpdatedAt)
 }
 
-func (s *PlantService) GetPlantsWithFilters(growthStage, species, cross, harvest string) ([]types.PlantWithDates, error) {
+func (s *PlantService) GetPlantsWithFilters(filters types.PlantFilters) ([]types.PlantWithDates, error) {
 	query := `
         WITH LastWatering AS (
             SELECT plant_id, entry_date as last_watered_at
@@ -398,13 +569,83 @@ func (s *PlantService) GetPlantsWithFilters(growthStage, species, cross, harvest
                 WHERE je2.plant_id = je1.plant_id
                 AND entry_type = 'Fertilizing'
             )
+        ),
+        Yield AS (
+            SELECT plant_id,
+                   COUNT(*) as harvest_count,
+                   SUM(pod_count) as pod_total,
+                   COALESCE(SUM(total_weight_grams), 0) as weight_total
+            FROM harvests
+            WHERE deleted_at IS NULL
+            GROUP BY plant_id
+        ),
+        VarietyYield AS (
+            SELECT vp.variety_id,
+                   COUNT(*) as harvest_count,
+                   SUM(h.pod_count) as pod_total,
+                   COALESCE(SUM(h.total_weight_grams), 0) as weight_total
+            FROM harvests h
+            JOIN plants vp ON h.plant_id = vp.id
+            WHERE h.deleted_at IS NULL AND vp.deleted_at IS NULL
+            AND vp.variety_id IS NOT NULL
+            GROUP BY vp.variety_id
+        ),
+        Heat AS (
+            SELECT ht.plant_id,
+                   AVG(ht.panel_score) as heat_score,
+                   AVG(` + heatSHUExpr + `) as heat_shu
+            FROM heat_tests ht
+            WHERE ht.deleted_at IS NULL
+            GROUP BY ht.plant_id
+        ),
+        HealthTrend AS (
+            SELECT DISTINCT ON (hc.plant_id) hc.plant_id, hc.from_health as earlier_health
+            FROM plant_health_changes hc
+            WHERE hc.from_health IS NOT NULL
+            AND hc.changed_at >= NOW() - INTERVAL '` + strconv.Itoa(types.HealthTrendDays) + ` days'
+            ORDER BY hc.plant_id, hc.changed_at
+        ),
+        Care AS (` + careSchedule("CURRENT_DATE") + `),
+        Tasks AS (
+            SELECT tp.id as plant_id,
+                   COUNT(*) FILTER (WHERE t.due_date = CURRENT_DATE) as due_tasks,
+                   COUNT(*) FILTER (WHERE t.due_date < CURRENT_DATE) as overdue_tasks
+            FROM tasks t, LATERAL (` + taskPlants + `) tp
+            WHERE t.deleted_at IS NULL AND t.completed_at IS NULL
+            AND t.due_date <= CURRENT_DATE
+            GROUP BY tp.id
         )
         SELECT p.*, 
                lw.last_watered_at,
-               lf.last_fertilized_at
+               lf.last_fertilized_at,
+               COALESCE(y.harvest_count, 0) as harvest_count,
+               COALESCE(y.pod_total, 0) as pod_total,
+               COALESCE(y.weight_total, 0) as weight_total,
+               COALESCE(vy.harvest_count, 0) as variety_harvest_count,
+               COALESCE(vy.pod_total, 0) as variety_pod_total,
+               COALESCE(vy.weight_total, 0) as variety_weight_total,
+               ht.heat_score,
+               ht.heat_shu,
+               CASE
+                   WHEN ` + healthRank("p.health") + ` > ` + healthRank("tr.earlier_health") + ` THEN 'Improving'
+                   WHEN ` + healthRank("p.health") + ` < ` + healthRank("tr.earlier_health") + ` THEN 'Declining'
+                   ELSE 'Stable'
+               END as health_trend,
+               care.watering_due,
+               COALESCE(care.watering_status, '') as watering_status,
+               care.fertilizing_due,
+               COALESCE(care.fertilizing_status, '') as fertilizing_status,
+               COALESCE(tk.due_tasks, 0) as due_tasks,
+               COALESCE(tk.overdue_tasks, 0) as overdue_tasks
         FROM plants p
         LEFT JOIN LastWatering lw ON p.id = lw.plant_id
         LEFT JOIN LastFertilizing lf ON p.id = lf.plant_id
+        LEFT JOIN Yield y ON p.id = y.plant_id
+        LEFT JOIN VarietyYield vy ON p.variety_id = vy.variety_id
+        LEFT JOIN Heat ht ON p.id = ht.plant_id
+        LEFT JOIN HealthTrend tr ON p.id = tr.plant_id
+        LEFT JOIN Care care ON p.id = care.plant_id
+        LEFT JOIN Tasks tk ON p.id = tk.plant_id
         WHERE p.deleted_at IS NULL
     `
 
@@ -412,37 +653,82 @@ func (s *PlantService) GetPlantsWithFilters(growthStage, species, cross, harvest
 	var conditions []string
 	argPosition := 1
 
-	if growthStage != "" {
+	if filters.GrowthStage != "" {
 		conditions = append(conditions, fmt.Sprintf("p.growth_stage = $%d", argPosition))
-		args = append(args, growthStage)
+		args = append(args, filters.GrowthStage)
 		argPosition++
 	}
 
-	if species != "" {
+	if filters.Species != "" {
 		conditions = append(conditions, fmt.Sprintf("p.species = $%d", argPosition))
-		args = append(args, species)
+		args = append(args, filters.Species)
 		argPosition++
 	}
 
-	if cross != "" {
+	if filters.Cross != "" {
 		conditions = append(conditions, fmt.Sprintf("p.is_cross = $%d", argPosition))
-		crossBool := cross == "true"
+		crossBool := filters.Cross == "true"
 		args = append(args, crossBool)
 		argPosition++
 	}
 
-	if harvest != "" {
-		conditions = append(conditions, fmt.Sprintf("p.is_harvested = $%d", argPosition))
-		harvestedBool := harvest == "true"
-		args = append(args, harvestedBool)
+	if filters.Season != "" {
+		conditions = append(conditions, fmt.Sprintf("p.season_finished = $%d", argPosition))
+		finishedBool := filters.Season == "finished"
+		args = append(args, finishedBool)
 		argPosition++
 	}
 
+	if filters.Generation != "" {
+		// Accept the same spellings as the forms, e.g. "f2" matches F2
+		generation := filters.Generation
+		if parsed, err := types.ParseGeneration(generation); err == nil {
+			generation = parsed.String()
+		}
+		conditions = append(conditions, fmt.Sprintf("p.generation = $%d", argPosition))
+		args = append(args, generation)
+		argPosition++
+	}
+
+	if filters.Project > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM project_plants pp WHERE pp.plant_id = p.id AND pp.project_id = $%d)", argPosition))
+		args = append(args, filters.Project)
+		argPosition++
+	}
+
+	// Heat bands follow the panel scale: up to "Warm" is mild, from "Hot"
+	// upwards is hot.
+	switch filters.Heat {
+	case "untested":
+		conditions = append(conditions, "ht.heat_score IS NULL")
+	case "mild":
+		conditions = append(conditions, "ht.heat_score < 3.5")
+	case "medium":
+		conditions = append(conditions, "ht.heat_score >= 3.5 AND ht.heat_score < 6.5")
+	case "hot":
+		conditions = append(conditions, "ht.heat_score >= 6.5")
+	}
+
+	switch filters.Care {
+	case "needs_care":
+		conditions = append(conditions, "(care.watering_status IN ('Due', 'Overdue') OR care.fertilizing_status IN ('Due', 'Overdue'))")
+	case "overdue":
+		conditions = append(conditions, "(care.watering_status = 'Overdue' OR care.fertilizing_status = 'Overdue')")
+	}
+
 	if len(conditions) > 0 {
 		query += " AND " + strings.Join(conditions, " AND ")
 	}
 
-	query += " ORDER BY p.created_at DESC"
+	switch filters.Sort {
+	case "heat_desc":
+		query += " ORDER BY ht.heat_score DESC NULLS LAST, p.created_at DESC"
+	case "heat_asc":
+		query += " ORDER BY ht.heat_score ASC NULLS LAST, p.created_at DESC"
+	default:
+		query += " ORDER BY p.created_at DESC"
+	}
 
 	var plants []types.PlantWithDates
 	err := s.db.Select(&plants, query, args...)
@@ -453,16 +739,18 @@ func (s *PlantService) GetPlantsWithFilters(growthStage, species, cross, harvest
 	return plants, nil
 }
 
-func (s *PlantService) MarkPlantAsHarvested(plantID int) error {
+// MarkSeasonFinished moves a plant into the season-finished lifecycle state.
+// Pickings are recorded separately in the harvest log.
+func (s *PlantService) MarkSeasonFinished(plantID int) error {
 	query := `
         UPDATE plants 
-        SET is_harvested = true,
-            harvested_at = CURRENT_TIMESTAMP
+        SET season_finished = true,
+            season_finished_at = CURRENT_TIMESTAMP
         WHERE id = $1 AND deleted_at IS NULL
     `
 	result, err := s.db.Exec(query, plantID)
 	if err != nil {
-		return fmt.Errorf("error marking plant as harvested: %w", err)
+		return fmt.Errorf("error marking season as finished: %w", err)
 	}
 
 	rows, err := result.RowsAffected()
@@ -474,3 +762,1136 @@ func (s *PlantService) MarkPlantAsHarvested(plantID int) error {
 	}
 	return nil
 }
+
+// maxPedigreeDepth bounds recursive pedigree walks so a bad row can never
+// make a query run away.
+const maxPedigreeDepth = 25
+
+var ErrInvalidParent = errors.New("invalid parent")
+
+// GetAncestors returns every recorded ancestor of a plant, nearest first.
+// Soft-deleted ancestors are kept so a line keeps its history after the
+// parent plants are gone.
+func (s *PlantService) GetAncestors(plantID int) ([]types.PedigreeNode, error) {
+	query := `
+        WITH RECURSIVE ancestry(plant_id, depth) AS (
+            SELECT unnest(ARRAY[seed_parent_id, pollen_parent_id]), 1
+            FROM plants
+            WHERE id = $1
+            UNION
+            SELECT unnest(ARRAY[p.seed_parent_id, p.pollen_parent_id]), a.depth + 1
+            FROM plants p
+            JOIN ancestry a ON p.id = a.plant_id
+            WHERE a.depth < $2
+        )
+        SELECT p.id, p.name, p.species, p.is_cross, p.generation,
+               p.seed_parent_id, p.pollen_parent_id,
+               p.seed_parent_external, p.pollen_parent_external,
+               p.deleted_at,
+               MIN(a.depth) as depth
+        FROM ancestry a
+        JOIN plants p ON p.id = a.plant_id
+        GROUP BY p.id
+        ORDER BY depth, p.name
+    `
+	var nodes []types.PedigreeNode
+	if err := s.db.Select(&nodes, query, plantID, maxPedigreeDepth); err != nil {
+		return nil, fmt.Errorf("error fetching ancestors: %w", err)
+	}
+	return nodes, nil
+}
+
+// GetDescendants returns every living plant that descends from the given
+// plant through either parent, nearest first.
+func (s *PlantService) GetDescendants(plantID int) ([]types.PedigreeNode, error) {
+	return s.descendants(plantID, false)
+}
+
+// descendants walks down from a plant through every row, deleted or not, so
+// a removed plant in the middle of a line does not hide the plants below
+// it. Deleted plants are only left out of the result when withDeleted is
+// false.
+func (s *PlantService) descendants(plantID int, withDeleted bool) ([]types.PedigreeNode, error) {
+	query := `
+        WITH RECURSIVE descendants(plant_id, depth) AS (
+            SELECT id, 1
+            FROM plants
+            WHERE (seed_parent_id = $1 OR pollen_parent_id = $1)
+            UNION
+            SELECT p.id, d.depth + 1
+            FROM plants p
+            JOIN descendants d ON p.seed_parent_id = d.plant_id OR p.pollen_parent_id = d.plant_id
+            WHERE d.depth < $2
+        )
+        SELECT p.id, p.name, p.species, p.is_cross, p.generation,
+               p.seed_parent_id, p.pollen_parent_id,
+               p.seed_parent_external, p.pollen_parent_external,
+               p.deleted_at,
+               MIN(d.depth) as depth
+        FROM descendants d
+        JOIN plants p ON p.id = d.plant_id
+        WHERE $3 OR p.deleted_at IS NULL
+        GROUP BY p.id
+        ORDER BY depth, p.name
+    `
+	var nodes []types.PedigreeNode
+	if err := s.db.Select(&nodes, query, plantID, maxPedigreeDepth, withDeleted); err != nil {
+		return nil, fmt.Errorf("error fetching descendants: %w", err)
+	}
+	return nodes, nil
+}
+
+func (s *PlantService) GetLineage(plantID int) (*types.Lineage, error) {
+	ancestors, err := s.GetAncestors(plantID)
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := s.GetDescendants(plantID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Lineage{
+		PlantID:     plantID,
+		Ancestors:   ancestors,
+		Descendants: descendants,
+	}, nil
+}
+
+// GetPedigreeNodes loads the given plants as pedigree nodes, including
+// deleted ones, so a family tree can show parents outside a lineage walk.
+func (s *PlantService) GetPedigreeNodes(ids []int) ([]types.PedigreeNode, error) {
+	query := `
+        SELECT id, name, species, is_cross, generation,
+               seed_parent_id, pollen_parent_id,
+               seed_parent_external, pollen_parent_external,
+               deleted_at
+        FROM plants
+        WHERE id = ANY($1)
+        ORDER BY name, id
+    `
+	var nodes []types.PedigreeNode
+	if err := s.db.Select(&nodes, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("error fetching pedigree nodes: %w", err)
+	}
+	return nodes, nil
+}
+
+// GetFamilyOffspring returns the living plants grown from a parent pair,
+// counting reciprocal crosses as the same family.
+func (s *PlantService) GetFamilyOffspring(parentAID, parentBID int) ([]types.PedigreeNode, error) {
+	query := `
+        SELECT id, name, species, is_cross, generation,
+               seed_parent_id, pollen_parent_id,
+               seed_parent_external, pollen_parent_external,
+               deleted_at
+        FROM plants
+        WHERE LEAST(seed_parent_id, pollen_parent_id) = $1
+        AND GREATEST(seed_parent_id, pollen_parent_id) = $2
+        AND deleted_at IS NULL
+        ORDER BY name, id
+    `
+	var nodes []types.PedigreeNode
+	if err := s.db.Select(&nodes, query, parentAID, parentBID); err != nil {
+		return nil, fmt.Errorf("error fetching family offspring: %w", err)
+	}
+	return nodes, nil
+}
+
+// GetPlantOptions lists the plants that can be picked as a parent. Harvested
+// plants are included since they are the usual source of saved seed.
+func (s *PlantService) GetPlantOptions() ([]types.PlantOption, error) {
+	query := `
+        SELECT id, name, species, generation
+        FROM plants
+        WHERE deleted_at IS NULL
+        ORDER BY name
+    `
+	var options []types.PlantOption
+	if err := s.db.Select(&options, query); err != nil {
+		return nil, fmt.Errorf("error fetching plant options: %w", err)
+	}
+	return options, nil
+}
+
+// ValidateParents checks that the chosen parents exist and that linking them
+// would not make a plant its own ancestor. plantID is 0 for new plants.
+func (s *PlantService) ValidateParents(plantID int, seedParentID, pollenParentID sql.NullInt64) error {
+	// Deleted descendants count too: they can still be picked as parents,
+	// and a loop through one is still a loop
+	var descendants []types.PedigreeNode
+	if plantID != 0 {
+		var err error
+		descendants, err = s.descendants(plantID, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, parentID := range []sql.NullInt64{seedParentID, pollenParentID} {
+		if !parentID.Valid {
+			continue
+		}
+		if int(parentID.Int64) == plantID {
+			return fmt.Errorf("%w: a plant cannot be its own parent", ErrInvalidParent)
+		}
+		for _, d := range descendants {
+			if int64(d.ID) == parentID.Int64 {
+				return fmt.Errorf("%w: %s descends from this plant", ErrInvalidParent, d.Name)
+			}
+		}
+
+		var exists bool
+		err := s.db.Get(&exists, `SELECT EXISTS(SELECT 1 FROM plants WHERE id = $1)`, parentID.Int64)
+		if err != nil {
+			return fmt.Errorf("error checking parent: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("%w: plant %d does not exist", ErrInvalidParent, parentID.Int64)
+		}
+	}
+	return nil
+}
+
+// GetGenerations lists the distinct generations in use, for the list filter.
+func (s *PlantService) GetGenerations() ([]string, error) {
+	query := `
+        SELECT DISTINCT generation
+        FROM plants
+        WHERE generation IS NOT NULL AND deleted_at IS NULL
+        ORDER BY generation
+    `
+	var generations []string
+	if err := s.db.Select(&generations, query); err != nil {
+		return nil, fmt.Errorf("error fetching generations: %w", err)
+	}
+	return generations, nil
+}
+
+func (s *PlantService) GetPlantFilterOptions() (*types.PlantFilterOptions, error) {
+	generations, err := s.GetGenerations()
+	if err != nil {
+		return nil, err
+	}
+
+	var species []types.Species
+	query := `
+        SELECT name
+        FROM species
+        WHERE deleted_at IS NULL
+        ORDER BY is_hybrid, name
+    `
+	if err := s.db.Select(&species, query); err != nil {
+		return nil, fmt.Errorf("error fetching species options: %w", err)
+	}
+
+	var projects []types.ProjectOption
+	query = `
+        SELECT id, name
+        FROM breeding_projects
+        WHERE deleted_at IS NULL
+        ORDER BY name
+    `
+	if err := s.db.Select(&projects, query); err != nil {
+		return nil, fmt.Errorf("error fetching project options: %w", err)
+	}
+
+	return &types.PlantFilterOptions{Species: species, Generations: generations, Projects: projects}, nil
+}
+
+// DeriveGeneration works out a plant's generation from its recorded parents.
+// The second return value is false when there is no seed parent or the
+// offspring is not a cross.
+func (s *PlantService) DeriveGeneration(plant *types.PlantWithDates) (types.Generation, bool, error) {
+	if !plant.SeedParentID.Valid && !plant.SeedParentExt.Valid {
+		return types.Generation{}, false, nil
+	}
+
+	seedParent, err := s.generationParent(plant.SeedParentID, plant.SeedParentExt)
+	if err != nil {
+		return types.Generation{}, false, err
+	}
+
+	var pollenParent *types.GenerationParent
+	if plant.PollenParentID.Valid || plant.PollenParentExt.Valid {
+		parent, err := s.generationParent(plant.PollenParentID, plant.PollenParentExt)
+		if err != nil {
+			return types.Generation{}, false, err
+		}
+		pollenParent = &parent
+	}
+
+	generation, ok := types.DeriveGeneration(seedParent, pollenParent)
+	return generation, ok, nil
+}
+
+// generationParent loads a parent's generation and, for crosses, its
+// ancestors. External varieties are treated as stable, non-cross parents.
+func (s *PlantService) generationParent(parentID sql.NullInt64, external sql.NullString) (types.GenerationParent, error) {
+	if !parentID.Valid {
+		return types.GenerationParent{External: external.String}, nil
+	}
+
+	var row struct {
+		Generation      sql.NullString `db:"generation"`
+		SeedParentExt   sql.NullString `db:"seed_parent_external"`
+		PollenParentExt sql.NullString `db:"pollen_parent_external"`
+	}
+	query := `SELECT generation, seed_parent_external, pollen_parent_external FROM plants WHERE id = $1`
+	if err := s.db.Get(&row, query, parentID.Int64); err != nil {
+		return types.GenerationParent{}, fmt.Errorf("error fetching parent generation: %w", err)
+	}
+
+	parent := types.GenerationParent{PlantID: int(parentID.Int64)}
+	if row.Generation.Valid {
+		if parsed, err := types.ParseGeneration(row.Generation.String); err == nil {
+			parent.Generation = &parsed
+		}
+	}
+	if parent.Generation == nil {
+		return parent, nil
+	}
+
+	ancestors, err := s.GetAncestors(parent.PlantID)
+	if err != nil {
+		return types.GenerationParent{}, err
+	}
+	externals := []sql.NullString{row.SeedParentExt, row.PollenParentExt}
+	for _, ancestor := range ancestors {
+		parent.AncestorIDs = append(parent.AncestorIDs, ancestor.ID)
+		externals = append(externals, ancestor.SeedParentExt, ancestor.PollenParentExt)
+	}
+	for _, name := range externals {
+		if name.Valid && name.String != "" {
+			parent.AncestorExternals = append(parent.AncestorExternals, name.String)
+		}
+	}
+	return parent, nil
+}
+
+// NormalizeGenerations rewrites hand-typed generations into canonical form so
+// that "f2 " and "F2" end up in the same line. Values that cannot be parsed
+// are left untouched and logged.
+func (s *PlantService) NormalizeGenerations() error {
+	var rows []struct {
+		ID         int    `db:"id"`
+		Generation string `db:"generation"`
+	}
+	err := s.db.Select(&rows, `SELECT id, generation FROM plants WHERE generation IS NOT NULL`)
+	if err != nil {
+		return fmt.Errorf("error fetching generations: %w", err)
+	}
+
+	for _, row := range rows {
+		if strings.TrimSpace(row.Generation) == "" {
+			if _, err := s.db.Exec(`UPDATE plants SET generation = NULL WHERE id = $1`, row.ID); err != nil {
+				return fmt.Errorf("error clearing generation: %w", err)
+			}
+			continue
+		}
+
+		parsed, err := types.ParseGeneration(row.Generation)
+		if err != nil {
+			log.Printf("Leaving unrecognised generation %q on plant %d", row.Generation, row.ID)
+			continue
+		}
+		if parsed.String() == row.Generation {
+			continue
+		}
+		if _, err := s.db.Exec(`UPDATE plants SET generation = $1 WHERE id = $2`, parsed.String(), row.ID); err != nil {
+			return fmt.Errorf("error normalizing generation: %w", err)
+		}
+	}
+	return nil
+}
+
+var (
+	ErrGerminationTrialNotFound = errors.New("germination trial not found")
+	ErrTooManyGerminated        = errors.New("more seeds germinated than were sown")
+	ErrCountBeforeSowing        = errors.New("count is dated before the sow date")
+	ErrNoSeedlingsLeft          = errors.New("not enough germinated seedlings left to promote")
+)
+
+// germinationTrialSelect works out each trial's germination percentage and
+// mean days to germination from its daily counts.
+const germinationTrialSelect = `
+        SELECT t.*,
+               lp.name as lot_plant_name,
+               v.name as variety_name,
+               COALESCE(gc.germinated, 0) as germinated,
+               COALESCE(gc.germinated, 0) * 100.0 / t.seeds_sown as rate,
+               gc.mean_days,
+               (
+                   SELECT COUNT(*)
+                   FROM plants p
+                   WHERE p.germination_trial_id = t.id AND p.deleted_at IS NULL
+               ) as promoted
+        FROM germination_trials t
+        LEFT JOIN seed_lots sl ON t.seed_lot_id = sl.id
+        LEFT JOIN plants lp ON sl.plant_id = lp.id
+        LEFT JOIN varieties v ON t.variety_id = v.id
+        LEFT JOIN (
+            SELECT c.trial_id,
+                   SUM(c.germinated) as germinated,
+                   SUM(c.germinated * (c.counted_on - gt.sow_date))::float8 / NULLIF(SUM(c.germinated), 0) as mean_days
+            FROM germination_counts c
+            JOIN germination_trials gt ON c.trial_id = gt.id
+            GROUP BY c.trial_id
+        ) gc ON gc.trial_id = t.id
+`
+
+func (s *PlantService) GetGerminationTrials() ([]types.GerminationTrial, error) {
+	query := germinationTrialSelect + `
+        WHERE t.deleted_at IS NULL
+        ORDER BY t.sow_date DESC, t.id DESC
+    `
+	var trials []types.GerminationTrial
+	if err := s.db.Select(&trials, query); err != nil {
+		return nil, fmt.Errorf("error fetching germination trials: %w", err)
+	}
+	return trials, nil
+}
+
+func (s *PlantService) GetGerminationTrial(id int) (*types.GerminationTrial, error) {
+	query := germinationTrialSelect + `
+        WHERE t.id = $1 AND t.deleted_at IS NULL
+    `
+	var trial types.GerminationTrial
+	if err := s.db.Get(&trial, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGerminationTrialNotFound
+		}
+		return nil, fmt.Errorf("error fetching germination trial: %w", err)
+	}
+	return &trial, nil
+}
+
+func (s *PlantService) GetGerminationCounts(trialID int) ([]types.GerminationCount, error) {
+	query := `
+        SELECT *
+        FROM germination_counts
+        WHERE trial_id = $1
+        ORDER BY counted_on
+    `
+	var counts []types.GerminationCount
+	if err := s.db.Select(&counts, query, trialID); err != nil {
+		return nil, fmt.Errorf("error fetching germination counts: %w", err)
+	}
+	return counts, nil
+}
+
+// CreateGerminationTrial records a sowing. Seed sown from a lot is taken out
+// of the lot in the same transaction, as when sowing a plant directly.
+func (s *PlantService) CreateGerminationTrial(trial *types.GerminationTrial) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if trial.SeedLotID.Valid {
+		if err := consumeSeeds(tx, int(trial.SeedLotID.Int64), trial.SeedsSown); err != nil {
+			return err
+		}
+	}
+
+	query := `
+        INSERT INTO germination_trials (
+            seed_lot_id, variety_id, seeds_sown, method, temperature_c, sow_date, notes
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id, created_at, updated_at
+    `
+	err = tx.QueryRow(
+		query,
+		trial.SeedLotID,
+		trial.VarietyID,
+		trial.SeedsSown,
+		trial.Method,
+		trial.TemperatureC,
+		trial.SowDate,
+		trial.Notes,
+	).Scan(&trial.ID, &trial.CreatedAt, &trial.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating germination trial: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// RecordGerminationCount sets the number of seeds that came up on a day,
+// replacing an earlier count for the same day.
+func (s *PlantService) RecordGerminationCount(trialID int, countedOn time.Time, germinated int) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var trial struct {
+		SeedsSown int       `db:"seeds_sown"`
+		SowDate   time.Time `db:"sow_date"`
+	}
+	query := `SELECT seeds_sown, sow_date FROM germination_trials WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.Get(&trial, query, trialID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrGerminationTrialNotFound
+		}
+		return fmt.Errorf("error fetching germination trial: %w", err)
+	}
+	if countedOn.Before(trial.SowDate) {
+		return ErrCountBeforeSowing
+	}
+
+	query = `
+        INSERT INTO germination_counts (trial_id, counted_on, germinated)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (trial_id, counted_on) DO UPDATE SET germinated = EXCLUDED.germinated
+    `
+	if _, err := tx.Exec(query, trialID, countedOn, germinated); err != nil {
+		return fmt.Errorf("error recording germination count: %w", err)
+	}
+
+	var total int
+	if err := tx.Get(&total, `SELECT COALESCE(SUM(germinated), 0) FROM germination_counts WHERE trial_id = $1`, trialID); err != nil {
+		return fmt.Errorf("error totalling germination counts: %w", err)
+	}
+	if total > trial.SeedsSown {
+		return ErrTooManyGerminated
+	}
+
+	return tx.Commit()
+}
+
+func (s *PlantService) DeleteGerminationCount(trialID, countID int) error {
+	result, err := s.db.Exec(`DELETE FROM germination_counts WHERE id = $1 AND trial_id = $2`, countID, trialID)
+	if err != nil {
+		return fmt.Errorf("error deleting germination count: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrGerminationTrialNotFound
+	}
+	return nil
+}
+
+// DeleteGerminationTrial removes a trial. Seedlings already promoted are
+// kept as plants.
+func (s *PlantService) DeleteGerminationTrial(id int) error {
+	query := `UPDATE germination_trials SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	result, err := s.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting germination trial: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrGerminationTrialNotFound
+	}
+	return nil
+}
+
+// GetGerminationTrialPlants lists the plants promoted out of a trial.
+func (s *PlantService) GetGerminationTrialPlants(trialID int) ([]types.PlantOption, error) {
+	query := `
+        SELECT id, name, species, generation
+        FROM plants
+        WHERE germination_trial_id = $1 AND deleted_at IS NULL
+        ORDER BY id
+    `
+	var plants []types.PlantOption
+	if err := s.db.Select(&plants, query, trialID); err != nil {
+		return nil, fmt.Errorf("error fetching trial plants: %w", err)
+	}
+	return plants, nil
+}
+
+// PromoteSeedlings turns germinated seedlings into plants at the Seedling
+// stage, numbered on from those already promoted. Seedlings from a lot get
+// the lot's parents; the seed was already taken out of the lot at sowing.
+func (s *PlantService) PromoteSeedlings(trialID int, count int, baseName string) error {
+	trial, err := s.GetGerminationTrial(trialID)
+	if err != nil {
+		return err
+	}
+	if count < 1 || count > trial.Promotable() {
+		return ErrNoSeedlingsLeft
+	}
+
+	template := types.PlantWithDates{
+		Health:             types.PlantHealthGood,
+		GrowthStage:        types.GrowthStageSeed,
+		PlantingDate:       trial.SowDate,
+		SeedLotID:          trial.SeedLotID,
+		VarietyID:          trial.VarietyID,
+		GerminationTrialID: sql.NullInt64{Int64: int64(trial.ID), Valid: true},
+	}
+
+	if trial.SeedLotID.Valid {
+		var lot struct {
+			PlantID         int            `db:"plant_id"`
+			PollenParentID  sql.NullInt64  `db:"pollen_parent_id"`
+			PollenParentExt sql.NullString `db:"pollen_parent_external"`
+			Species         sql.NullString `db:"species"`
+			VarietyID       sql.NullInt64  `db:"variety_id"`
+		}
+		query := `
+            SELECT sl.plant_id, sl.pollen_parent_id, sl.pollen_parent_external, p.species, p.variety_id
+            FROM seed_lots sl
+            JOIN plants p ON sl.plant_id = p.id
+            WHERE sl.id = $1
+        `
+		if err := s.db.Get(&lot, query, trial.SeedLotID.Int64); err != nil {
+			return fmt.Errorf("error fetching trial seed lot: %w", err)
+		}
+		template.SeedParentID = sql.NullInt64{Int64: int64(lot.PlantID), Valid: true}
+		template.PollenParentID = lot.PollenParentID
+		template.PollenParentExt = lot.PollenParentExt
+		template.Species = types.Species(lot.Species.String)
+		if !template.VarietyID.Valid {
+			template.VarietyID = lot.VarietyID
+		}
+
+		generation, ok, err := s.DeriveGeneration(&template)
+		if err != nil {
+			return err
+		}
+		if ok {
+			// Crosses are not a cultivar until the line is named
+			template.IsCross = true
+			template.Generation = sql.NullString{String: generation.String(), Valid: true}
+			template.VarietyID = trial.VarietyID
+		}
+	}
+
+	if template.Species == "" && template.VarietyID.Valid {
+		var species sql.NullString
+		query := `SELECT species FROM varieties WHERE id = $1`
+		if err := s.db.Get(&species, query, template.VarietyID.Int64); err != nil {
+			return fmt.Errorf("error fetching variety species: %w", err)
+		}
+		template.Species = types.Species(species.String)
+	}
+
+	baseName = strings.TrimSpace(baseName)
+	if baseName == "" {
+		baseName = trial.SeedlingName()
+	}
+
+	// Seedlings are counted as up from the first day any came up
+	var germinatedOn time.Time
+	query := `SELECT MIN(counted_on) FROM germination_counts WHERE trial_id = $1 AND germinated > 0`
+	if err := s.db.Get(&germinatedOn, query, trial.ID); err != nil {
+		return fmt.Errorf("error fetching first germination: %w", err)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the trial before counting what is left, so two promotions at once
+	// can neither overdraw the trial nor hand out the same numbers. The
+	// re-read is a separate statement so it sees what the other committed.
+	query = `SELECT id FROM germination_trials WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if _, err := tx.Exec(query, trialID); err != nil {
+		return fmt.Errorf("error locking germination trial: %w", err)
+	}
+	if err := tx.Get(trial, germinationTrialSelect+` WHERE t.id = $1 AND t.deleted_at IS NULL`, trialID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrGerminationTrialNotFound
+		}
+		return fmt.Errorf("error fetching germination trial: %w", err)
+	}
+	if count > trial.Promotable() {
+		return ErrNoSeedlingsLeft
+	}
+
+	for i := 1; i <= count; i++ {
+		plant := template
+		plant.Name = fmt.Sprintf("%s #%d", baseName, trial.Promoted+i)
+		if err := insertPlant(tx, &plant); err != nil {
+			return fmt.Errorf("error promoting seedling: %w", err)
+		}
+		if err := changeStage(tx, plant.ID, types.GrowthStageSeed, types.GrowthStageSeedling, germinatedOn, types.StageSourceGermination, ""); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+var (
+	ErrFinalStage              = errors.New("plant is already at its last growth stage")
+	ErrPlantGone               = errors.New("plant is dead or given away")
+	ErrSameStage               = errors.New("plant is already at that stage")
+	ErrTransitionBeforeSowing  = errors.New("stage change is dated before the plant was sown")
+	ErrTransitionOutOfSequence = errors.New("stage change is dated before the previous one")
+)
+
+func recordStageTransition(q plantWriter, plantID int, from, to types.GrowthStage, on time.Time, source types.StageSource, reason string) error {
+	query := `
+        INSERT INTO plant_stage_transitions (plant_id, from_stage, to_stage, transitioned_on, source, reason)
+        VALUES ($1, NULLIF($2, ''), $3, $4, $5, NULLIF($6, ''))
+    `
+	if _, err := q.Exec(query, plantID, string(from), to, on, source, strings.TrimSpace(reason)); err != nil {
+		return fmt.Errorf("error recording stage transition: %w", err)
+	}
+	return nil
+}
+
+// changeStage moves a plant to a new stage and records the transition.
+func changeStage(q plantWriter, plantID int, from, to types.GrowthStage, on time.Time, source types.StageSource, reason string) error {
+	query := `UPDATE plants SET growth_stage = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	if _, err := q.Exec(query, to, plantID); err != nil {
+		return fmt.Errorf("error updating growth stage: %w", err)
+	}
+	return recordStageTransition(q, plantID, from, to, on, source, reason)
+}
+
+// lockStage locks a plant for a stage change dated on and returns its
+// current stage.
+func lockStage(tx *sqlx.Tx, plantID int, on time.Time) (types.GrowthStage, error) {
+	var plant struct {
+		GrowthStage  types.GrowthStage `db:"growth_stage"`
+		PlantingDate time.Time         `db:"planting_date"`
+		LastChange   sql.NullTime      `db:"last_change"`
+	}
+	query := `
+        SELECT p.growth_stage, p.planting_date,
+               (SELECT MAX(t.transitioned_on) FROM plant_stage_transitions t WHERE t.plant_id = p.id) as last_change
+        FROM plants p
+        WHERE p.id = $1 AND p.deleted_at IS NULL
+        FOR UPDATE
+    `
+	if err := tx.Get(&plant, query, plantID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrPlantNotFound
+		}
+		return "", fmt.Errorf("error fetching plant stage: %w", err)
+	}
+
+	if err := checkStageDate(on, plant.PlantingDate, plant.LastChange); err != nil {
+		return "", err
+	}
+	return plant.GrowthStage, nil
+}
+
+// checkStageDate rejects a stage change dated before sowing or before the
+// plant's previous stage change.
+func checkStageDate(on, plantingDate time.Time, lastChange sql.NullTime) error {
+	if on.Before(plantingDate) {
+		return ErrTransitionBeforeSowing
+	}
+	if lastChange.Valid && on.Before(lastChange.Time) {
+		return ErrTransitionOutOfSequence
+	}
+	return nil
+}
+
+// AdvanceStage moves a plant on to the next growth stage as of the given day.
+func (s *PlantService) AdvanceStage(plantID int, on time.Time) (types.GrowthStage, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return "", fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := lockStage(tx, plantID, on)
+	if err != nil {
+		return "", err
+	}
+
+	next, ok := current.Next()
+	if !ok {
+		return "", ErrFinalStage
+	}
+
+	if err := changeStage(tx, plantID, current, next, on, types.StageSourceAdvance, ""); err != nil {
+		return "", err
+	}
+	return next, tx.Commit()
+}
+
+// MoveToStage moves a plant to any stage, typically into or out of
+// overwintering or dormancy, or to its end as dead or given away. A plant
+// that has ended can only be brought back through the edit form.
+func (s *PlantService) MoveToStage(plantID int, to types.GrowthStage, on time.Time, reason string) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := lockStage(tx, plantID, on)
+	if err != nil {
+		return err
+	}
+	if current.IsEnd() {
+		return ErrPlantGone
+	}
+	if current == to {
+		return ErrSameStage
+	}
+
+	if err := changeStage(tx, plantID, current, to, on, types.StageSourceLifecycle, reason); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PlantService) GetStageTransitions(plantID int) ([]types.StageTransition, error) {
+	query := `
+        SELECT *
+        FROM plant_stage_transitions
+        WHERE plant_id = $1
+        ORDER BY transitioned_on, id
+    `
+	var transitions []types.StageTransition
+	if err := s.db.Select(&transitions, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching stage transitions: %w", err)
+	}
+	return transitions, nil
+}
+
+// plantMilestones gives each plant's days from sowing to its first observed
+// seedling and flower and to its first harvest.
+const plantMilestones = `
+        SELECT p.id, p.variety_id,
+               (
+                   SELECT MIN(t.transitioned_on) FROM plant_stage_transitions t
+                   WHERE t.plant_id = p.id AND t.to_stage = 'Seedling' AND t.from_stage IS NOT NULL
+               ) - p.planting_date as seed_to_seedling,
+               (
+                   SELECT MIN(t.transitioned_on) FROM plant_stage_transitions t
+                   WHERE t.plant_id = p.id AND t.to_stage = 'Flowering' AND t.from_stage IS NOT NULL
+               ) - p.planting_date as to_first_flower,
+               (
+                   SELECT MIN(h.harvest_date) FROM harvests h
+                   WHERE h.plant_id = p.id AND h.deleted_at IS NULL
+               ) - p.planting_date as to_first_ripe_pod
+        FROM plants p
+        WHERE p.deleted_at IS NULL
+`
+
+const stageDurationsSelect = `
+        SELECT AVG(m.seed_to_seedling)::float8 as seed_to_seedling,
+               COUNT(m.seed_to_seedling) as seedling_plants,
+               AVG(m.to_first_flower)::float8 as to_first_flower,
+               COUNT(m.to_first_flower) as flowering_plants,
+               AVG(m.to_first_ripe_pod)::float8 as to_first_ripe_pod,
+               COUNT(m.to_first_ripe_pod) as ripe_pod_plants
+        FROM (` + plantMilestones + `) m
+`
+
+func (s *PlantService) GetStageDurations(plantID int) (*types.StageDurations, error) {
+	var durations types.StageDurations
+	if err := s.db.Get(&durations, stageDurationsSelect+`WHERE m.id = $1`, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching stage durations: %w", err)
+	}
+	return &durations, nil
+}
+
+// GetVarietyStageDurations averages the stage durations of a variety's plants.
+func (s *PlantService) GetVarietyStageDurations(varietyID int) (*types.StageDurations, error) {
+	var durations types.StageDurations
+	if err := s.db.Get(&durations, stageDurationsSelect+`WHERE m.variety_id = $1`, varietyID); err != nil {
+		return nil, fmt.Errorf("error fetching variety stage durations: %w", err)
+	}
+	return &durations, nil
+}
+
+// BackfillStageHistory gives plants that predate stage tracking a starting
+// entry for their current stage, dated from sowing for seed and from when
+// the plant was entered otherwise.
+func (s *PlantService) BackfillStageHistory() error {
+	query := `
+        INSERT INTO plant_stage_transitions (plant_id, to_stage, transitioned_on, source)
+        SELECT p.id, p.growth_stage,
+               CASE WHEN p.growth_stage = 'Seed' THEN p.planting_date
+                    ELSE GREATEST(p.created_at::date, p.planting_date) END,
+               'Created'
+        FROM plants p
+        WHERE p.growth_stage IS NOT NULL
+        AND NOT EXISTS (SELECT 1 FROM plant_stage_transitions t WHERE t.plant_id = p.id)
+    `
+	result, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("error backfilling stage history: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+		log.Printf("Recorded starting stage for %d plants", rows)
+	}
+	return nil
+}
+
+var ErrHealthAlertNotFound = errors.New("health alert not found")
+
+// healthRank places a health column on types.HealthLevels, 1 for the worst.
+func healthRank(column string) string {
+	levels := make([]string, len(types.HealthLevels))
+	for i, level := range types.HealthLevels {
+		levels[i] = "'" + string(level) + "'"
+	}
+	return fmt.Sprintf("array_position(ARRAY[%s]::text[], %s::text)", strings.Join(levels, ", "), column)
+}
+
+func recordHealthChange(q plantWriter, plantID int, from, to types.PlantHealth, journalEntryID sql.NullInt64) error {
+	if to == "" {
+		return nil
+	}
+	query := `
+        INSERT INTO plant_health_changes (plant_id, from_health, to_health, journal_entry_id)
+        VALUES ($1, NULLIF($2, ''), $3, $4)
+    `
+	if _, err := q.Exec(query, plantID, string(from), to, journalEntryID); err != nil {
+		return fmt.Errorf("error recording health change: %w", err)
+	}
+	return nil
+}
+
+// changeHealth records a health change, first writing the note as a journal
+// entry when one is given so the history can link to it.
+func changeHealth(q plantWriter, plantID int, from, to types.PlantHealth, note string) error {
+	var journalEntryID sql.NullInt64
+	if note = strings.TrimSpace(note); note != "" {
+		entry := &types.JournalEntry{
+			PlantID:     plantID,
+			Title:       fmt.Sprintf("Health: %s", to),
+			EntryType:   "General",
+			Description: note,
+			EntryDate:   time.Now(),
+		}
+		if from != "" {
+			entry.Title = fmt.Sprintf("Health: %s → %s", from, to)
+		}
+		if to.Rank() < from.Rank() {
+			entry.EntryType = "Problem"
+		}
+		if err := insertJournalEntry(q, entry); err != nil {
+			return fmt.Errorf("error creating health journal entry: %w", err)
+		}
+		journalEntryID = sql.NullInt64{Int64: int64(entry.ID), Valid: true}
+	}
+	return recordHealthChange(q, plantID, from, to, journalEntryID)
+}
+
+// SetHealth changes a plant's health outside the edit form.
+func (s *PlantService) SetHealth(plantID int, health types.PlantHealth, note string) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous types.PlantHealth
+	query := `SELECT COALESCE(health, '') FROM plants WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.Get(&previous, query, plantID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrPlantNotFound
+		}
+		return fmt.Errorf("error fetching plant health: %w", err)
+	}
+	if previous == health {
+		return nil
+	}
+
+	query = `UPDATE plants SET health = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	if _, err := tx.Exec(query, health, plantID); err != nil {
+		return fmt.Errorf("error updating plant health: %w", err)
+	}
+	if err := changeHealth(tx, plantID, previous, health, note); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *PlantService) GetHealthHistory(plantID int) ([]types.HealthChange, error) {
+	query := `
+        SELECT hc.*, je.title as journal_title
+        FROM plant_health_changes hc
+        LEFT JOIN journal_entries je ON hc.journal_entry_id = je.id AND je.deleted_at IS NULL
+        WHERE hc.plant_id = $1
+        ORDER BY hc.changed_at DESC, hc.id DESC
+    `
+	var changes []types.HealthChange
+	if err := s.db.Select(&changes, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching health history: %w", err)
+	}
+	return changes, nil
+}
+
+// CheckHealthDeclines flags plants whose health is now at least two levels
+// below the best it was within the last windowDays. A plant with an alert
+// still open is not flagged again, and once acknowledged it is only flagged
+// again after a newer health change. It returns the new alerts.
+func (s *PlantService) CheckHealthDeclines(windowDays int) ([]types.HealthAlert, error) {
+	query := `
+        WITH WindowHealth AS (
+            SELECT hc.plant_id, hc.from_health as health
+            FROM plant_health_changes hc
+            WHERE hc.changed_at >= NOW() - make_interval(days => $1)
+            AND hc.from_health IS NOT NULL
+            UNION ALL
+            SELECT hc.plant_id, hc.to_health as health
+            FROM plant_health_changes hc
+            WHERE hc.changed_at >= NOW() - make_interval(days => $1)
+        ),
+        Peak AS (
+            SELECT DISTINCT ON (wh.plant_id) wh.plant_id, wh.health as peak_health
+            FROM WindowHealth wh
+            ORDER BY wh.plant_id, ` + healthRank("wh.health") + ` DESC
+        )
+        INSERT INTO health_alerts (plant_id, peak_health, health, window_days)
+        SELECT p.id, pk.peak_health, p.health, $1
+        FROM plants p
+        JOIN Peak pk ON pk.plant_id = p.id
+        WHERE p.deleted_at IS NULL
+        AND NOT p.season_finished
+        AND p.growth_stage NOT IN ('Dead', 'Given Away')
+        AND ` + healthRank("pk.peak_health") + ` - ` + healthRank("p.health") + ` >= 2
+        AND NOT EXISTS (
+            SELECT 1 FROM health_alerts a
+            WHERE a.plant_id = p.id
+            AND (a.acknowledged_at IS NULL OR a.detected_at >= (
+                SELECT MAX(hc.changed_at) FROM plant_health_changes hc WHERE hc.plant_id = p.id
+            ))
+        )
+        RETURNING *, (SELECT name FROM plants WHERE id = plant_id) as plant_name
+    `
+	var alerts []types.HealthAlert
+	if err := s.db.Select(&alerts, query, windowDays); err != nil {
+		return nil, fmt.Errorf("error checking health declines: %w", err)
+	}
+	return alerts, nil
+}
+
+// GetOpenHealthAlerts lists alerts nobody has looked into yet.
+func (s *PlantService) GetOpenHealthAlerts() ([]types.HealthAlert, error) {
+	query := `
+        SELECT a.*, p.name as plant_name
+        FROM health_alerts a
+        JOIN plants p ON a.plant_id = p.id
+        WHERE a.acknowledged_at IS NULL AND p.deleted_at IS NULL
+        ORDER BY a.detected_at DESC
+    `
+	var alerts []types.HealthAlert
+	if err := s.db.Select(&alerts, query); err != nil {
+		return nil, fmt.Errorf("error fetching health alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+func (s *PlantService) AcknowledgeHealthAlert(id int) error {
+	query := `UPDATE health_alerts SET acknowledged_at = CURRENT_TIMESTAMP WHERE id = $1 AND acknowledged_at IS NULL`
+	result, err := s.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error acknowledging health alert: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrHealthAlertNotFound
+	}
+	return nil
+}
+
+// BackfillHealthHistory gives plants that predate health tracking a
+// starting entry for their current health.
+func (s *PlantService) BackfillHealthHistory() error {
+	query := `
+        INSERT INTO plant_health_changes (plant_id, to_health, changed_at)
+        SELECT p.id, p.health, p.created_at
+        FROM plants p
+        WHERE p.health IS NOT NULL
+        AND NOT EXISTS (SELECT 1 FROM plant_health_changes hc WHERE hc.plant_id = p.id)
+    `
+	result, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("error backfilling health history: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+		log.Printf("Recorded starting health for %d plants", rows)
+	}
+	return nil
+}
+
+// stageCareDefault picks a plant's default interval for its growth stage
+// out of types.StageCareDefaults.
+func stageCareDefault(days func(types.CareInterval) int) string {
+	var cases []string
+	for _, stage := range types.AllGrowthStages() {
+		if interval, ok := types.StageCareDefaults[stage]; ok && days(interval) > 0 {
+			cases = append(cases, fmt.Sprintf("WHEN '%s' THEN %d", stage, days(interval)))
+		}
+	}
+	return "CASE p.growth_stage " + strings.Join(cases, " ") + " ELSE 0 END"
+}
+
+// careSchedule gives each plant's watering and fertilizing intervals, taken
+// from the plant, then its variety, then its growth stage, with the day each
+// next falls due and its status as of asOf. A plant with nothing logged yet
+// is counted from its planting date. Finished, dead and given away plants
+// are left out.
+func careSchedule(asOf string) string {
+	status := func(due string) string {
+		return `CASE
+                   WHEN ` + due + ` IS NULL THEN NULL
+                   WHEN ` + due + ` < ` + asOf + ` THEN 'Overdue'
+                   WHEN ` + due + ` = ` + asOf + ` THEN 'Due'
+                   ELSE 'OK'
+               END`
+	}
+	return `
+            SELECT c.*,
+                   ` + status("c.watering_due") + ` as watering_status,
+                   ` + status("c.fertilizing_due") + ` as fertilizing_status
+            FROM (
+                SELECT s.*,
+                       COALESCE(s.last_watered, s.planting_date)::date + s.watering_every as watering_due,
+                       COALESCE(s.last_fertilized, s.planting_date)::date + s.fertilizing_every as fertilizing_due
+                FROM (
+                    SELECT p.id as plant_id, p.name as plant_name, p.planting_date,
+                           NULLIF(COALESCE(p.watering_interval_days, v.watering_interval_days, ` + stageCareDefault(func(i types.CareInterval) int { return i.WateringDays }) + `), 0) as watering_every,
+                           NULLIF(COALESCE(p.fertilizing_interval_days, v.fertilizing_interval_days, ` + stageCareDefault(func(i types.CareInterval) int { return i.FertilizingDays }) + `), 0) as fertilizing_every,
+                           (SELECT MAX(je.entry_date) FROM journal_entries je WHERE je.plant_id = p.id AND je.entry_type = 'Watering') as last_watered,
+                           (SELECT MAX(je.entry_date) FROM journal_entries je WHERE je.plant_id = p.id AND je.entry_type = 'Fertilizing') as last_fertilized
+                    FROM plants p
+                    LEFT JOIN varieties v ON p.variety_id = v.id AND v.deleted_at IS NULL
+                    WHERE p.deleted_at IS NULL
+                    AND NOT p.season_finished
+                    AND p.growth_stage NOT IN ('Dead', 'Given Away')
+                ) s
+            ) c
+        `
+}
+
+// GetOverduePlants lists every plant whose watering or fertilizing is
+// overdue as of the given day, most overdue first.
+func (s *PlantService) GetOverduePlants(asOf time.Time) ([]types.CareSchedule, error) {
+	query := `
+        SELECT plant_id, plant_name,
+               watering_every, watering_due, COALESCE(watering_status, '') as watering_status,
+               fertilizing_every, fertilizing_due, COALESCE(fertilizing_status, '') as fertilizing_status
+        FROM (` + careSchedule("$1::date") + `) care
+        WHERE care.watering_status = 'Overdue' OR care.fertilizing_status = 'Overdue'
+        ORDER BY LEAST(care.watering_due, care.fertilizing_due), care.plant_name
+    `
+	var due []types.CareSchedule
+	if err := s.db.Select(&due, query, asOf); err != nil {
+		return nil, fmt.Errorf("error fetching overdue plants: %w", err)
+	}
+	return due, nil
+}
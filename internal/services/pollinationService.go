@@ -0,0 +1,243 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pepper-analytics-ai/internal/types"
+	"time"
+)
+
+var (
+	ErrPollinationNotFound = errors.New("pollination not found")
+	ErrPollinationNotSet   = errors.New("pollination did not set fruit")
+	ErrSeedAlreadySaved    = errors.New("seed from this pollination is already saved")
+	ErrOutcomeLocked       = errors.New("outcome cannot change once seed is saved")
+)
+
+type PollinationService struct {
+	db *sqlx.DB
+}
+
+func NewPollinationService(db *sqlx.DB) *PollinationService {
+	return &PollinationService{db: db}
+}
+
+const pollinationSelect = `
+        SELECT po.*,
+               p.name as plant_name,
+               d.name as donor_name,
+               (
+                   SELECT MIN(sl.id)
+                   FROM seed_lots sl
+                   WHERE sl.pollination_id = po.id AND sl.deleted_at IS NULL
+               ) as seed_lot_id
+        FROM pollinations po
+        JOIN plants p ON po.plant_id = p.id
+        LEFT JOIN plants d ON po.donor_plant_id = d.id
+`
+
+func (s *PollinationService) GetPlantPollinations(plantID int) ([]types.Pollination, error) {
+	query := pollinationSelect + `
+        WHERE po.plant_id = $1 AND po.deleted_at IS NULL
+        ORDER BY po.pollination_date DESC, po.id DESC
+    `
+	var pollinations []types.Pollination
+	if err := s.db.Select(&pollinations, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching pollinations: %w", err)
+	}
+	return pollinations, nil
+}
+
+// GetOpenPollinations lists pollinations still waiting for an outcome.
+func (s *PollinationService) GetOpenPollinations() ([]types.Pollination, error) {
+	query := pollinationSelect + `
+        WHERE po.outcome = 'Pending' AND po.deleted_at IS NULL AND p.deleted_at IS NULL
+        ORDER BY po.pollination_date, po.id
+    `
+	var pollinations []types.Pollination
+	if err := s.db.Select(&pollinations, query); err != nil {
+		return nil, fmt.Errorf("error fetching open pollinations: %w", err)
+	}
+	return pollinations, nil
+}
+
+func (s *PollinationService) GetPollination(plantID, id int) (*types.Pollination, error) {
+	query := pollinationSelect + `
+        WHERE po.id = $1 AND po.plant_id = $2 AND po.deleted_at IS NULL
+    `
+	var pollination types.Pollination
+	if err := s.db.Get(&pollination, query, id, plantID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPollinationNotFound
+		}
+		return nil, fmt.Errorf("error fetching pollination: %w", err)
+	}
+	return &pollination, nil
+}
+
+func (s *PollinationService) CreatePollination(pollination *types.Pollination) error {
+	query := `
+        INSERT INTO pollinations (
+            plant_id, donor_plant_id, donor_external, pollination_date,
+            flower_tag, emasculated, isolation_method, outcome, notes
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING id, created_at, updated_at
+    `
+	err := s.db.QueryRow(
+		query,
+		pollination.PlantID,
+		pollination.DonorPlantID,
+		pollination.DonorExternal,
+		pollination.PollinationDate,
+		pollination.FlowerTag,
+		pollination.Emasculated,
+		pollination.IsolationMethod,
+		pollination.Outcome,
+		pollination.Notes,
+	).Scan(&pollination.ID, &pollination.CreatedAt, &pollination.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating pollination: %w", err)
+	}
+	return nil
+}
+
+// RecordOutcome marks whether the pollinated flower set fruit or dropped.
+// The outcome is fixed once seed from the pod has been saved.
+func (s *PollinationService) RecordOutcome(plantID, id int, outcome types.PollinationOutcome, outcomeDate time.Time) error {
+	query := `
+        UPDATE pollinations po
+        SET outcome = $1,
+            outcome_date = $2,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE po.id = $3 AND po.plant_id = $4 AND po.deleted_at IS NULL
+        AND NOT EXISTS (
+            SELECT 1 FROM seed_lots sl
+            WHERE sl.pollination_id = po.id AND sl.deleted_at IS NULL
+        )
+    `
+	date := sql.NullTime{Time: outcomeDate, Valid: outcome != types.PollinationPending}
+	result, err := s.db.Exec(query, outcome, date, id, plantID)
+	if err != nil {
+		return fmt.Errorf("error recording pollination outcome: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		if _, err := s.GetPollination(plantID, id); err != nil {
+			return err
+		}
+		return ErrOutcomeLocked
+	}
+	return nil
+}
+
+func (s *PollinationService) DeletePollination(plantID, id int) error {
+	query := `
+        UPDATE pollinations
+        SET deleted_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND plant_id = $2 AND deleted_at IS NULL
+    `
+	result, err := s.db.Exec(query, id, plantID)
+	if err != nil {
+		return fmt.Errorf("error deleting pollination: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrPollinationNotFound
+	}
+	return nil
+}
+
+// SaveSeed records the seed from a pollinated pod as a seed lot whose pollen
+// parent is the donor, so plants sown from it get the full cross pedigree.
+func (s *PollinationService) SaveSeed(plantID, id int, seedCount int, harvestDate time.Time) (*types.SeedLot, error) {
+	pollination, err := s.GetPollination(plantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if pollination.Outcome != types.PollinationSet {
+		return nil, ErrPollinationNotSet
+	}
+	if pollination.SeedLotID.Valid {
+		return nil, ErrSeedAlreadySaved
+	}
+
+	lot := &types.SeedLot{
+		PlantID:         pollination.PlantID,
+		PollenParentID:  pollination.DonorPlantID,
+		PollenParentExt: pollination.DonorExternal,
+		SeedCount:       seedCount,
+		HarvestDate:     harvestDate,
+		PollinationID:   sql.NullInt64{Int64: int64(pollination.ID), Valid: true},
+	}
+	if pollination.FlowerTag.Valid {
+		lot.GerminationNotes = sql.NullString{String: "Flower tag " + pollination.FlowerTag.String, Valid: true}
+	}
+
+	if err := insertSeedLot(s.db, lot); err != nil {
+		// Two saves racing past the check above meet the unique index
+		if isUniqueViolation(err) {
+			return nil, ErrSeedAlreadySaved
+		}
+		return nil, err
+	}
+	return lot, nil
+}
+
+const pollinationStatsColumns = `
+               COUNT(*) as attempts,
+               COUNT(*) FILTER (WHERE po.outcome = 'Set') as set_count,
+               COUNT(*) FILTER (WHERE po.outcome = 'Dropped') as dropped_count,
+               COUNT(*) FILTER (WHERE po.outcome = 'Pending') as pending_count
+`
+
+// GetPairSuccessRates tallies outcomes per recipient x donor pair. Pairs are
+// told apart by plant, so two plants sharing a name are not lumped together.
+func (s *PollinationService) GetPairSuccessRates() ([]types.PollinationStats, error) {
+	query := `
+        SELECT p.name || ' x ' || CASE
+                   WHEN po.donor_plant_id = po.plant_id THEN 'self'
+                   ELSE COALESCE(d.name, po.donor_external, 'unknown')
+               END as label,` + pollinationStatsColumns + `
+        FROM pollinations po
+        JOIN plants p ON po.plant_id = p.id
+        LEFT JOIN plants d ON po.donor_plant_id = d.id
+        WHERE po.deleted_at IS NULL
+        GROUP BY po.plant_id, po.donor_plant_id, po.donor_external, p.name, d.name
+        ORDER BY attempts DESC, label, po.plant_id, po.donor_plant_id
+    `
+	var stats []types.PollinationStats
+	if err := s.db.Select(&stats, query); err != nil {
+		return nil, fmt.Errorf("error fetching pair success rates: %w", err)
+	}
+	return stats, nil
+}
+
+// GetTechniqueSuccessRates tallies outcomes per isolation method, split by
+// whether the flower was emasculated.
+func (s *PollinationService) GetTechniqueSuccessRates() ([]types.PollinationStats, error) {
+	query := `
+        SELECT po.isolation_method || CASE
+                   WHEN po.emasculated THEN ', emasculated'
+                   ELSE ''
+               END as label,` + pollinationStatsColumns + `
+        FROM pollinations po
+        WHERE po.deleted_at IS NULL
+        GROUP BY 1
+        ORDER BY attempts DESC, label
+    `
+	var stats []types.PollinationStats
+	if err := s.db.Select(&stats, query); err != nil {
+		return nil, fmt.Errorf("error fetching technique success rates: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pepper-analytics-ai/internal/types"
+)
+
+var (
+	ErrProjectNotFound = errors.New("project not found")
+)
+
+type ProjectService struct {
+	db *sqlx.DB
+}
+
+func NewProjectService(db *sqlx.DB) *ProjectService {
+	return &ProjectService{db: db}
+}
+
+const projectSelect = `
+        SELECT bp.*,
+               (
+                   SELECT COUNT(*)
+                   FROM project_plants pp
+                   JOIN plants p ON pp.plant_id = p.id
+                   WHERE pp.project_id = bp.id AND p.deleted_at IS NULL
+               ) as plant_count
+        FROM breeding_projects bp
+`
+
+func (s *ProjectService) GetProjects() ([]types.BreedingProject, error) {
+	query := projectSelect + `
+        WHERE bp.deleted_at IS NULL
+        ORDER BY bp.status = 'Active' DESC, bp.name
+    `
+	var projects []types.BreedingProject
+	if err := s.db.Select(&projects, query); err != nil {
+		return nil, fmt.Errorf("error fetching projects: %w", err)
+	}
+	return projects, nil
+}
+
+func (s *ProjectService) GetProject(id int) (*types.BreedingProject, error) {
+	query := projectSelect + `
+        WHERE bp.id = $1 AND bp.deleted_at IS NULL
+    `
+	var project types.BreedingProject
+	if err := s.db.Get(&project, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("error fetching project: %w", err)
+	}
+	return &project, nil
+}
+
+func (s *ProjectService) CreateProject(project *types.BreedingProject) error {
+	query := `
+        INSERT INTO breeding_projects (name, goal, status, started_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at, updated_at
+    `
+	err := s.db.QueryRow(
+		query,
+		project.Name,
+		project.Goal,
+		project.Status,
+		project.StartedAt,
+	).Scan(&project.ID, &project.CreatedAt, &project.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating project: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectService) UpdateProject(project *types.BreedingProject) error {
+	query := `
+        UPDATE breeding_projects
+        SET name = $1,
+            goal = $2,
+            status = $3,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $4 AND deleted_at IS NULL
+        RETURNING updated_at
+    `
+	err := s.db.QueryRow(
+		query,
+		project.Name,
+		project.Goal,
+		project.Status,
+		project.ID,
+	).Scan(&project.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrProjectNotFound
+		}
+		return fmt.Errorf("error updating project: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectService) DeleteProject(id int) error {
+	query := `UPDATE breeding_projects SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	result, err := s.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting project: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func (s *ProjectService) GetTargetTraits(projectID int) ([]types.ProjectTargetTrait, error) {
+	query := `
+        SELECT pt.*,
+               td.name as trait_name,
+               td.unit,
+               st.label as state_label
+        FROM project_target_traits pt
+        JOIN trait_definitions td ON pt.trait_id = td.id
+        LEFT JOIN trait_states st ON st.trait_id = pt.trait_id AND st.value = pt.state_value
+        WHERE pt.project_id = $1 AND td.deleted_at IS NULL
+        ORDER BY td.sort_order, td.name
+    `
+	var targets []types.ProjectTargetTrait
+	if err := s.db.Select(&targets, query, projectID); err != nil {
+		return nil, fmt.Errorf("error fetching target traits: %w", err)
+	}
+	return targets, nil
+}
+
+// SetTargetTrait adds a target trait to a project, replacing any earlier
+// target for the same trait.
+func (s *ProjectService) SetTargetTrait(target *types.ProjectTargetTrait) error {
+	query := `
+        INSERT INTO project_target_traits (
+            project_id, trait_id, state_value, min_value, max_value, notes
+        ) VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (project_id, trait_id) DO UPDATE
+        SET state_value = EXCLUDED.state_value,
+            min_value = EXCLUDED.min_value,
+            max_value = EXCLUDED.max_value,
+            notes = EXCLUDED.notes
+    `
+	_, err := s.db.Exec(
+		query,
+		target.ProjectID,
+		target.TraitID,
+		target.StateValue,
+		target.MinValue,
+		target.MaxValue,
+		target.Notes,
+	)
+	if err != nil {
+		return fmt.Errorf("error setting target trait: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectService) RemoveTargetTrait(projectID, traitID int) error {
+	_, err := s.db.Exec(`DELETE FROM project_target_traits WHERE project_id = $1 AND trait_id = $2`, projectID, traitID)
+	if err != nil {
+		return fmt.Errorf("error removing target trait: %w", err)
+	}
+	return nil
+}
+
+// GetMembers lists the living plants of a project, oldest generation first.
+func (s *ProjectService) GetMembers(projectID int) ([]types.ProjectMember, error) {
+	query := `
+        SELECT p.id, p.name, p.species, p.generation, p.growth_stage,
+               p.season_finished, p.selection_status
+        FROM project_plants pp
+        JOIN plants p ON pp.plant_id = p.id
+        WHERE pp.project_id = $1 AND p.deleted_at IS NULL
+        ORDER BY p.generation NULLS FIRST, p.name, p.id
+    `
+	var members []types.ProjectMember
+	if err := s.db.Select(&members, query, projectID); err != nil {
+		return nil, fmt.Errorf("error fetching project members: %w", err)
+	}
+	return members, nil
+}
+
+func (s *ProjectService) AddPlant(projectID, plantID int) error {
+	query := `
+        INSERT INTO project_plants (project_id, plant_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING
+    `
+	if _, err := s.db.Exec(query, projectID, plantID); err != nil {
+		return fmt.Errorf("error adding plant to project: %w", err)
+	}
+	return nil
+}
+
+// AddDescendants adds every living descendant of the project's plants, so
+// later generations join the project without being added one by one. It
+// returns the number of plants added.
+func (s *ProjectService) AddDescendants(projectID int) (int, error) {
+	query := `
+        WITH RECURSIVE descendants(plant_id, depth) AS (
+            SELECT p.id, 1
+            FROM plants p
+            JOIN project_plants pp ON pp.plant_id IN (p.seed_parent_id, p.pollen_parent_id)
+            WHERE pp.project_id = $1 AND p.deleted_at IS NULL
+            UNION
+            SELECT p.id, d.depth + 1
+            FROM plants p
+            JOIN descendants d ON d.plant_id IN (p.seed_parent_id, p.pollen_parent_id)
+            WHERE p.deleted_at IS NULL AND d.depth < $2
+        )
+        INSERT INTO project_plants (project_id, plant_id)
+        SELECT DISTINCT $1::int4, plant_id FROM descendants
+        ON CONFLICT DO NOTHING
+    `
+	result, err := s.db.Exec(query, projectID, maxPedigreeDepth)
+	if err != nil {
+		return 0, fmt.Errorf("error adding descendants to project: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
+
+func (s *ProjectService) RemovePlant(projectID, plantID int) error {
+	_, err := s.db.Exec(`DELETE FROM project_plants WHERE project_id = $1 AND plant_id = $2`, projectID, plantID)
+	if err != nil {
+		return fmt.Errorf("error removing plant from project: %w", err)
+	}
+	return nil
+}
+
+// GetGenerationSummary counts the project's plants and keep/cull decisions
+// per generation.
+func (s *ProjectService) GetGenerationSummary(projectID int) ([]types.GenerationSummary, error) {
+	query := `
+        SELECT COALESCE(p.generation, 'Unknown') as generation,
+               COUNT(*) as plants,
+               COUNT(*) FILTER (WHERE p.selection_status = 'Keep') as keep_count,
+               COUNT(*) FILTER (WHERE p.selection_status = 'Cull') as cull_count,
+               COUNT(*) FILTER (WHERE p.selection_status = 'Undecided') as undecided_count
+        FROM project_plants pp
+        JOIN plants p ON pp.plant_id = p.id
+        WHERE pp.project_id = $1 AND p.deleted_at IS NULL
+        GROUP BY 1
+        ORDER BY 1
+    `
+	var summary []types.GenerationSummary
+	if err := s.db.Select(&summary, query, projectID); err != nil {
+		return nil, fmt.Errorf("error fetching generation summary: %w", err)
+	}
+	return summary, nil
+}
+
+// GetOpenPollinations lists pending pollinations on the project's plants.
+func (s *ProjectService) GetOpenPollinations(projectID int) ([]types.Pollination, error) {
+	query := pollinationSelect + `
+        JOIN project_plants pp ON pp.plant_id = po.plant_id
+        WHERE pp.project_id = $1
+        AND po.outcome = 'Pending'
+        AND po.deleted_at IS NULL
+        AND p.deleted_at IS NULL
+        ORDER BY po.pollination_date, po.id
+    `
+	var pollinations []types.Pollination
+	if err := s.db.Select(&pollinations, query, projectID); err != nil {
+		return nil, fmt.Errorf("error fetching project pollinations: %w", err)
+	}
+	return pollinations, nil
+}
+
+func (s *ProjectService) GetDashboard(projectID int) (*types.ProjectDashboard, error) {
+	project, err := s.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &types.ProjectDashboard{Project: *project}
+	if dashboard.Targets, err = s.GetTargetTraits(projectID); err != nil {
+		return nil, err
+	}
+	if dashboard.Generations, err = s.GetGenerationSummary(projectID); err != nil {
+		return nil, err
+	}
+	if dashboard.Members, err = s.GetMembers(projectID); err != nil {
+		return nil, err
+	}
+	if dashboard.OpenPollinations, err = s.GetOpenPollinations(projectID); err != nil {
+		return nil, err
+	}
+	return dashboard, nil
+}
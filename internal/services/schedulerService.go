@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+	"os"
+	"pepper-analytics-ai/internal/types"
+	"time"
+)
+
+var (
+	ErrJobNotFound     = errors.New("job not found")
+	ErrJobDisabled     = errors.New("job is disabled")
+	ErrInvalidSchedule = errors.New("invalid schedule")
+)
+
+// JobFunc does a job's work and returns a one-line summary for the run
+// history.
+type JobFunc func(ctx context.Context) (string, error)
+
+type scheduledJob struct {
+	name        string
+	description string
+	schedule    string
+	run         JobFunc
+}
+
+// SchedulerService runs background jobs on cron schedules inside the server
+// process. Schedules, last and next run times live in scheduled_jobs so
+// they survive restarts and can be changed from the admin page. Each run
+// takes a Postgres advisory lock on the job, so when several servers share
+// the database only one of them runs it.
+type SchedulerService struct {
+	db   *sqlx.DB
+	jobs []scheduledJob
+	host string
+}
+
+func NewSchedulerService(db *sqlx.DB) *SchedulerService {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return &SchedulerService{
+		db:   db,
+		host: host,
+	}
+}
+
+// Register adds a job for Start to run. schedule is only the default: once
+// the job is in the database, the schedule saved there wins.
+func (s *SchedulerService) Register(name, description, schedule string, run JobFunc) error {
+	if _, err := types.ParseCronSchedule(schedule); err != nil {
+		return fmt.Errorf("%w for %s: %v", ErrInvalidSchedule, name, err)
+	}
+	s.jobs = append(s.jobs, scheduledJob{name: name, description: description, schedule: schedule, run: run})
+	return nil
+}
+
+// Start saves the registered jobs and then checks for due jobs every tick
+// until ctx is done. A job that was due while no server was running runs
+// once, not once per missed slot.
+func (s *SchedulerService) Start(ctx context.Context, tick time.Duration) error {
+	now := time.Now()
+	var names []string
+	for _, job := range s.jobs {
+		schedule, _ := types.ParseCronSchedule(job.schedule)
+		query := `
+            INSERT INTO scheduled_jobs (name, description, schedule, next_run_at)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description
+        `
+		if _, err := s.db.Exec(query, job.name, job.description, job.schedule, nullTime(schedule.Next(now))); err != nil {
+			return fmt.Errorf("error saving job %s: %w", job.name, err)
+		}
+		names = append(names, job.name)
+	}
+
+	// Runs this server left behind when it last stopped will never finish
+	query := `
+        UPDATE job_runs SET status = 'Failed', message = 'Interrupted by a restart', finished_at = $1
+        WHERE status = 'Running' AND host = $2 AND job_name = ANY($3)
+    `
+	if _, err := s.db.Exec(query, now, s.host, pq.Array(names)); err != nil {
+		return fmt.Errorf("error closing interrupted job runs: %w", err)
+	}
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		s.runDue(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func (s *SchedulerService) runDue(ctx context.Context, now time.Time) {
+	for _, job := range s.jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.runIfDue(ctx, job, now); err != nil {
+			log.Printf("Warning: Error running job %s: %v", job.name, err)
+		}
+	}
+}
+
+// runIfDue runs the job if it is due and no other server holds its lock.
+// The lock is a session lock, so it is taken and released on one pooled
+// connection.
+func (s *SchedulerService) runIfDue(ctx context.Context, job scheduledJob, now time.Time) error {
+	var due bool
+	query := `SELECT EXISTS (SELECT 1 FROM scheduled_jobs WHERE name = $1 AND enabled AND next_run_at <= $2)`
+	if err := s.db.Get(&due, query, job.name, now); err != nil {
+		return fmt.Errorf("error checking job: %w", err)
+	}
+	if !due {
+		return nil
+	}
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	lockKey := "scheduled_job:" + job.name
+	var locked bool
+	if err := conn.GetContext(ctx, &locked, `SELECT pg_try_advisory_lock(hashtext($1))`, lockKey); err != nil {
+		return fmt.Errorf("error taking job lock: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, lockKey); err != nil {
+			log.Printf("Warning: Error releasing lock on job %s: %v", job.name, err)
+		}
+	}()
+
+	// Another server may have run the job between the check and the lock
+	var stored types.ScheduledJob
+	err = conn.GetContext(ctx, &stored, `SELECT * FROM scheduled_jobs WHERE name = $1`, job.name)
+	if err != nil {
+		return fmt.Errorf("error fetching job: %w", err)
+	}
+	if !stored.Enabled || !stored.NextRunAt.Valid || stored.NextRunAt.Time.After(now) {
+		return nil
+	}
+
+	schedule, err := types.ParseCronSchedule(stored.Schedule)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	query = `UPDATE scheduled_jobs SET last_run_at = $1, next_run_at = $2 WHERE name = $3`
+	if _, err := conn.ExecContext(ctx, query, now, nullTime(schedule.Next(now)), job.name); err != nil {
+		return fmt.Errorf("error updating job: %w", err)
+	}
+
+	var runID int
+	query = `INSERT INTO job_runs (job_name, host, started_at) VALUES ($1, $2, $3) RETURNING id`
+	if err := conn.GetContext(ctx, &runID, query, job.name, s.host, now); err != nil {
+		return fmt.Errorf("error recording job run: %w", err)
+	}
+
+	message, runErr := runJob(ctx, job)
+	status := types.JobSucceeded
+	if runErr != nil {
+		status = types.JobFailed
+		message = runErr.Error()
+		log.Printf("Warning: Job %s failed: %v", job.name, runErr)
+	}
+
+	query = `UPDATE job_runs SET status = $1, message = NULLIF($2, ''), finished_at = $3 WHERE id = $4`
+	if _, err := conn.ExecContext(context.Background(), query, status, message, time.Now(), runID); err != nil {
+		return fmt.Errorf("error recording job result: %w", err)
+	}
+	return nil
+}
+
+// runJob runs the job, turning a panic into a failed run so one bad job
+// does not take the server down.
+func runJob(ctx context.Context, job scheduledJob) (message string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.run(ctx)
+}
+
+// GetJobs lists the jobs with the outcome of their latest run.
+func (s *SchedulerService) GetJobs() ([]types.ScheduledJob, error) {
+	query := `
+        SELECT sj.*, lr.status as last_status, lr.message as last_message
+        FROM scheduled_jobs sj
+        LEFT JOIN LATERAL (
+            SELECT jr.status, jr.message
+            FROM job_runs jr
+            WHERE jr.job_name = sj.name
+            ORDER BY jr.started_at DESC, jr.id DESC
+            LIMIT 1
+        ) lr ON true
+        ORDER BY sj.name
+    `
+	var jobs []types.ScheduledJob
+	if err := s.db.Select(&jobs, query); err != nil {
+		return nil, fmt.Errorf("error fetching jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// GetRuns lists the newest runs, of one job or of all of them when jobName
+// is empty.
+func (s *SchedulerService) GetRuns(jobName string, limit int) ([]types.JobRun, error) {
+	query := `
+        SELECT * FROM job_runs
+        WHERE ($1 = '' OR job_name = $1)
+        ORDER BY started_at DESC, id DESC
+        LIMIT $2
+    `
+	var runs []types.JobRun
+	if err := s.db.Select(&runs, query, jobName, limit); err != nil {
+		return nil, fmt.Errorf("error fetching job runs: %w", err)
+	}
+	return runs, nil
+}
+
+// SetSchedule changes when a job runs and moves its next run to match.
+func (s *SchedulerService) SetSchedule(name, spec string) error {
+	schedule, err := types.ParseCronSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	next := schedule.Next(time.Now())
+	if next.IsZero() {
+		return fmt.Errorf("%w: %s never runs", ErrInvalidSchedule, spec)
+	}
+
+	query := `UPDATE scheduled_jobs SET schedule = $1, next_run_at = $2, updated_at = CURRENT_TIMESTAMP WHERE name = $3`
+	return s.updateJob(query, schedule.String(), next, name)
+}
+
+// SetEnabled pauses or resumes a job. A resumed job picks up at its next
+// slot rather than running for the ones it missed.
+func (s *SchedulerService) SetEnabled(name string, enabled bool) error {
+	var job types.ScheduledJob
+	if err := s.db.Get(&job, `SELECT * FROM scheduled_jobs WHERE name = $1`, name); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrJobNotFound
+		}
+		return fmt.Errorf("error fetching job: %w", err)
+	}
+
+	next := job.NextRunAt
+	if enabled {
+		if schedule, err := types.ParseCronSchedule(job.Schedule); err == nil {
+			next = nullTime(schedule.Next(time.Now()))
+		}
+	}
+
+	query := `UPDATE scheduled_jobs SET enabled = $1, next_run_at = $2, updated_at = CURRENT_TIMESTAMP WHERE name = $3`
+	return s.updateJob(query, enabled, next, name)
+}
+
+// RunNow makes a job due straight away. The server whose scheduler next
+// checks for due jobs runs it.
+func (s *SchedulerService) RunNow(name string) error {
+	var enabled bool
+	if err := s.db.Get(&enabled, `SELECT enabled FROM scheduled_jobs WHERE name = $1`, name); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrJobNotFound
+		}
+		return fmt.Errorf("error fetching job: %w", err)
+	}
+	if !enabled {
+		return ErrJobDisabled
+	}
+
+	query := `UPDATE scheduled_jobs SET next_run_at = $1, updated_at = CURRENT_TIMESTAMP WHERE name = $2`
+	return s.updateJob(query, time.Now(), name)
+}
+
+func (s *SchedulerService) updateJob(query string, args ...interface{}) error {
+	result, err := s.db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("error updating job: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrJobNotFound
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pepper-analytics-ai/internal/types"
+)
+
+var (
+	ErrSeedLotNotFound = errors.New("seed lot not found")
+	ErrNotEnoughSeeds  = errors.New("not enough seeds left in lot")
+)
+
+type SeedLotService struct {
+	db *sqlx.DB
+}
+
+func NewSeedLotService(db *sqlx.DB) *SeedLotService {
+	return &SeedLotService{db: db}
+}
+
+const seedLotSelect = `
+        SELECT sl.*,
+               p.name as plant_name,
+               p.species as plant_species,
+               p.generation as plant_generation,
+               pp.name as pollen_parent_name,
+               v.id as variety_id,
+               v.name as variety_name,
+               (
+                   SELECT SUM(t.germinated) * 100.0 / SUM(t.seeds_sown)
+                   FROM (
+                       SELECT gt.seeds_sown,
+                              (SELECT COALESCE(SUM(c.germinated), 0) FROM germination_counts c WHERE c.trial_id = gt.id) as germinated
+                       FROM germination_trials gt
+                       WHERE gt.seed_lot_id = sl.id AND gt.deleted_at IS NULL
+                   ) t
+               ) as germination_rate
+        FROM seed_lots sl
+        JOIN plants p ON sl.plant_id = p.id
+        LEFT JOIN plants pp ON sl.pollen_parent_id = pp.id
+        LEFT JOIN varieties v ON p.variety_id = v.id AND v.deleted_at IS NULL
+`
+
+func (s *SeedLotService) GetSeedLots() ([]types.SeedLot, error) {
+	query := seedLotSelect + `
+        WHERE sl.deleted_at IS NULL
+        ORDER BY COALESCE(v.name, p.name), p.name, sl.harvest_date DESC
+    `
+	var lots []types.SeedLot
+	if err := s.db.Select(&lots, query); err != nil {
+		return nil, fmt.Errorf("error fetching seed lots: %w", err)
+	}
+	return lots, nil
+}
+
+// GetAvailableSeedLots lists lots that still have seed to sow.
+func (s *SeedLotService) GetAvailableSeedLots() ([]types.SeedLot, error) {
+	query := seedLotSelect + `
+        WHERE sl.deleted_at IS NULL AND sl.seed_count > 0
+        ORDER BY p.name, sl.harvest_date DESC
+    `
+	var lots []types.SeedLot
+	if err := s.db.Select(&lots, query); err != nil {
+		return nil, fmt.Errorf("error fetching available seed lots: %w", err)
+	}
+	return lots, nil
+}
+
+func (s *SeedLotService) GetPlantSeedLots(plantID int) ([]types.SeedLot, error) {
+	query := seedLotSelect + `
+        WHERE sl.plant_id = $1 AND sl.deleted_at IS NULL
+        ORDER BY sl.harvest_date DESC
+    `
+	var lots []types.SeedLot
+	if err := s.db.Select(&lots, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching plant seed lots: %w", err)
+	}
+	return lots, nil
+}
+
+func (s *SeedLotService) GetSeedLot(id int) (*types.SeedLot, error) {
+	query := seedLotSelect + `
+        WHERE sl.id = $1 AND sl.deleted_at IS NULL
+    `
+	var lot types.SeedLot
+	if err := s.db.Get(&lot, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSeedLotNotFound
+		}
+		return nil, fmt.Errorf("error fetching seed lot: %w", err)
+	}
+	return &lot, nil
+}
+
+// GetSeedLotsByVariety groups the inventory by the source plant's variety.
+// Lots off plants without a variety, such as unnamed crosses, are grouped by
+// the plant's name.
+func (s *SeedLotService) GetSeedLotsByVariety() ([]types.SeedLotGroup, error) {
+	lots, err := s.GetSeedLots()
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []types.SeedLotGroup
+	index := make(map[string]int)
+	for _, lot := range lots {
+		key := "plant:" + lot.PlantName
+		variety := lot.PlantName
+		if lot.VarietyID.Valid {
+			key = fmt.Sprintf("variety:%d", lot.VarietyID.Int64)
+			variety = lot.VarietyName.String
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, types.SeedLotGroup{
+				VarietyID: lot.VarietyID,
+				Variety:   variety,
+				Species:   lot.PlantSpecies,
+			})
+		}
+		groups[i].TotalSeeds += lot.SeedCount
+		groups[i].Lots = append(groups[i].Lots, lot)
+	}
+	return groups, nil
+}
+
+func (s *SeedLotService) CreateSeedLot(lot *types.SeedLot) error {
+	return insertSeedLot(s.db, lot)
+}
+
+func insertSeedLot(q queryRower, lot *types.SeedLot) error {
+	query := `
+        INSERT INTO seed_lots (
+            plant_id, pollen_parent_id, pollen_parent_external, seed_count,
+            harvest_date, storage_location, germination_notes, harvest_id,
+            pollination_id
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING id, created_at, updated_at
+    `
+	err := q.QueryRow(
+		query,
+		lot.PlantID,
+		lot.PollenParentID,
+		lot.PollenParentExt,
+		lot.SeedCount,
+		lot.HarvestDate,
+		lot.StorageLocation,
+		lot.GerminationNotes,
+		lot.HarvestID,
+		lot.PollinationID,
+	).Scan(&lot.ID, &lot.CreatedAt, &lot.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating seed lot: %w", err)
+	}
+	return nil
+}
+
+func (s *SeedLotService) UpdateSeedLot(lot *types.SeedLot) error {
+	query := `
+        UPDATE seed_lots
+        SET seed_count = $1,
+            storage_location = $2,
+            germination_notes = $3,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $4 AND deleted_at IS NULL
+        RETURNING updated_at
+    `
+	err := s.db.QueryRow(
+		query,
+		lot.SeedCount,
+		lot.StorageLocation,
+		lot.GerminationNotes,
+		lot.ID,
+	).Scan(&lot.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrSeedLotNotFound
+		}
+		return fmt.Errorf("error updating seed lot: %w", err)
+	}
+	return nil
+}
+
+func (s *SeedLotService) DeleteSeedLot(id int) error {
+	query := `UPDATE seed_lots SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	result, err := s.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting seed lot: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrSeedLotNotFound
+	}
+	return nil
+}
+
+// consumeSeeds takes seeds out of a lot inside the caller's transaction and
+// fails rather than letting the count go negative.
+func consumeSeeds(tx *sqlx.Tx, lotID int, count int) error {
+	query := `
+        UPDATE seed_lots
+        SET seed_count = seed_count - $1,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND deleted_at IS NULL AND seed_count >= $1
+    `
+	result, err := tx.Exec(query, count, lotID)
+	if err != nil {
+		return fmt.Errorf("error consuming seeds: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotEnoughSeeds
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pepper-analytics-ai/internal/types"
+)
+
+type SelectionService struct {
+	db *sqlx.DB
+}
+
+func NewSelectionService(db *sqlx.DB) *SelectionService {
+	return &SelectionService{db: db}
+}
+
+// RecordDecision stores a keep/cull decision, links the trait scores it
+// cites and updates the plant's current selection status, all in one
+// transaction.
+func (s *SelectionService) RecordDecision(decision *types.SelectionDecision) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+        UPDATE plants
+        SET selection_status = $1,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND deleted_at IS NULL
+    `, decision.Status, decision.PlantID)
+	if err != nil {
+		return fmt.Errorf("error updating selection status: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrPlantNotFound
+	}
+
+	query := `
+        INSERT INTO selection_decisions (plant_id, status, reason, decided_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at
+    `
+	err = tx.QueryRow(
+		query,
+		decision.PlantID,
+		decision.Status,
+		decision.Reason,
+		decision.DecidedAt,
+	).Scan(&decision.ID, &decision.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating selection decision: %w", err)
+	}
+
+	// Only scores taken on this plant can be cited
+	if len(decision.TraitScoreIDs) > 0 {
+		_, err = tx.Exec(`
+            INSERT INTO selection_decision_scores (decision_id, trait_score_id)
+            SELECT $1, id FROM trait_scores
+            WHERE id = ANY($2) AND plant_id = $3
+        `, decision.ID, pq.Array(decision.TraitScoreIDs), decision.PlantID)
+		if err != nil {
+			return fmt.Errorf("error linking decision scores: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPlantDecisions returns a plant's selection history, newest first, with
+// the cited trait scores loaded.
+func (s *SelectionService) GetPlantDecisions(plantID int) ([]types.SelectionDecision, error) {
+	query := `
+        SELECT *
+        FROM selection_decisions
+        WHERE plant_id = $1
+        ORDER BY decided_at DESC, id DESC
+    `
+	var decisions []types.SelectionDecision
+	if err := s.db.Select(&decisions, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching selection decisions: %w", err)
+	}
+
+	var cited []struct {
+		DecisionID int `db:"decision_id"`
+		types.TraitScore
+	}
+	query = `
+        SELECT sds.decision_id,
+               ts.*,
+               td.name as trait_name,
+               td.unit,
+               st.label as state_label
+        FROM selection_decision_scores sds
+        JOIN selection_decisions sd ON sds.decision_id = sd.id
+        JOIN trait_scores ts ON sds.trait_score_id = ts.id
+        JOIN trait_definitions td ON ts.trait_id = td.id
+        LEFT JOIN trait_states st ON st.trait_id = ts.trait_id AND st.value = ts.state_value
+        WHERE sd.plant_id = $1
+        ORDER BY td.sort_order
+    `
+	if err := s.db.Select(&cited, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching decision scores: %w", err)
+	}
+
+	index := make(map[int]int, len(decisions))
+	for i, decision := range decisions {
+		index[decision.ID] = i
+	}
+	for _, score := range cited {
+		if i, ok := index[score.DecisionID]; ok {
+			decisions[i].Scores = append(decisions[i].Scores, score.TraitScore)
+		}
+	}
+	return decisions, nil
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pepper-analytics-ai/internal/types"
+	"strings"
+)
+
+var (
+	ErrSpeciesNotFound  = errors.New("species not found")
+	ErrUnknownSpecies   = errors.New("unknown species")
+	ErrDuplicateSpecies = errors.New("species or synonym already exists")
+	ErrSpeciesInUse     = errors.New("species is still used by plants")
+	ErrInvalidHybrid    = errors.New("a hybrid needs two different species as parents")
+)
+
+type SpeciesService struct {
+	db *sqlx.DB
+}
+
+func NewSpeciesService(db *sqlx.DB) *SpeciesService {
+	return &SpeciesService{db: db}
+}
+
+const taxonSelect = `
+        SELECT s.*,
+               pa.name as parent_a_name,
+               pb.name as parent_b_name,
+               COALESCE((
+                   SELECT string_agg(ss.synonym, ', ' ORDER BY ss.synonym)
+                   FROM species_synonyms ss
+                   WHERE ss.species_id = s.id
+               ), '') as synonyms,
+               (
+                   SELECT COUNT(*)
+                   FROM plants p
+                   WHERE p.species = s.name AND p.deleted_at IS NULL
+               ) as plant_count
+        FROM species s
+        LEFT JOIN species pa ON s.parent_a_id = pa.id
+        LEFT JOIN species pb ON s.parent_b_id = pb.id
+`
+
+// GetSpecies lists the taxonomy, species before hybrids.
+func (s *SpeciesService) GetSpecies() ([]types.Taxon, error) {
+	query := taxonSelect + `
+        WHERE s.deleted_at IS NULL
+        ORDER BY s.is_hybrid, s.name
+    `
+	var taxa []types.Taxon
+	if err := s.db.Select(&taxa, query); err != nil {
+		return nil, fmt.Errorf("error fetching species: %w", err)
+	}
+	return taxa, nil
+}
+
+func (s *SpeciesService) GetTaxon(id int) (*types.Taxon, error) {
+	query := taxonSelect + `
+        WHERE s.id = $1 AND s.deleted_at IS NULL
+    `
+	var taxon types.Taxon
+	if err := s.db.Get(&taxon, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSpeciesNotFound
+		}
+		return nil, fmt.Errorf("error fetching species: %w", err)
+	}
+	return &taxon, nil
+}
+
+// Resolve turns a typed species name into the name listed in the species
+// table. Synonyms resolve to the accepted name and a hybrid may be given by
+// its parents in either order, e.g. "C. frutescens x C. chinense".
+func (s *SpeciesService) Resolve(input string) (types.Species, error) {
+	name := types.NormalizeSpeciesName(input)
+	if name == "" {
+		return "", ErrUnknownSpecies
+	}
+
+	query := `
+        SELECT s.name
+        FROM species s
+        LEFT JOIN species_synonyms ss ON ss.species_id = s.id
+        WHERE s.deleted_at IS NULL
+        AND (lower(s.name) = lower($1) OR lower(ss.synonym) = lower($1))
+        LIMIT 1
+    `
+	var species types.Species
+	err := s.db.Get(&species, query, name)
+	if err == nil {
+		return species, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", fmt.Errorf("error resolving species: %w", err)
+	}
+
+	parentA, parentB, ok := types.SplitHybrid(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownSpecies, input)
+	}
+	idA, err := s.resolveID(parentA)
+	if err != nil {
+		return "", err
+	}
+	idB, err := s.resolveID(parentB)
+	if err != nil {
+		return "", err
+	}
+
+	query = `
+        SELECT name
+        FROM species
+        WHERE is_hybrid AND deleted_at IS NULL
+        AND ((parent_a_id = $1 AND parent_b_id = $2) OR (parent_a_id = $2 AND parent_b_id = $1))
+        LIMIT 1
+    `
+	if err := s.db.Get(&species, query, idA, idB); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%w: %s", ErrUnknownSpecies, input)
+		}
+		return "", fmt.Errorf("error resolving hybrid: %w", err)
+	}
+	return species, nil
+}
+
+// resolveID looks up a non-hybrid species by name or synonym.
+func (s *SpeciesService) resolveID(name string) (int, error) {
+	query := `
+        SELECT s.id
+        FROM species s
+        LEFT JOIN species_synonyms ss ON ss.species_id = s.id
+        WHERE s.deleted_at IS NULL AND NOT s.is_hybrid
+        AND (lower(s.name) = lower($1) OR lower(ss.synonym) = lower($1))
+        LIMIT 1
+    `
+	var id int
+	if err := s.db.Get(&id, query, name); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("%w: %s", ErrUnknownSpecies, name)
+		}
+		return 0, fmt.Errorf("error resolving species: %w", err)
+	}
+	return id, nil
+}
+
+func (s *SpeciesService) CreateSpecies(taxon *types.Taxon) error {
+	taxon.Name = types.Species(types.NormalizeSpeciesName(string(taxon.Name)))
+	if exists, err := s.nameTaken(string(taxon.Name)); err != nil {
+		return err
+	} else if exists {
+		return ErrDuplicateSpecies
+	}
+
+	query := `
+        INSERT INTO species (name, notes)
+        VALUES ($1, $2)
+        RETURNING id, created_at, updated_at
+    `
+	err := s.db.QueryRow(query, taxon.Name, taxon.Notes).Scan(&taxon.ID, &taxon.CreatedAt, &taxon.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicateSpecies
+		}
+		return fmt.Errorf("error creating species: %w", err)
+	}
+	return nil
+}
+
+// CreateHybrid adds an interspecific hybrid named after its parents.
+func (s *SpeciesService) CreateHybrid(parentAID, parentBID int, notes sql.NullString) (*types.Taxon, error) {
+	if parentAID == parentBID {
+		return nil, ErrInvalidHybrid
+	}
+
+	parentA, err := s.GetTaxon(parentAID)
+	if err != nil {
+		return nil, err
+	}
+	parentB, err := s.GetTaxon(parentBID)
+	if err != nil {
+		return nil, err
+	}
+	if parentA.IsHybrid || parentB.IsHybrid {
+		return nil, ErrInvalidHybrid
+	}
+
+	if _, err := s.Resolve(string(parentA.Name) + " x " + string(parentB.Name)); err == nil {
+		return nil, ErrDuplicateSpecies
+	} else if !errors.Is(err, ErrUnknownSpecies) {
+		return nil, err
+	}
+
+	hybrid := &types.Taxon{
+		Name:      types.HybridName(parentA.Name, parentB.Name),
+		IsHybrid:  true,
+		ParentAID: sql.NullInt64{Int64: int64(parentAID), Valid: true},
+		ParentBID: sql.NullInt64{Int64: int64(parentBID), Valid: true},
+		Notes:     notes,
+	}
+	query := `
+        INSERT INTO species (name, is_hybrid, parent_a_id, parent_b_id, notes)
+        VALUES ($1, true, $2, $3, $4)
+        RETURNING id, created_at, updated_at
+    `
+	err = s.db.QueryRow(query, hybrid.Name, hybrid.ParentAID, hybrid.ParentBID, hybrid.Notes).
+		Scan(&hybrid.ID, &hybrid.CreatedAt, &hybrid.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateSpecies
+		}
+		return nil, fmt.Errorf("error creating hybrid: %w", err)
+	}
+	return hybrid, nil
+}
+
+func (s *SpeciesService) AddSynonym(speciesID int, synonym string) error {
+	synonym = types.NormalizeSpeciesName(synonym)
+	if synonym == "" {
+		return ErrUnknownSpecies
+	}
+	if exists, err := s.nameTaken(synonym); err != nil {
+		return err
+	} else if exists {
+		return ErrDuplicateSpecies
+	}
+
+	query := `
+        INSERT INTO species_synonyms (species_id, synonym)
+        SELECT id, $2 FROM species WHERE id = $1 AND deleted_at IS NULL
+    `
+	result, err := s.db.Exec(query, speciesID, synonym)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicateSpecies
+		}
+		return fmt.Errorf("error adding synonym: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrSpeciesNotFound
+	}
+	return nil
+}
+
+// DeleteSpecies removes a species that no plant uses.
+func (s *SpeciesService) DeleteSpecies(id int) error {
+	taxon, err := s.GetTaxon(id)
+	if err != nil {
+		return err
+	}
+	if taxon.PlantCount > 0 {
+		return ErrSpeciesInUse
+	}
+
+	query := `UPDATE species SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	if _, err := s.db.Exec(query, id); err != nil {
+		return fmt.Errorf("error deleting species: %w", err)
+	}
+	return nil
+}
+
+// nameTaken reports whether a name is already a species or a synonym, since
+// either would make resolving it ambiguous.
+func (s *SpeciesService) nameTaken(name string) (bool, error) {
+	query := `
+        SELECT EXISTS (
+            SELECT 1 FROM species WHERE lower(name) = lower($1) AND deleted_at IS NULL
+            UNION ALL
+            SELECT 1 FROM species_synonyms WHERE lower(synonym) = lower($1)
+        )
+    `
+	var exists bool
+	if err := s.db.Get(&exists, query, strings.TrimSpace(name)); err != nil {
+		return false, fmt.Errorf("error checking species name: %w", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pepper-analytics-ai/internal/types"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTaskNotFound     = errors.New("task not found")
+	ErrTaskDone         = errors.New("task is already done")
+	ErrTaskHasNoPlants  = errors.New("task has no plants")
+	ErrInvalidEntryType = errors.New("invalid journal entry type")
+)
+
+// TaskService keeps the to-do list. Completed tasks are written to the
+// plants' journals through PlantService.
+type TaskService struct {
+	db           *sqlx.DB
+	plantService *PlantService
+}
+
+func NewTaskService(db *sqlx.DB, plantService *PlantService) *TaskService {
+	return &TaskService{
+		db:           db,
+		plantService: plantService,
+	}
+}
+
+// taskPlants lists the live plants a task applies to: the ones picked for it
+// and those currently in its project.
+const taskPlants = `
+        SELECT p.id, p.name
+        FROM plants p
+        WHERE p.deleted_at IS NULL
+        AND (
+            p.id IN (SELECT tp.plant_id FROM task_plants tp WHERE tp.task_id = t.id)
+            OR p.id IN (SELECT pp.plant_id FROM project_plants pp WHERE pp.project_id = t.project_id)
+        )
+`
+
+const taskSelect = `
+        SELECT t.*, bp.name as project_name,
+               (SELECT string_agg(tp.name, ', ' ORDER BY tp.name) FROM (` + taskPlants + `) tp) as plant_names,
+               (SELECT COUNT(*) FROM (` + taskPlants + `) tp) as plant_count
+        FROM tasks t
+        LEFT JOIN breeding_projects bp ON t.project_id = bp.id AND bp.deleted_at IS NULL
+`
+
+// GetOpenTasks lists tasks not yet done, soonest first. A plantID above 0
+// keeps to the tasks on that plant.
+func (s *TaskService) GetOpenTasks(plantID int) ([]types.Task, error) {
+	query := taskSelect + `
+        WHERE t.deleted_at IS NULL AND t.completed_at IS NULL
+        AND ($1 = 0 OR $1 IN (SELECT tp.id FROM (` + taskPlants + `) tp))
+        ORDER BY t.due_date, t.title
+    `
+	var tasks []types.Task
+	if err := s.db.Select(&tasks, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// GetDueTasks lists the open tasks due on or before the given day.
+func (s *TaskService) GetDueTasks(asOf time.Time) ([]types.Task, error) {
+	query := taskSelect + `
+        WHERE t.deleted_at IS NULL AND t.completed_at IS NULL
+        AND t.due_date <= $1::date
+        ORDER BY t.due_date, t.title
+    `
+	var tasks []types.Task
+	if err := s.db.Select(&tasks, query, asOf); err != nil {
+		return nil, fmt.Errorf("error fetching due tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (s *TaskService) GetTask(id int) (*types.Task, error) {
+	var task types.Task
+	err := s.db.Get(&task, taskSelect+`WHERE t.id = $1 AND t.deleted_at IS NULL`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("error fetching task: %w", err)
+	}
+	return &task, nil
+}
+
+// GetRecentCompletions lists the last times tasks were done, newest first.
+func (s *TaskService) GetRecentCompletions(limit int) ([]types.TaskCompletion, error) {
+	query := `
+        SELECT tc.*, t.title as task_title
+        FROM task_completions tc
+        JOIN tasks t ON tc.task_id = t.id
+        WHERE t.deleted_at IS NULL
+        ORDER BY tc.completed_on DESC, tc.id DESC
+        LIMIT $1
+    `
+	var completions []types.TaskCompletion
+	if err := s.db.Select(&completions, query, limit); err != nil {
+		return nil, fmt.Errorf("error fetching task completions: %w", err)
+	}
+	return completions, nil
+}
+
+// CreateTask adds a task on the given plants, its project's plants, or both.
+func (s *TaskService) CreateTask(task *types.Task, plantIDs []int) error {
+	if len(plantIDs) == 0 && !task.ProjectID.Valid {
+		return ErrTaskHasNoPlants
+	}
+	if !validEntryType(task.EntryType) {
+		return ErrInvalidEntryType
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO tasks (title, description, entry_type, due_date, rrule, project_id)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at, updated_at
+    `
+	err = tx.QueryRow(
+		query,
+		task.Title,
+		task.Description,
+		task.EntryType,
+		task.DueDate,
+		task.RRule,
+		task.ProjectID,
+	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating task: %w", err)
+	}
+
+	for _, plantID := range plantIDs {
+		query := `INSERT INTO task_plants (task_id, plant_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+		if _, err := tx.Exec(query, task.ID, plantID); err != nil {
+			return fmt.Errorf("error adding plant to task: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func validEntryType(entryType string) bool {
+	for _, t := range types.JournalEntryTypes {
+		if t == entryType {
+			return true
+		}
+	}
+	return false
+}
+
+// CompleteTask records the task as done on completedOn and writes a journal
+// entry for each of its plants. A recurring task moves on to its next
+// occurrence after completedOn, skipping any it fell behind on; a one-off
+// task, or one at the end of its series, is closed. occurrences is the
+// number of completions the caller saw, so a repeated submit returns
+// ErrTaskDone instead of completing the next occurrence as well.
+func (s *TaskService) CompleteTask(id, occurrences int, completedOn time.Time, note string) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The lock makes a second submit wait for the first, and then see the
+	// occurrence it was completing as done
+	var task types.Task
+	err = tx.Get(&task, `SELECT * FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrTaskNotFound
+		}
+		return fmt.Errorf("error fetching task: %w", err)
+	}
+	if task.CompletedAt.Valid || task.Occurrences != occurrences {
+		return ErrTaskDone
+	}
+
+	var plantIDs []int
+	if err := tx.Select(&plantIDs, `SELECT tp.id FROM tasks t, LATERAL (`+taskPlants+`) tp WHERE t.id = $1`, id); err != nil {
+		return fmt.Errorf("error fetching task plants: %w", err)
+	}
+	if len(plantIDs) == 0 {
+		return ErrTaskHasNoPlants
+	}
+
+	note = strings.TrimSpace(note)
+	description := strings.TrimSpace(strings.Join([]string{task.Description.String, note}, "\n\n"))
+	for _, plantID := range plantIDs {
+		entry := &types.JournalEntry{
+			PlantID:     plantID,
+			Title:       task.Title,
+			EntryType:   task.EntryType,
+			Description: description,
+			EntryDate:   completedOn,
+		}
+		if err := s.plantService.CreateJournalEntryTx(tx, entry); err != nil {
+			return err
+		}
+	}
+
+	query := `
+        INSERT INTO task_completions (task_id, due_date, completed_on, note, plant_count)
+        VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+    `
+	if _, err := tx.Exec(query, id, task.DueDate, completedOn, note, len(plantIDs)); err != nil {
+		return fmt.Errorf("error recording task completion: %w", err)
+	}
+
+	done := task.Occurrences + 1
+	next, ok := time.Time{}, false
+	if rule, recurring := task.Recurrence(); recurring {
+		next, ok = rule.Next(task.DueDate, done)
+		for ok && !next.After(completedOn) {
+			done++
+			next, ok = rule.Next(next, done)
+		}
+	}
+
+	if ok {
+		query = `UPDATE tasks SET due_date = $1, occurrences = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3`
+		_, err = tx.Exec(query, next, done, id)
+	} else {
+		query = `UPDATE tasks SET occurrences = $1, completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+		_, err = tx.Exec(query, done, id)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating task: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *TaskService) DeleteTask(id int) error {
+	query := `UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	result, err := s.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting task: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
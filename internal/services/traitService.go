@@ -0,0 +1,249 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pepper-analytics-ai/internal/types"
+)
+
+var (
+	ErrTraitNotFound     = errors.New("trait not found")
+	ErrInvalidTraitValue = errors.New("invalid trait value")
+)
+
+type TraitService struct {
+	db *sqlx.DB
+}
+
+func NewTraitService(db *sqlx.DB) *TraitService {
+	return &TraitService{db: db}
+}
+
+// GetTraitDefinitions returns all active trait definitions with their
+// categorical states loaded.
+func (s *TraitService) GetTraitDefinitions() ([]types.TraitDefinition, error) {
+	query := `
+        SELECT *
+        FROM trait_definitions
+        WHERE deleted_at IS NULL
+        ORDER BY sort_order, name
+    `
+	var traits []types.TraitDefinition
+	if err := s.db.Select(&traits, query); err != nil {
+		return nil, fmt.Errorf("error fetching trait definitions: %w", err)
+	}
+
+	var states []types.TraitState
+	if err := s.db.Select(&states, `SELECT * FROM trait_states ORDER BY trait_id, value`); err != nil {
+		return nil, fmt.Errorf("error fetching trait states: %w", err)
+	}
+
+	index := make(map[int]int, len(traits))
+	for i, trait := range traits {
+		index[trait.ID] = i
+	}
+	for _, state := range states {
+		if i, ok := index[state.TraitID]; ok {
+			traits[i].States = append(traits[i].States, state)
+		}
+	}
+	return traits, nil
+}
+
+func (s *TraitService) GetTraitDefinition(id int) (*types.TraitDefinition, error) {
+	var trait types.TraitDefinition
+	err := s.db.Get(&trait, `SELECT * FROM trait_definitions WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTraitNotFound
+		}
+		return nil, fmt.Errorf("error fetching trait definition: %w", err)
+	}
+
+	if err := s.db.Select(&trait.States, `SELECT * FROM trait_states WHERE trait_id = $1 ORDER BY value`, id); err != nil {
+		return nil, fmt.Errorf("error fetching trait states: %w", err)
+	}
+	return &trait, nil
+}
+
+func (s *TraitService) CreateTraitDefinition(trait *types.TraitDefinition) error {
+	if trait.ScaleType == types.TraitScaleCategorical && len(trait.States) == 0 {
+		return fmt.Errorf("%w: categorical traits need at least one state", ErrInvalidTraitValue)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO trait_definitions (
+            code, name, trait_group, scale_type, unit,
+            min_value, max_value, description, sort_order
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8,
+            (SELECT COALESCE(MAX(sort_order), 0) + 10 FROM trait_definitions))
+        RETURNING id, sort_order, created_at, updated_at
+    `
+	err = tx.QueryRow(
+		query,
+		trait.Code,
+		trait.Name,
+		trait.TraitGroup,
+		trait.ScaleType,
+		trait.Unit,
+		trait.MinValue,
+		trait.MaxValue,
+		trait.Description,
+	).Scan(&trait.ID, &trait.SortOrder, &trait.CreatedAt, &trait.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating trait definition: %w", err)
+	}
+
+	for i := range trait.States {
+		trait.States[i].TraitID = trait.ID
+		err := tx.QueryRow(
+			`INSERT INTO trait_states (trait_id, value, label) VALUES ($1, $2, $3) RETURNING id`,
+			trait.ID,
+			trait.States[i].Value,
+			trait.States[i].Label,
+		).Scan(&trait.States[i].ID)
+		if err != nil {
+			return fmt.Errorf("error creating trait state: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *TraitService) DeleteTraitDefinition(id int) error {
+	query := `UPDATE trait_definitions SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	result, err := s.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting trait definition: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTraitNotFound
+	}
+	return nil
+}
+
+// GetPlantScores returns the most recent score of each trait on a plant.
+func (s *TraitService) GetPlantScores(plantID int) ([]types.TraitScore, error) {
+	query := `
+        SELECT DISTINCT ON (ts.trait_id)
+               ts.*,
+               td.code as trait_code,
+               td.name as trait_name,
+               td.unit,
+               st.label as state_label
+        FROM trait_scores ts
+        JOIN trait_definitions td ON ts.trait_id = td.id
+        LEFT JOIN trait_states st ON st.trait_id = ts.trait_id AND st.value = ts.state_value
+        WHERE ts.plant_id = $1
+        AND ts.deleted_at IS NULL
+        AND td.deleted_at IS NULL
+        ORDER BY ts.trait_id, ts.observed_date DESC, ts.id DESC
+    `
+	var scores []types.TraitScore
+	if err := s.db.Select(&scores, query, plantID); err != nil {
+		return nil, fmt.Errorf("error fetching trait scores: %w", err)
+	}
+	return scores, nil
+}
+
+// ScorePlant stores the given scores for a plant in one transaction. Each
+// score is checked against its trait's scale, and scores that repeat the
+// plant's latest value for a trait are skipped so the history only records
+// changes.
+func (s *TraitService) ScorePlant(plantID int, scores []types.TraitScore) error {
+	traits, err := s.GetTraitDefinitions()
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]types.TraitDefinition, len(traits))
+	for _, trait := range traits {
+		byID[trait.ID] = trait
+	}
+
+	latest, err := s.GetPlantScores(plantID)
+	if err != nil {
+		return err
+	}
+	current := make(map[int]types.TraitScore, len(latest))
+	for _, score := range latest {
+		current[score.TraitID] = score
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range scores {
+		score := &scores[i]
+		trait, ok := byID[score.TraitID]
+		if !ok {
+			return ErrTraitNotFound
+		}
+		if err := validateTraitScore(trait, *score); err != nil {
+			return err
+		}
+		if prev, ok := current[score.TraitID]; ok &&
+			prev.StateValue == score.StateValue && prev.NumericValue == score.NumericValue {
+			continue
+		}
+
+		query := `
+            INSERT INTO trait_scores (
+                plant_id, trait_id, state_value, numeric_value, observed_date, notes
+            ) VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id, created_at, updated_at
+        `
+		err := tx.QueryRow(
+			query,
+			plantID,
+			score.TraitID,
+			score.StateValue,
+			score.NumericValue,
+			score.ObservedDate,
+			score.Notes,
+		).Scan(&score.ID, &score.CreatedAt, &score.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error creating trait score: %w", err)
+		}
+		score.PlantID = plantID
+	}
+
+	return tx.Commit()
+}
+
+func validateTraitScore(trait types.TraitDefinition, score types.TraitScore) error {
+	switch trait.ScaleType {
+	case types.TraitScaleCategorical:
+		if !score.StateValue.Valid || score.NumericValue.Valid {
+			return fmt.Errorf("%w: %s needs one of its states", ErrInvalidTraitValue, trait.Name)
+		}
+		if _, ok := trait.StateLabel(int(score.StateValue.Int64)); !ok {
+			return fmt.Errorf("%w: %d is not a state of %s", ErrInvalidTraitValue, score.StateValue.Int64, trait.Name)
+		}
+	case types.TraitScaleNumeric:
+		if !score.NumericValue.Valid || score.StateValue.Valid {
+			return fmt.Errorf("%w: %s needs a number", ErrInvalidTraitValue, trait.Name)
+		}
+		value := score.NumericValue.Float64
+		if (trait.MinValue.Valid && value < trait.MinValue.Float64) ||
+			(trait.MaxValue.Valid && value > trait.MaxValue.Float64) {
+			return fmt.Errorf("%w: %s is out of range", ErrInvalidTraitValue, trait.Name)
+		}
+	}
+	return nil
+}
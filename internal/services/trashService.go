@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// trashTables lists the soft-deleted tables in the order they are purged,
+// dependents first, each with the references that keep a deleted row in
+// place. Deleted plants stay while any plant still names them as a parent,
+// so pedigrees keep their removed ancestors.
+var trashTables = []struct {
+	table string
+	keep  string
+}{
+	{"journal_entries", ""},
+	{"heat_tests", ""},
+	{"plant_genotypes", ""},
+	{"trait_scores", `EXISTS (SELECT 1 FROM selection_decision_scores r WHERE r.trait_score_id = t.id)`},
+	{"tasks", ""},
+	{"harvests", `EXISTS (SELECT 1 FROM seed_lots r WHERE r.harvest_id = t.id)
+            OR EXISTS (SELECT 1 FROM heat_tests r WHERE r.harvest_id = t.id)`},
+	{"pollinations", `EXISTS (SELECT 1 FROM seed_lots r WHERE r.pollination_id = t.id)`},
+	{"planned_crosses", `EXISTS (SELECT 1 FROM pollinations r WHERE r.planned_cross_id = t.id)`},
+	{"germination_trials", `EXISTS (SELECT 1 FROM plants r WHERE r.germination_trial_id = t.id)`},
+	{"seed_lots", `EXISTS (SELECT 1 FROM plants r WHERE r.seed_lot_id = t.id)
+            OR EXISTS (SELECT 1 FROM germination_trials r WHERE r.seed_lot_id = t.id)`},
+	{"trait_definitions", `EXISTS (SELECT 1 FROM trait_scores r WHERE r.trait_id = t.id)`},
+	{"breeding_projects", ""},
+	{"varieties", `EXISTS (SELECT 1 FROM plants r WHERE r.variety_id = t.id)
+            OR EXISTS (SELECT 1 FROM germination_trials r WHERE r.variety_id = t.id)`},
+	{"species", `EXISTS (SELECT 1 FROM species r WHERE r.parent_a_id = t.id OR r.parent_b_id = t.id)`},
+	{"users", ""},
+	{"plants", `EXISTS (SELECT 1 FROM plants r WHERE r.seed_parent_id = t.id OR r.pollen_parent_id = t.id)
+            OR EXISTS (SELECT 1 FROM seed_lots r WHERE r.plant_id = t.id OR r.pollen_parent_id = t.id)
+            OR EXISTS (SELECT 1 FROM pollinations r WHERE r.donor_plant_id = t.id)
+            OR EXISTS (SELECT 1 FROM planned_crosses r WHERE r.seed_parent_id = t.id OR r.pollen_parent_id = t.id)`},
+}
+
+// TrashService empties the trash: rows soft-deleted long enough ago are
+// removed for good.
+type TrashService struct {
+	db *sqlx.DB
+}
+
+func NewTrashService(db *sqlx.DB) *TrashService {
+	return &TrashService{db: db}
+}
+
+// PurgeDeleted removes rows deleted before the given time that nothing
+// else still points at, and returns how many went from each table. Rows
+// kept because of a reference are purged by a later run once that goes.
+func (s *TrashService) PurgeDeleted(before time.Time) (map[string]int64, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	purged := make(map[string]int64)
+	for _, trash := range trashTables {
+		query := fmt.Sprintf(`DELETE FROM %s t WHERE t.deleted_at < $1`, trash.table)
+		if trash.keep != "" {
+			query += ` AND NOT (` + trash.keep + `)`
+		}
+
+		result, err := tx.Exec(query, before)
+		if err != nil {
+			return nil, fmt.Errorf("error purging %s: %w", trash.table, err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if rows > 0 {
+			purged[trash.table] = rows
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing purge: %w", err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+	"pepper-analytics-ai/internal/types"
+)
+
+var (
+	ErrVarietyNotFound  = errors.New("variety not found")
+	ErrDuplicateVariety = errors.New("a variety with this name already exists")
+)
+
+type VarietyService struct {
+	db *sqlx.DB
+}
+
+func NewVarietyService(db *sqlx.DB) *VarietyService {
+	return &VarietyService{db: db}
+}
+
+const varietySelect = `
+        SELECT v.*,
+               (
+                   SELECT COUNT(*)
+                   FROM plants p
+                   WHERE p.variety_id = v.id AND p.deleted_at IS NULL
+               ) as plant_count
+        FROM varieties v
+`
+
+func (s *VarietyService) GetVarieties() ([]types.Variety, error) {
+	query := varietySelect + `
+        WHERE v.deleted_at IS NULL
+        ORDER BY v.name
+    `
+	var varieties []types.Variety
+	if err := s.db.Select(&varieties, query); err != nil {
+		return nil, fmt.Errorf("error fetching varieties: %w", err)
+	}
+	return varieties, nil
+}
+
+func (s *VarietyService) GetVariety(id int) (*types.Variety, error) {
+	query := varietySelect + `
+        WHERE v.id = $1 AND v.deleted_at IS NULL
+    `
+	var variety types.Variety
+	if err := s.db.Get(&variety, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrVarietyNotFound
+		}
+		return nil, fmt.Errorf("error fetching variety: %w", err)
+	}
+	return &variety, nil
+}
+
+func (s *VarietyService) CreateVariety(variety *types.Variety) error {
+	return insertVariety(s.db, variety)
+}
+
+func insertVariety(q queryRower, variety *types.Variety) error {
+	query := `
+        INSERT INTO varieties (
+            name, species, origin, typical_shu, days_to_maturity,
+            pod_description, seed_source, watering_interval_days, fertilizing_interval_days
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING id, created_at, updated_at
+    `
+	err := q.QueryRow(
+		query,
+		variety.Name,
+		variety.Species,
+		variety.Origin,
+		variety.TypicalSHU,
+		variety.DaysToMaturity,
+		variety.PodDescription,
+		variety.SeedSource,
+		variety.WateringInterval,
+		variety.FertilizingInterval,
+	).Scan(&variety.ID, &variety.CreatedAt, &variety.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicateVariety
+		}
+		return fmt.Errorf("error creating variety: %w", err)
+	}
+	return nil
+}
+
+func (s *VarietyService) UpdateVariety(variety *types.Variety) error {
+	query := `
+        UPDATE varieties
+        SET name = $1,
+            species = $2,
+            origin = $3,
+            typical_shu = $4,
+            days_to_maturity = $5,
+            pod_description = $6,
+            seed_source = $7,
+            watering_interval_days = $8,
+            fertilizing_interval_days = $9,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $10 AND deleted_at IS NULL
+    `
+	result, err := s.db.Exec(
+		query,
+		variety.Name,
+		variety.Species,
+		variety.Origin,
+		variety.TypicalSHU,
+		variety.DaysToMaturity,
+		variety.PodDescription,
+		variety.SeedSource,
+		variety.WateringInterval,
+		variety.FertilizingInterval,
+		variety.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicateVariety
+		}
+		return fmt.Errorf("error updating variety: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrVarietyNotFound
+	}
+	return nil
+}
+
+// DeleteVariety removes a variety from the catalog. Its plants are kept and
+// lose the link.
+func (s *VarietyService) DeleteVariety(id int) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE varieties SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting variety: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrVarietyNotFound
+	}
+
+	if _, err := tx.Exec(`UPDATE plants SET variety_id = NULL WHERE variety_id = $1`, id); err != nil {
+		return fmt.Errorf("error unlinking variety plants: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetVarietyPlants lists the plants grown from a variety.
+func (s *VarietyService) GetVarietyPlants(id int) ([]types.PlantOption, error) {
+	query := `
+        SELECT id, name, species, generation
+        FROM plants
+        WHERE variety_id = $1 AND deleted_at IS NULL
+        ORDER BY planting_date DESC, name
+    `
+	var plants []types.PlantOption
+	if err := s.db.Select(&plants, query, id); err != nil {
+		return nil, fmt.Errorf("error fetching variety plants: %w", err)
+	}
+	return plants, nil
+}
+
+// ResolveVariety finds the catalog variety a plant name refers to, adding
+// it when it is not catalogued yet. Names are matched without regard to case
+// once the plant numbering is stripped.
+func (s *VarietyService) ResolveVariety(plantName string, species types.Species) (sql.NullInt64, error) {
+	return resolveVariety(s.db, plantName, species)
+}
+
+type varietyResolver interface {
+	queryRower
+	Get(dest interface{}, query string, args ...interface{}) error
+}
+
+func resolveVariety(q varietyResolver, plantName string, species types.Species) (sql.NullInt64, error) {
+	name := types.VarietyNameFromPlant(plantName)
+	if name == "" {
+		return sql.NullInt64{}, nil
+	}
+
+	var id int64
+	query := `SELECT id FROM varieties WHERE lower(name) = lower($1) AND deleted_at IS NULL`
+	err := q.Get(&id, query, name)
+	if err == nil {
+		return sql.NullInt64{Int64: id, Valid: true}, nil
+	}
+	if err != sql.ErrNoRows {
+		return sql.NullInt64{}, fmt.Errorf("error looking up variety: %w", err)
+	}
+
+	variety := &types.Variety{
+		Name:    name,
+		Species: sql.NullString{String: string(species), Valid: species != ""},
+	}
+	if err := insertVariety(q, variety); err != nil {
+		return sql.NullInt64{}, err
+	}
+	return sql.NullInt64{Int64: int64(variety.ID), Valid: true}, nil
+}
+
+// BackfillFromPlantNames links plants that predate the catalog to a variety
+// parsed out of their names. It only runs while the catalog is empty, so
+// later unlinking by hand sticks. Crosses are left alone since they are not
+// a cultivar until someone names the stabilised line.
+func (s *VarietyService) BackfillFromPlantNames() error {
+	var catalogued bool
+	if err := s.db.Get(&catalogued, `SELECT EXISTS(SELECT 1 FROM varieties)`); err != nil {
+		return fmt.Errorf("error checking variety catalog: %w", err)
+	}
+	if catalogued {
+		return nil
+	}
+
+	var plants []struct {
+		ID      int           `db:"id"`
+		Name    string        `db:"name"`
+		Species types.Species `db:"species"`
+	}
+	query := `
+        SELECT id, name, COALESCE(species, '') as species
+        FROM plants
+        WHERE variety_id IS NULL
+        AND NOT COALESCE(is_cross, false)
+        AND deleted_at IS NULL
+        ORDER BY id
+    `
+	if err := s.db.Select(&plants, query); err != nil {
+		return fmt.Errorf("error fetching plants without variety: %w", err)
+	}
+	if len(plants) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	linked := 0
+	for _, plant := range plants {
+		varietyID, err := resolveVariety(tx, plant.Name, plant.Species)
+		if err != nil {
+			return err
+		}
+		if !varietyID.Valid {
+			log.Printf("No variety name in plant %d %q", plant.ID, plant.Name)
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE plants SET variety_id = $1 WHERE id = $2`, varietyID, plant.ID); err != nil {
+			return fmt.Errorf("error linking plant variety: %w", err)
+		}
+		linked++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Printf("Linked %d plants to varieties by name", linked)
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
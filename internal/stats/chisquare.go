@@ -0,0 +1,78 @@
+package stats
+
+import "math"
+
+// ChiSquare returns Pearson's chi-square statistic for observed counts
+// against expected counts. Classes with no expected count are skipped.
+func ChiSquare(observed []int, expected []float64) float64 {
+	var sum float64
+	for i, o := range observed {
+		if expected[i] <= 0 {
+			continue
+		}
+		d := float64(o) - expected[i]
+		sum += d * d / expected[i]
+	}
+	return sum
+}
+
+// ChiSquarePValue returns the probability of a chi-square statistic at least
+// as large as x with df degrees of freedom.
+func ChiSquarePValue(x float64, df int) float64 {
+	if df < 1 {
+		return math.NaN()
+	}
+	if x <= 0 {
+		return 1
+	}
+	return upperIncompleteGamma(float64(df)/2, x/2)
+}
+
+// upperIncompleteGamma is the regularized upper incomplete gamma function
+// Q(a, x), using the series expansion below a+1 and a continued fraction
+// above it (Numerical Recipes, 6.2).
+func upperIncompleteGamma(a, x float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	lgamma, _ := math.Lgamma(a)
+
+	if x < a+1 {
+		term := 1 / a
+		sum := term
+		for n := 1; n < maxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lgamma)
+	}
+
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < maxIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChiSquare(t *testing.T) {
+	tests := []struct {
+		name     string
+		observed []int
+		expected []float64
+		want     float64
+	}{
+		{name: "perfect fit", observed: []int{75, 25}, expected: []float64{75, 25}, want: 0},
+		{name: "Mendel round and wrinkled", observed: []int{5474, 1850}, expected: []float64{5493, 1831}, want: 361.0/5493 + 361.0/1831},
+		{name: "dihybrid", observed: []int{315, 108, 101, 32}, expected: []float64{312.75, 104.25, 104.25, 34.75}, want: 0.470024},
+		{name: "empty class skipped", observed: []int{10, 3}, expected: []float64{10, 0}, want: 0},
+	}
+
+	for _, tt := range tests {
+		got := ChiSquare(tt.observed, tt.expected)
+		if math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("%s: ChiSquare = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChiSquarePValue(t *testing.T) {
+	tests := []struct {
+		x    float64
+		df   int
+		want float64
+	}{
+		{x: 0, df: 1, want: 1},
+		{x: 1, df: 1, want: 0.317311},
+		{x: 3.841459, df: 1, want: 0.05},
+		{x: 2, df: 2, want: math.Exp(-1)},
+		{x: 5.991465, df: 2, want: 0.05},
+		{x: 7.814728, df: 3, want: 0.05},
+		{x: 0.470024, df: 3, want: 0.925426},
+		{x: 30, df: 3, want: 1.3800e-6},
+	}
+
+	for _, tt := range tests {
+		got := ChiSquarePValue(tt.x, tt.df)
+		if math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("ChiSquarePValue(%v, %d) = %v, want %v", tt.x, tt.df, got, tt.want)
+		}
+	}
+
+	if p := ChiSquarePValue(1, 0); !math.IsNaN(p) {
+		t.Errorf("ChiSquarePValue(1, 0) = %v, want NaN", p)
+	}
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"database/sql"
+)
+
+// CareStatus says whether watering or fertilizing is due. It is empty for a
+// plant without a schedule.
+type CareStatus string
+
+const (
+	CareOK      CareStatus = "OK"
+	CareDue     CareStatus = "Due"
+	CareOverdue CareStatus = "Overdue"
+)
+
+// CareInterval is how often a plant is watered and fertilized, in days. Zero
+// means the plant is not on a schedule for it.
+type CareInterval struct {
+	WateringDays    int
+	FertilizingDays int
+}
+
+// StageCareDefaults are the intervals used when neither the plant nor its
+// variety sets one. Stages not listed have no schedule.
+var StageCareDefaults = map[GrowthStage]CareInterval{
+	GrowthStageSeedling:      {WateringDays: 2, FertilizingDays: 14},
+	GrowthStageVegetative:    {WateringDays: 3, FertilizingDays: 14},
+	GrowthStageFlowering:     {WateringDays: 3, FertilizingDays: 10},
+	GrowthStageFruiting:      {WateringDays: 3, FertilizingDays: 10},
+	GrowthStageOverwintering: {WateringDays: 10},
+	GrowthStageDormant:       {WateringDays: 21},
+}
+
+// CareSchedule is a plant's care schedule as of a given day.
+type CareSchedule struct {
+	PlantID           int           `db:"plant_id"`
+	PlantName         string        `db:"plant_name"`
+	WateringEvery     sql.NullInt64 `db:"watering_every"`
+	WateringDue       sql.NullTime  `db:"watering_due"`
+	WateringStatus    CareStatus    `db:"watering_status"`
+	FertilizingEvery  sql.NullInt64 `db:"fertilizing_every"`
+	FertilizingDue    sql.NullTime  `db:"fertilizing_due"`
+	FertilizingStatus CareStatus    `db:"fertilizing_status"`
+}
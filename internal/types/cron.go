@@ -0,0 +1,165 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the shorthand schedules accepted in place of five fields.
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// cronField is one field of a cron schedule as a bit per allowed value.
+type cronField struct {
+	bits uint64
+	star bool
+}
+
+func (f cronField) has(v int) bool {
+	return f.bits&(1<<uint(v)) != 0
+}
+
+// CronSchedule is a standard five-field cron schedule: minute, hour, day of
+// month, month and day of week. Fields take "*", single values, ranges
+// ("1-5"), steps ("*/15", "8-18/2") and comma separated lists of those. Day
+// of week runs 0-6 from Sunday, with 7 also meaning Sunday. As in cron, when
+// both day fields are restricted a day matching either one will do.
+type CronSchedule struct {
+	spec   string
+	minute cronField
+	hour   cronField
+	dom    cronField
+	month  cronField
+	dow    cronField
+}
+
+// ParseCronSchedule reads a schedule such as "*/5 * * * *" or "@daily".
+func ParseCronSchedule(spec string) (CronSchedule, error) {
+	spec = strings.Join(strings.Fields(spec), " ")
+	c := CronSchedule{spec: spec}
+
+	expanded := spec
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		expanded = macro
+	}
+	fields := strings.Fields(expanded)
+	if len(fields) != 5 {
+		return c, fmt.Errorf("cron schedule needs 5 fields, got %d: %q", len(fields), spec)
+	}
+
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return c, fmt.Errorf("invalid minute: %w", err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return c, fmt.Errorf("invalid hour: %w", err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return c, fmt.Errorf("invalid day of month: %w", err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return c, fmt.Errorf("invalid month: %w", err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return c, fmt.Errorf("invalid day of week: %w", err)
+	}
+	if c.dow.has(7) {
+		c.dow.bits |= 1
+	}
+	return c, nil
+}
+
+func parseCronField(field string, min, max int) (cronField, error) {
+	var f cronField
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return f, fmt.Errorf("invalid step: %s", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+			if !hasStep {
+				f.star = true
+			}
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var errA, errB error
+			lo, errA = strconv.Atoi(a)
+			hi, errB = strconv.Atoi(b)
+			if errA != nil || errB != nil || lo > hi {
+				return f, fmt.Errorf("invalid range: %s", part)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return f, fmt.Errorf("invalid value: %s", part)
+			}
+			lo = n
+			if !hasStep {
+				hi = n
+			}
+		}
+		if lo < min || hi > max {
+			return f, fmt.Errorf("%s is outside %d-%d", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			f.bits |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+func (c CronSchedule) String() string {
+	return c.spec
+}
+
+func (c CronSchedule) matchesDay(t time.Time) bool {
+	dom := c.dom.has(t.Day())
+	dow := c.dow.has(int(t.Weekday()))
+	if c.dom.star || c.dow.star {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first minute after t that the schedule fires on, in t's
+// location, or the zero time if it never does within five years (such as
+// "0 0 30 2 *").
+func (c CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !c.month.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !c.hour.has(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !c.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
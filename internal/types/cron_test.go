@@ -0,0 +1,78 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	valid := []string{"* * * * *", "*/5 * * * *", "0 8-18/2 * * 1-5", "0 0 1,15 * *", "0 12 * * 7", "@daily", "@Weekly"}
+	for _, spec := range valid {
+		if _, err := ParseCronSchedule(spec); err != nil {
+			t.Errorf("ParseCronSchedule(%q) error: %v", spec, err)
+		}
+	}
+
+	invalid := []string{"", "* * * *", "* * * * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "@often"}
+	for _, spec := range invalid {
+		if _, err := ParseCronSchedule(spec); err == nil {
+			t.Errorf("ParseCronSchedule(%q) succeeded, want error", spec)
+		}
+	}
+
+	c, err := ParseCronSchedule("  */5   * * * * ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.String() != "*/5 * * * *" {
+		t.Errorf("String() = %q, want %q", c.String(), "*/5 * * * *")
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	at := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		spec string
+		from string
+		want string
+	}{
+		{spec: "* * * * *", from: "2026-10-18 10:07", want: "2026-10-18 10:08"},
+		{spec: "*/15 * * * *", from: "2026-10-18 10:07", want: "2026-10-18 10:15"},
+		{spec: "*/15 * * * *", from: "2026-10-18 23:50", want: "2026-10-19 00:00"},
+		{spec: "@daily", from: "2026-10-18 10:00", want: "2026-10-19 00:00"},
+		{spec: "@yearly", from: "2026-10-18 10:00", want: "2027-01-01 00:00"},
+		{spec: "30 8 * * 1-5", from: "2026-10-16 09:00", want: "2026-10-19 08:30"},
+		{spec: "0 12 * * 7", from: "2026-10-17 13:00", want: "2026-10-18 12:00"},
+		{spec: "0 8-18/2 * * *", from: "2026-10-18 17:00", want: "2026-10-18 18:00"},
+		{spec: "0 8-18/2 * * *", from: "2026-10-18 18:30", want: "2026-10-19 08:00"},
+		// With both day fields restricted either one will do
+		{spec: "0 9 15 * 3", from: "2026-10-12 10:00", want: "2026-10-14 09:00"},
+		{spec: "0 9 15 * 3", from: "2026-10-14 10:00", want: "2026-10-15 09:00"},
+		{spec: "0 0 29 2 *", from: "2026-10-18 10:00", want: "2028-02-29 00:00"},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseCronSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseCronSchedule(%q) error: %v", tt.spec, err)
+		}
+		if got := c.Next(at(tt.from)); !got.Equal(at(tt.want)) {
+			t.Errorf("%q after %s = %s, want %s", tt.spec, tt.from, got.Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+
+	never, err := ParseCronSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := never.Next(at("2026-10-18 10:00")); !got.IsZero() {
+		t.Errorf("Feb 30 schedule fired at %s, want never", got)
+	}
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type CrossStatus string
+
+const (
+	CrossPlanned   CrossStatus = "Planned"
+	CrossAttempted CrossStatus = "Attempted"
+	CrossCompleted CrossStatus = "Completed"
+	CrossAbandoned CrossStatus = "Abandoned"
+)
+
+// PlannedCross is a cross drafted ahead of flowering. Pollinations carried
+// out for it point back at it, and the plants sown from their seed are its
+// offspring. A self has the same plant as both parents.
+type PlannedCross struct {
+	ID               int            `db:"id"`
+	SeedParentID     int            `db:"seed_parent_id"`
+	PollenParentID   int            `db:"pollen_parent_id"`
+	ProjectID        sql.NullInt64  `db:"project_id"`
+	Status           CrossStatus    `db:"status"`
+	Notes            sql.NullString `db:"notes"`
+	CreatedAt        time.Time      `db:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at"`
+	DeletedAt        *time.Time     `db:"deleted_at"`
+	SeedParentName   string         `db:"seed_parent_name"`
+	PollenParentName string         `db:"pollen_parent_name"`
+	ProjectName      sql.NullString `db:"project_name"`
+	PollinationCount int            `db:"pollination_count"`
+}
+
+// IsSelf reports whether the cross is a self-pollination.
+func (c PlannedCross) IsSelf() bool {
+	return c.SeedParentID == c.PollenParentID
+}
+
+// Label names the cross, e.g. "Habanero x Charapita" or "Habanero (self)".
+func (c PlannedCross) Label() string {
+	if c.IsSelf() {
+		return c.SeedParentName + " (self)"
+	}
+	return c.SeedParentName + " x " + c.PollenParentName
+}
+
+func ParseCrossStatus(s string) (CrossStatus, error) {
+	switch s {
+	case "Planned":
+		return CrossPlanned, nil
+	case "Attempted":
+		return CrossAttempted, nil
+	case "Completed":
+		return CrossCompleted, nil
+	case "Abandoned":
+		return CrossAbandoned, nil
+	default:
+		return "", fmt.Errorf("invalid cross status value: %s", s)
+	}
+}
@@ -0,0 +1,135 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// DigestFrequency is how often a user gets the garden digest.
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "Off"
+	DigestDaily  DigestFrequency = "Daily"
+	DigestWeekly DigestFrequency = "Weekly"
+)
+
+var DigestFrequencies = []DigestFrequency{DigestOff, DigestDaily, DigestWeekly}
+
+func ParseDigestFrequency(s string) (DigestFrequency, error) {
+	for _, f := range DigestFrequencies {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("invalid digest frequency value: %s", s)
+}
+
+// Days is the period a digest of this frequency looks back over.
+func (f DigestFrequency) Days() int {
+	if f == DigestWeekly {
+		return 7
+	}
+	return 1
+}
+
+// DigestSection is one part of the digest a user can opt into.
+type DigestSection string
+
+const (
+	DigestCare     DigestSection = "Care"
+	DigestHealth   DigestSection = "Health"
+	DigestTasks    DigestSection = "Tasks"
+	DigestJournal  DigestSection = "Journal"
+	DigestHarvests DigestSection = "Harvests"
+)
+
+var DigestSections = []DigestSection{DigestCare, DigestHealth, DigestTasks, DigestJournal, DigestHarvests}
+
+func ParseDigestSection(s string) (DigestSection, error) {
+	for _, section := range DigestSections {
+		if string(section) == s {
+			return section, nil
+		}
+	}
+	return "", fmt.Errorf("invalid digest section value: %s", s)
+}
+
+func (s DigestSection) Label() string {
+	switch s {
+	case DigestCare:
+		return "Needs water or fertilizer"
+	case DigestHealth:
+		return "Health declines"
+	case DigestTasks:
+		return "Upcoming tasks"
+	case DigestJournal:
+		return "Journal entries"
+	case DigestHarvests:
+		return "Harvests this week"
+	default:
+		return string(s)
+	}
+}
+
+// DigestHarvestDays is how far back the harvest totals go, whatever the
+// digest's frequency.
+const DigestHarvestDays = 7
+
+// DigestTaskDays is how far ahead the digest lists upcoming tasks.
+const DigestTaskDays = 7
+
+// RecentJournalEntry is a journal entry with the plant it is about.
+type RecentJournalEntry struct {
+	JournalEntry
+	PlantName string `db:"plant_name"`
+}
+
+// Digest is a summary of the garden for one user. Only the sections the
+// user picked are filled in.
+type Digest struct {
+	UserName       string
+	Frequency      DigestFrequency
+	AsOf           time.Time
+	Since          time.Time
+	BaseURL        string
+	Sections       []DigestSection
+	Overdue        []CareSchedule
+	HealthAlerts   []HealthAlert
+	Tasks          []Task
+	JournalEntries []RecentJournalEntry
+	Harvests       []PlantYield
+}
+
+// Has reports whether the digest includes the section.
+func (d Digest) Has(section DigestSection) bool {
+	for _, s := range d.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty reports whether there is nothing to tell in any of its sections.
+func (d Digest) IsEmpty() bool {
+	return len(d.Overdue) == 0 && len(d.HealthAlerts) == 0 && len(d.Tasks) == 0 &&
+		len(d.JournalEntries) == 0 && len(d.Harvests) == 0
+}
+
+// HarvestTotals sums the harvest section.
+func (d Digest) HarvestTotals() YieldTotals {
+	var totals YieldTotals
+	for _, yield := range d.Harvests {
+		totals.HarvestCount += yield.HarvestCount
+		totals.PodCount += yield.PodCount
+		totals.TotalWeightGrams += yield.TotalWeightGrams
+	}
+	return totals
+}
+
+// Link turns an app path into a link from the digest. Without a base URL the
+// link stays relative, which still works in the browser preview.
+func (d Digest) Link(path string) string {
+	return d.BaseURL + path
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type GenerationKind string
+
+const (
+	GenerationFilial         GenerationKind = "F"
+	GenerationBackcross      GenerationKind = "BC"
+	GenerationSelfed         GenerationKind = "S"
+	GenerationOpenPollinated GenerationKind = "OP"
+)
+
+// Generation is a parsed filial generation such as F2, BC1, S3 or OP.
+// Number is always zero for open-pollinated seed.
+type Generation struct {
+	Kind   GenerationKind
+	Number int
+}
+
+func (g Generation) String() string {
+	if g.Kind == GenerationOpenPollinated {
+		return string(g.Kind)
+	}
+	return fmt.Sprintf("%s%d", g.Kind, g.Number)
+}
+
+// ParseGeneration accepts hand-typed generations ("f2 ", "BC-1", "s 3", "op")
+// and returns them in canonical form.
+func ParseGeneration(s string) (Generation, error) {
+	normalized := strings.ToUpper(strings.Join(strings.Fields(s), ""))
+	normalized = strings.ReplaceAll(normalized, "-", "")
+
+	if normalized == string(GenerationOpenPollinated) {
+		return Generation{Kind: GenerationOpenPollinated}, nil
+	}
+
+	for _, kind := range []GenerationKind{GenerationBackcross, GenerationFilial, GenerationSelfed} {
+		digits, found := strings.CutPrefix(normalized, string(kind))
+		if !found {
+			continue
+		}
+		n, err := strconv.Atoi(digits)
+		if err != nil || n < 1 {
+			break
+		}
+		return Generation{Kind: kind, Number: n}, nil
+	}
+
+	return Generation{}, fmt.Errorf("invalid generation value: %s", s)
+}
+
+// GenerationParent describes one parent for generation derivation. PlantID is
+// zero for external varieties, which are known by External instead, and
+// Generation is nil when the parent is not itself a cross. The ancestor fields
+// list the plant's recorded ancestors, by plant ID and by external name, so a
+// cross back to one of them can be told apart from an outcross.
+type GenerationParent struct {
+	PlantID           int
+	External          string
+	Generation        *Generation
+	AncestorIDs       []int
+	AncestorExternals []string
+}
+
+// isAncestorOf reports whether p is a recorded ancestor of line.
+func (p GenerationParent) isAncestorOf(line GenerationParent) bool {
+	if p.PlantID != 0 {
+		for _, id := range line.AncestorIDs {
+			if id == p.PlantID {
+				return true
+			}
+		}
+		return false
+	}
+	name := strings.TrimSpace(p.External)
+	if name == "" {
+		return false
+	}
+	for _, external := range line.AncestorExternals {
+		if strings.EqualFold(strings.TrimSpace(external), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeriveGeneration works out the generation of seed taken from seedParent.
+// pollenParent is nil when the pollen donor is unknown. The rules are:
+//
+//   - unknown donor gives OP seed
+//   - selfing an Fn gives Fn+1, selfing an Sn gives Sn+1, anything else gives S1
+//   - two non-cross parents give an F1
+//   - sibling Fn x Fn gives Fn+1
+//   - an F line crossed back to one of its own non-cross ancestors gives
+//     BC1, and a BCn crossed back to one gives BCn+1
+//   - any other combination, such as a line crossed to an unrelated
+//     variety, starts a new F1
+//
+// The second return value is false when the offspring is not a cross at all,
+// such as open-pollinated or selfed seed of a stable variety.
+func DeriveGeneration(seedParent GenerationParent, pollenParent *GenerationParent) (Generation, bool) {
+	seedGen := seedParent.Generation
+
+	if pollenParent == nil {
+		if seedGen == nil {
+			return Generation{}, false
+		}
+		return Generation{Kind: GenerationOpenPollinated}, true
+	}
+
+	pollenGen := pollenParent.Generation
+
+	if seedParent.PlantID != 0 && seedParent.PlantID == pollenParent.PlantID {
+		if seedGen == nil {
+			return Generation{}, false
+		}
+		switch seedGen.Kind {
+		case GenerationFilial, GenerationSelfed:
+			return Generation{Kind: seedGen.Kind, Number: seedGen.Number + 1}, true
+		default:
+			return Generation{Kind: GenerationSelfed, Number: 1}, true
+		}
+	}
+
+	switch {
+	case seedGen == nil && pollenGen == nil:
+		return Generation{Kind: GenerationFilial, Number: 1}, true
+	case seedGen != nil && pollenGen != nil:
+		if seedGen.Kind == GenerationFilial && *seedGen == *pollenGen {
+			return Generation{Kind: GenerationFilial, Number: seedGen.Number + 1}, true
+		}
+		return Generation{Kind: GenerationFilial, Number: 1}, true
+	}
+
+	line, recurrent := seedParent, *pollenParent
+	if seedGen == nil {
+		line, recurrent = *pollenParent, seedParent
+	}
+	if !recurrent.isAncestorOf(line) {
+		return Generation{Kind: GenerationFilial, Number: 1}, true
+	}
+	switch line.Generation.Kind {
+	case GenerationBackcross:
+		return Generation{Kind: GenerationBackcross, Number: line.Generation.Number + 1}, true
+	case GenerationFilial:
+		return Generation{Kind: GenerationBackcross, Number: 1}, true
+	default:
+		return Generation{Kind: GenerationFilial, Number: 1}, true
+	}
+}
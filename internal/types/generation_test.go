@@ -0,0 +1,90 @@
+package types
+
+import "testing"
+
+func TestParseGeneration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Generation
+		wantErr bool
+	}{
+		{in: "F1", want: Generation{Kind: GenerationFilial, Number: 1}},
+		{in: "f2 ", want: Generation{Kind: GenerationFilial, Number: 2}},
+		{in: "BC-1", want: Generation{Kind: GenerationBackcross, Number: 1}},
+		{in: "s 3", want: Generation{Kind: GenerationSelfed, Number: 3}},
+		{in: "op", want: Generation{Kind: GenerationOpenPollinated}},
+		{in: "F0", wantErr: true},
+		{in: "F", wantErr: true},
+		{in: "X2", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseGeneration(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseGeneration(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseGeneration(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseGeneration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDeriveGeneration(t *testing.T) {
+	gen := func(s string) *Generation {
+		g, err := ParseGeneration(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &g
+	}
+
+	// Plant 10 is an F1 of plant 1 and the external variety "Bhut Jolokia"
+	f1 := GenerationParent{PlantID: 10, Generation: gen("F1"), AncestorIDs: []int{1}, AncestorExternals: []string{"Bhut Jolokia"}}
+	f2 := GenerationParent{PlantID: 20, Generation: gen("F2"), AncestorIDs: []int{10, 1}, AncestorExternals: []string{"Bhut Jolokia"}}
+	bc1 := GenerationParent{PlantID: 30, Generation: gen("BC1"), AncestorIDs: []int{10, 1}, AncestorExternals: []string{"Bhut Jolokia"}}
+	stable := GenerationParent{PlantID: 1}
+	unrelated := GenerationParent{PlantID: 2}
+
+	tests := []struct {
+		name    string
+		seed    GenerationParent
+		pollen  *GenerationParent
+		want    string
+		isCross bool
+	}{
+		{name: "open pollinated cross", seed: f1, want: "OP", isCross: true},
+		{name: "open pollinated variety", seed: stable},
+		{name: "selfed F1", seed: f1, pollen: &f1, want: "F2", isCross: true},
+		{name: "selfed variety", seed: stable, pollen: &stable},
+		{name: "selfed BC1", seed: bc1, pollen: &bc1, want: "S1", isCross: true},
+		{name: "two varieties", seed: stable, pollen: &unrelated, want: "F1", isCross: true},
+		{name: "sibling F2", seed: f2, pollen: &GenerationParent{PlantID: 21, Generation: gen("F2")}, want: "F3", isCross: true},
+		{name: "F1 back to parent", seed: f1, pollen: &stable, want: "BC1", isCross: true},
+		{name: "parent onto F1", seed: stable, pollen: &f1, want: "BC1", isCross: true},
+		{name: "F1 back to external parent", seed: f1, pollen: &GenerationParent{External: "bhut jolokia"}, want: "BC1", isCross: true},
+		{name: "BC1 back to parent", seed: bc1, pollen: &stable, want: "BC2", isCross: true},
+		{name: "F2 to unrelated plant", seed: f2, pollen: &unrelated, want: "F1", isCross: true},
+		{name: "F2 to unrelated external", seed: f2, pollen: &GenerationParent{External: "Carolina Reaper"}, want: "F1", isCross: true},
+		{name: "F1 to unnamed external", seed: f1, pollen: &GenerationParent{}, want: "F1", isCross: true},
+		{name: "F2 x BC1", seed: f2, pollen: &bc1, want: "F1", isCross: true},
+	}
+
+	for _, tt := range tests {
+		got, isCross := DeriveGeneration(tt.seed, tt.pollen)
+		if isCross != tt.isCross {
+			t.Errorf("%s: isCross = %v, want %v", tt.name, isCross, tt.isCross)
+			continue
+		}
+		if isCross && got.String() != tt.want {
+			t.Errorf("%s: got %v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package types
+
+import "math"
+
+// Genotype is the call at one biallelic locus, written with A for the
+// dominant and a for the recessive allele. GenotypeDominant is a plant known
+// to carry the dominant allele whose second allele is not known, as inferred
+// from a dominant phenotype.
+type Genotype string
+
+const (
+	GenotypeHomozygousDominant  Genotype = "AA"
+	GenotypeHeterozygous        Genotype = "Aa"
+	GenotypeHomozygousRecessive Genotype = "aa"
+	GenotypeDominant            Genotype = "A_"
+	GenotypeUnknown             Genotype = "Unknown"
+)
+
+// Locus is a well-characterised single gene. Plants whose latest score for
+// TraitCode is one of DominantStates or RecessiveStates have a genotype that
+// can be read from their phenotype.
+type Locus struct {
+	Symbol          string
+	Name            string
+	DominantAllele  string
+	RecessiveAllele string
+	DominantLabel   string
+	RecessiveLabel  string
+	TraitCode       string
+	DominantStates  []int
+	RecessiveStates []int
+}
+
+// KnownLoci are the loci the cross planner predicts. Red fruit needs a
+// dominant allele at each of y, c1 and c2. Without the red pigments of Y,
+// fruit ripens yellow to orange, and white fruit is recessive at all three;
+// yellow and orange fruit say nothing certain about c1 and c2.
+var KnownLoci = []Locus{
+	{
+		Symbol: "y", Name: "Capsanthin-capsorubin synthase",
+		DominantAllele: "Y", RecessiveAllele: "y",
+		DominantLabel: "red pigments", RecessiveLabel: "no red pigments",
+		TraitCode: "fruit_color_mature", DominantStates: []int{7, 8, 9}, RecessiveStates: []int{1, 2, 3, 4, 5, 6},
+	},
+	{
+		Symbol: "c1", Name: "Carotenoid content 1",
+		DominantAllele: "C1", RecessiveAllele: "c1",
+		DominantLabel: "full carotenoids", RecessiveLabel: "reduced carotenoids",
+		TraitCode: "fruit_color_mature", DominantStates: []int{7, 8, 9}, RecessiveStates: []int{1},
+	},
+	{
+		Symbol: "c2", Name: "Phytoene synthase",
+		DominantAllele: "C2", RecessiveAllele: "c2",
+		DominantLabel: "full carotenoids", RecessiveLabel: "reduced carotenoids",
+		TraitCode: "fruit_color_mature", DominantStates: []int{7, 8, 9}, RecessiveStates: []int{1},
+	},
+	{
+		Symbol: "Pun1", Name: "Pungency",
+		DominantAllele: "Pun1", RecessiveAllele: "pun1",
+		DominantLabel: "pungent", RecessiveLabel: "non-pungent",
+		TraitCode: "placenta_capsaicin", DominantStates: []int{1}, RecessiveStates: []int{0},
+	},
+}
+
+// FindLocus looks up one of the KnownLoci by its symbol.
+func FindLocus(symbol string) (Locus, bool) {
+	for _, locus := range KnownLoci {
+		if locus.Symbol == symbol {
+			return locus, true
+		}
+	}
+	return Locus{}, false
+}
+
+// Notation writes a genotype with the locus' allele names, e.g. Y/y.
+func (l Locus) Notation(g Genotype) string {
+	switch g {
+	case GenotypeHomozygousDominant:
+		return l.DominantAllele + "/" + l.DominantAllele
+	case GenotypeHeterozygous:
+		return l.DominantAllele + "/" + l.RecessiveAllele
+	case GenotypeHomozygousRecessive:
+		return l.RecessiveAllele + "/" + l.RecessiveAllele
+	case GenotypeDominant:
+		return l.DominantAllele + "/-"
+	default:
+		return "?"
+	}
+}
+
+// GenotypeRatio is the expected share of each genotype in a population.
+type GenotypeRatio struct {
+	HomozygousDominant  float64
+	Heterozygous        float64
+	HomozygousRecessive float64
+}
+
+// Dominant is the share showing the dominant phenotype.
+func (r GenotypeRatio) Dominant() float64 {
+	return r.HomozygousDominant + r.Heterozygous
+}
+
+// Recessive is the share showing the recessive phenotype.
+func (r GenotypeRatio) Recessive() float64 {
+	return r.HomozygousRecessive
+}
+
+// LocusPrediction is the expected segregation at one locus of a cross.
+// Known is false when either parent's genotype is unknown.
+type LocusPrediction struct {
+	Locus        Locus
+	SeedParent   Genotype
+	PollenParent Genotype
+	Known        bool
+	Assumed      bool
+	F1           GenotypeRatio
+	F2           GenotypeRatio
+}
+
+// PhenotypeClass is one combination of phenotypes across the predicted loci
+// and the share of offspring expected to show it.
+type PhenotypeClass struct {
+	Label       string
+	Description string
+	Probability float64
+}
+
+// PlantsNeeded is how many offspring to grow to see at least one plant of
+// the class with the given confidence, e.g. 0.95.
+func (c PhenotypeClass) PlantsNeeded(confidence float64) int {
+	switch {
+	case c.Probability <= 0:
+		return 0
+	case c.Probability >= 1:
+		return 1
+	}
+	return int(math.Ceil(math.Log(1-confidence) / math.Log(1-c.Probability)))
+}
+
+// CrossPrediction is the expected outcome of crossing two plants, or of
+// selfing one when both parents are the same plant.
+type CrossPrediction struct {
+	SeedParent   PlantOption
+	PollenParent PlantOption
+	SeedScores   []TraitScore
+	PollenScores []TraitScore
+	Loci         []LocusPrediction
+	F1Classes    []PhenotypeClass
+	F2Classes    []PhenotypeClass
+	Relationship *Relationship
+}
+
+// IsSelf reports whether the prediction is for a self-pollination.
+func (p CrossPrediction) IsSelf() bool {
+	return p.SeedParent.ID == p.PollenParent.ID
+}
+
+// Label names the cross, e.g. "Habanero x Charapita" or "Habanero (self)".
+func (p CrossPrediction) Label() string {
+	if p.IsSelf() {
+		return p.SeedParent.Name + " (self)"
+	}
+	return p.SeedParent.Name + " x " + p.PollenParent.Name
+}
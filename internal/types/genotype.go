@@ -0,0 +1,94 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type GenotypeSource string
+
+const (
+	GenotypeSourceTestCross GenotypeSource = "Test Cross"
+	GenotypeSourceProgeny   GenotypeSource = "Progeny"
+	GenotypeSourceMarker    GenotypeSource = "Marker"
+)
+
+// GenotypeCall is a recorded genotype of a plant at one of the KnownLoci.
+// Calls are kept as a history; the latest call per locus is current.
+type GenotypeCall struct {
+	ID        int            `db:"id"`
+	PlantID   int            `db:"plant_id"`
+	Locus     string         `db:"locus"`
+	Genotype  Genotype       `db:"genotype"`
+	Source    GenotypeSource `db:"source"`
+	CalledAt  time.Time      `db:"called_at"`
+	Notes     sql.NullString `db:"notes"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+	DeletedAt *time.Time     `db:"deleted_at"`
+}
+
+// LocusGenotype brings together what is known about a plant at one locus:
+// the recorded call, what its phenotype shows and what its parents and
+// offspring imply. Conflict is set when the relatives contradict the call.
+type LocusGenotype struct {
+	Locus     Locus
+	Call      *GenotypeCall
+	Phenotype Genotype
+	Inferred  Genotype
+	Reason    string
+	Conflict  bool
+}
+
+// Effective is the best available genotype: a recorded call, then one
+// inferred from relatives, then the phenotype.
+func (g LocusGenotype) Effective() Genotype {
+	switch {
+	case g.Call != nil && g.Call.Genotype != GenotypeUnknown:
+		return g.Call.Genotype
+	case g.Inferred != GenotypeUnknown && g.Inferred != "":
+		return g.Inferred
+	case g.Phenotype != "":
+		return g.Phenotype
+	default:
+		return GenotypeUnknown
+	}
+}
+
+// Suggested reports whether the relatives imply a call that has not been
+// recorded yet.
+func (g LocusGenotype) Suggested() bool {
+	if g.Inferred == "" || g.Inferred == GenotypeUnknown {
+		return false
+	}
+	return g.Call == nil || g.Call.Genotype != g.Inferred
+}
+
+func ParseGenotype(s string) (Genotype, error) {
+	switch s {
+	case "AA":
+		return GenotypeHomozygousDominant, nil
+	case "Aa":
+		return GenotypeHeterozygous, nil
+	case "aa":
+		return GenotypeHomozygousRecessive, nil
+	case "Unknown":
+		return GenotypeUnknown, nil
+	default:
+		return "", fmt.Errorf("invalid genotype value: %s", s)
+	}
+}
+
+func ParseGenotypeSource(s string) (GenotypeSource, error) {
+	switch s {
+	case "Test Cross":
+		return GenotypeSourceTestCross, nil
+	case "Progeny":
+		return GenotypeSourceProgeny, nil
+	case "Marker":
+		return GenotypeSourceMarker, nil
+	default:
+		return "", fmt.Errorf("invalid genotype source value: %s", s)
+	}
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type GerminationMethod string
+
+const (
+	GerminationPaperTowel GerminationMethod = "Paper Towel"
+	GerminationCellTray   GerminationMethod = "Cell Tray"
+	GerminationRockwool   GerminationMethod = "Rockwool"
+	GerminationDirectSow  GerminationMethod = "Direct Sow"
+)
+
+// GerminationMethods lists the methods in the order the forms offer them.
+var GerminationMethods = []GerminationMethod{
+	GerminationPaperTowel,
+	GerminationCellTray,
+	GerminationRockwool,
+	GerminationDirectSow,
+}
+
+// GerminationTrial is one sowing of seed from a lot or a catalog variety,
+// followed by daily counts of the seeds that came up. Germinated, Rate and
+// MeanDays are worked out from the counts; Promoted is the number of
+// seedlings already moved into plants.
+type GerminationTrial struct {
+	ID           int               `db:"id"`
+	SeedLotID    sql.NullInt64     `db:"seed_lot_id"`
+	VarietyID    sql.NullInt64     `db:"variety_id"`
+	SeedsSown    int               `db:"seeds_sown"`
+	Method       GerminationMethod `db:"method"`
+	TemperatureC sql.NullFloat64   `db:"temperature_c"`
+	SowDate      time.Time         `db:"sow_date"`
+	Notes        sql.NullString    `db:"notes"`
+	CreatedAt    time.Time         `db:"created_at"`
+	UpdatedAt    time.Time         `db:"updated_at"`
+	DeletedAt    *time.Time        `db:"deleted_at"`
+	LotPlantName sql.NullString    `db:"lot_plant_name"`
+	VarietyName  sql.NullString    `db:"variety_name"`
+	Germinated   int               `db:"germinated"`
+	Rate         float64           `db:"rate"`
+	MeanDays     sql.NullFloat64   `db:"mean_days"`
+	Promoted     int               `db:"promoted"`
+}
+
+// Label names what was sown, preferring the catalog variety.
+func (t GerminationTrial) Label() string {
+	switch {
+	case t.VarietyName.Valid && t.LotPlantName.Valid:
+		return fmt.Sprintf("%s (seed from %s)", t.VarietyName.String, t.LotPlantName.String)
+	case t.VarietyName.Valid:
+		return t.VarietyName.String
+	case t.LotPlantName.Valid:
+		return "Seed from " + t.LotPlantName.String
+	default:
+		return fmt.Sprintf("Trial %d", t.ID)
+	}
+}
+
+// SeedlingName is the name promoted seedlings are numbered under by default:
+// the variety, or else the mother plant without its own numbering.
+func (t GerminationTrial) SeedlingName() string {
+	if t.VarietyName.Valid {
+		return t.VarietyName.String
+	}
+	if t.LotPlantName.Valid {
+		if name := VarietyNameFromPlant(t.LotPlantName.String); name != "" {
+			return name
+		}
+		return t.LotPlantName.String
+	}
+	return t.Label()
+}
+
+// Promotable is the number of germinated seedlings not yet made into plants.
+func (t GerminationTrial) Promotable() int {
+	if t.Germinated < t.Promoted {
+		return 0
+	}
+	return t.Germinated - t.Promoted
+}
+
+// GerminationCount is the number of seeds that came up on one day of a
+// trial, not a running total.
+type GerminationCount struct {
+	ID         int       `db:"id"`
+	TrialID    int       `db:"trial_id"`
+	CountedOn  time.Time `db:"counted_on"`
+	Germinated int       `db:"germinated"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func ParseGerminationMethod(s string) (GerminationMethod, error) {
+	switch s {
+	case "Paper Towel":
+		return GerminationPaperTowel, nil
+	case "Cell Tray":
+		return GerminationCellTray, nil
+	case "Rockwool":
+		return GerminationRockwool, nil
+	case "Direct Sow":
+		return GerminationDirectSow, nil
+	default:
+		return "", fmt.Errorf("invalid germination method value: %s", s)
+	}
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type QualityGrade string
+
+const (
+	QualityGradeA    QualityGrade = "A"
+	QualityGradeB    QualityGrade = "B"
+	QualityGradeC    QualityGrade = "C"
+	QualityGradeCull QualityGrade = "Cull"
+)
+
+// Harvest is one picking from a plant. Plants are picked many times a season.
+type Harvest struct {
+	ID               int             `db:"id"`
+	PlantID          int             `db:"plant_id"`
+	HarvestDate      time.Time       `db:"harvest_date"`
+	PodCount         int             `db:"pod_count"`
+	TotalWeightGrams sql.NullFloat64 `db:"total_weight_grams"`
+	QualityGrade     QualityGrade    `db:"quality_grade"`
+	ImagePath        sql.NullString  `db:"image_path"`
+	Notes            sql.NullString  `db:"notes"`
+	CreatedAt        time.Time       `db:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at"`
+	DeletedAt        *time.Time      `db:"deleted_at"`
+}
+
+// YieldTotals sums the harvest log of a plant or a variety.
+type YieldTotals struct {
+	HarvestCount     int     `db:"harvest_count"`
+	PodCount         int     `db:"pod_count"`
+	TotalWeightGrams float64 `db:"total_weight_grams"`
+}
+
+// PlantYield is a plant's harvest totals over some period.
+type PlantYield struct {
+	PlantID   int    `db:"plant_id"`
+	PlantName string `db:"plant_name"`
+	YieldTotals
+}
+
+func ParseQualityGrade(s string) (QualityGrade, error) {
+	switch s {
+	case "A":
+		return QualityGradeA, nil
+	case "B":
+		return QualityGradeB, nil
+	case "C":
+		return QualityGradeC, nil
+	case "Cull":
+		return QualityGradeCull, nil
+	default:
+		return "", fmt.Errorf("invalid quality grade value: %s", s)
+	}
+}
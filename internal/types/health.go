@@ -0,0 +1,62 @@
+package types
+
+import (
+	"database/sql"
+	"time"
+)
+
+// HealthLevels lists plant health from worst to best.
+var HealthLevels = []PlantHealth{
+	PlantHealthPoor,
+	PlantHealthFair,
+	PlantHealthGood,
+	PlantHealthExcellent,
+}
+
+// Rank places a health value on HealthLevels, 1 for Poor up to 4 for
+// Excellent, or 0 when unknown.
+func (h PlantHealth) Rank() int {
+	for i, level := range HealthLevels {
+		if level == h {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+type HealthTrend string
+
+const (
+	HealthImproving HealthTrend = "Improving"
+	HealthStable    HealthTrend = "Stable"
+	HealthDeclining HealthTrend = "Declining"
+)
+
+// HealthTrendDays is how far back the plant cards look to call health
+// improving or declining.
+const HealthTrendDays = 14
+
+// HealthChange is one change of a plant's health. FromHealth is unset for
+// the health the plant was entered with.
+type HealthChange struct {
+	ID             int            `db:"id"`
+	PlantID        int            `db:"plant_id"`
+	FromHealth     sql.NullString `db:"from_health"`
+	ToHealth       PlantHealth    `db:"to_health"`
+	ChangedAt      time.Time      `db:"changed_at"`
+	JournalEntryID sql.NullInt64  `db:"journal_entry_id"`
+	JournalTitle   sql.NullString `db:"journal_title"`
+}
+
+// HealthAlert flags a plant whose health dropped at least two levels from
+// its best within the check window.
+type HealthAlert struct {
+	ID             int          `db:"id"`
+	PlantID        int          `db:"plant_id"`
+	PeakHealth     PlantHealth  `db:"peak_health"`
+	Health         PlantHealth  `db:"health"`
+	WindowDays     int          `db:"window_days"`
+	DetectedAt     time.Time    `db:"detected_at"`
+	AcknowledgedAt sql.NullTime `db:"acknowledged_at"`
+	PlantName      string       `db:"plant_name"`
+}
@@ -0,0 +1,134 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+type PodRipeness string
+
+const (
+	RipenessGreen    PodRipeness = "Green"
+	RipenessBreaker  PodRipeness = "Breaker"
+	RipenessRipe     PodRipeness = "Ripe"
+	RipenessOverripe PodRipeness = "Overripe"
+)
+
+// SHUPerCapsaicinoidMg converts a capsaicinoid content in mg per g of dry
+// pod to Scoville heat units, using the 16 SHU per ppm of capsaicin and
+// dihydrocapsaicin.
+const SHUPerCapsaicinoidMg = 16000
+
+// HeatLevel is one step of the 0-10 taste-panel scale with the rough
+// Scoville range it stands for.
+type HeatLevel struct {
+	Score  int
+	Label  string
+	MinSHU int
+	MaxSHU int
+}
+
+// HeatScale is the scale testers score pods on.
+var HeatScale = []HeatLevel{
+	{0, "No heat", 0, 0},
+	{1, "Very mild", 1, 500},
+	{2, "Mild", 500, 2500},
+	{3, "Warm", 2500, 10000},
+	{4, "Medium", 10000, 30000},
+	{5, "Medium hot", 30000, 50000},
+	{6, "Hot", 50000, 100000},
+	{7, "Very hot", 100000, 350000},
+	{8, "Extremely hot", 350000, 600000},
+	{9, "Superhot", 600000, 1000000},
+	{10, "Beyond superhot", 1000000, 0},
+}
+
+// HeatLabel names the level nearest to an average score.
+func HeatLabel(score float64) string {
+	i := int(math.Round(score))
+	if i < 0 || i >= len(HeatScale) {
+		return ""
+	}
+	return HeatScale[i].Label
+}
+
+// HeatTest is one tasting of a plant's pods, optionally backed by a lab
+// result given either directly in SHU or as capsaicinoid content.
+type HeatTest struct {
+	ID            int             `db:"id"`
+	PlantID       int             `db:"plant_id"`
+	HarvestID     sql.NullInt64   `db:"harvest_id"`
+	TestedAt      time.Time       `db:"tested_at"`
+	PanelScore    int             `db:"panel_score"`
+	SHU           sql.NullInt64   `db:"shu"`
+	Capsaicinoids sql.NullFloat64 `db:"capsaicinoids_mg_g"`
+	Tester        sql.NullString  `db:"tester"`
+	Ripeness      PodRipeness     `db:"ripeness"`
+	Notes         sql.NullString  `db:"notes"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+	DeletedAt     *time.Time      `db:"deleted_at"`
+	HarvestDate   sql.NullTime    `db:"harvest_date"`
+}
+
+// MeasuredSHU is the lab result in SHU, converted from capsaicinoid
+// content when no SHU figure was given.
+func (t HeatTest) MeasuredSHU() (int, bool) {
+	switch {
+	case t.SHU.Valid:
+		return int(t.SHU.Int64), true
+	case t.Capsaicinoids.Valid:
+		return int(math.Round(t.Capsaicinoids.Float64 * SHUPerCapsaicinoidMg)), true
+	default:
+		return 0, false
+	}
+}
+
+// HeatSummary averages a plant's heat tests.
+type HeatSummary struct {
+	TestCount    int             `db:"test_count"`
+	AverageScore sql.NullFloat64 `db:"average_score"`
+	MinScore     sql.NullInt64   `db:"min_score"`
+	MaxScore     sql.NullInt64   `db:"max_score"`
+	AverageSHU   sql.NullFloat64 `db:"average_shu"`
+	LabCount     int             `db:"lab_count"`
+}
+
+// HeatDistribution counts the siblings of one family by their average
+// panel score, rounded to the scale.
+type HeatDistribution struct {
+	Label  string
+	Plants int
+	Counts []int
+	Mean   float64
+	Min    float64
+	Max    float64
+}
+
+// MaxCount is the tallest bar, for scaling the histogram.
+func (d HeatDistribution) MaxCount() int {
+	var max int
+	for _, count := range d.Counts {
+		if count > max {
+			max = count
+		}
+	}
+	return max
+}
+
+func ParsePodRipeness(s string) (PodRipeness, error) {
+	switch s {
+	case "Green":
+		return RipenessGreen, nil
+	case "Breaker":
+		return RipenessBreaker, nil
+	case "Ripe":
+		return RipenessRipe, nil
+	case "Overripe":
+		return RipenessOverripe, nil
+	default:
+		return "", fmt.Errorf("invalid pod ripeness value: %s", s)
+	}
+}
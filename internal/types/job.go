@@ -0,0 +1,49 @@
+package types
+
+import (
+	"database/sql"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "Running"
+	JobSucceeded JobStatus = "Succeeded"
+	JobFailed    JobStatus = "Failed"
+)
+
+// ScheduledJob is a background job and when it runs. The schedule is a cron
+// expression; NextRunAt is worked out from it each time the job runs.
+type ScheduledJob struct {
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Schedule    string         `db:"schedule"`
+	Enabled     bool           `db:"enabled"`
+	LastRunAt   sql.NullTime   `db:"last_run_at"`
+	NextRunAt   sql.NullTime   `db:"next_run_at"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+	LastStatus  sql.NullString `db:"last_status"`
+	LastMessage sql.NullString `db:"last_message"`
+}
+
+// JobRun is one run of a job, on whichever server took the job's lock.
+type JobRun struct {
+	ID         int            `db:"id"`
+	JobName    string         `db:"job_name"`
+	Status     JobStatus      `db:"status"`
+	Message    sql.NullString `db:"message"`
+	Host       string         `db:"host"`
+	StartedAt  time.Time      `db:"started_at"`
+	FinishedAt sql.NullTime   `db:"finished_at"`
+}
+
+// Duration is how long the run took, or has taken so far.
+func (r JobRun) Duration(now time.Time) time.Duration {
+	end := now
+	if r.FinishedAt.Valid {
+		end = r.FinishedAt.Time
+	}
+	return end.Sub(r.StartedAt).Round(time.Millisecond)
+}
@@ -0,0 +1,167 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+// NotificationEvent is a kind of message the app sends out.
+type NotificationEvent string
+
+const (
+	NotifyTaskDue     NotificationEvent = "Task Due"
+	NotifyCareOverdue NotificationEvent = "Care Overdue"
+	NotifyHealthAlert NotificationEvent = "Health Alert"
+	NotifyDigest      NotificationEvent = "Digest"
+)
+
+var NotificationEvents = []NotificationEvent{
+	NotifyTaskDue,
+	NotifyCareOverdue,
+	NotifyHealthAlert,
+	NotifyDigest,
+}
+
+func ParseNotificationEvent(s string) (NotificationEvent, error) {
+	for _, event := range NotificationEvents {
+		if string(event) == s {
+			return event, nil
+		}
+	}
+	return "", fmt.Errorf("invalid notification event value: %s", s)
+}
+
+// ChannelKind is how a channel delivers: email, ntfy or Gotify push, or a
+// JSON webhook.
+type ChannelKind string
+
+const (
+	ChannelSMTP    ChannelKind = "SMTP"
+	ChannelNtfy    ChannelKind = "Ntfy"
+	ChannelGotify  ChannelKind = "Gotify"
+	ChannelWebhook ChannelKind = "Webhook"
+)
+
+var ChannelKinds = []ChannelKind{ChannelSMTP, ChannelNtfy, ChannelGotify, ChannelWebhook}
+
+func ParseChannelKind(s string) (ChannelKind, error) {
+	for _, kind := range ChannelKinds {
+		if string(kind) == s {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("invalid channel kind value: %s", s)
+}
+
+// TargetLabel names what a channel of kind k is sent to.
+func (k ChannelKind) TargetLabel() string {
+	switch k {
+	case ChannelSMTP:
+		return "Email address"
+	case ChannelNtfy:
+		return "Topic URL"
+	case ChannelGotify:
+		return "Server URL"
+	default:
+		return "Webhook URL"
+	}
+}
+
+// QuietHours is a daily window, in the user's time zone, when notifications
+// are held back. Only the time of day of Start and End is used; the window
+// runs past midnight when End is before Start.
+type QuietHours struct {
+	Start    sql.NullTime `db:"quiet_start"`
+	End      sql.NullTime `db:"quiet_end"`
+	Timezone string       `db:"timezone"`
+}
+
+// Contains reports whether t falls in the quiet window.
+func (q QuietHours) Contains(t time.Time) bool {
+	if !q.Start.Valid || !q.End.Valid {
+		return false
+	}
+	if loc, err := time.LoadLocation(q.Timezone); err == nil {
+		t = t.In(loc)
+	}
+	now := t.Hour()*60 + t.Minute()
+	from := q.Start.Time.Hour()*60 + q.Start.Time.Minute()
+	until := q.End.Time.Hour()*60 + q.End.Time.Minute()
+
+	switch {
+	case from < until:
+		return now >= from && now < until
+	case from > until:
+		return now >= from || now < until
+	default:
+		return false
+	}
+}
+
+// Label shows the window as "22:00–07:00 Europe/Berlin".
+func (q QuietHours) Label() string {
+	if !q.Start.Valid || !q.End.Valid {
+		return ""
+	}
+	return q.Start.Time.Format("15:04") + "–" + q.End.Time.Format("15:04") + " " + q.Timezone
+}
+
+// User is someone who receives notifications.
+type User struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+	QuietHours
+	DigestFrequency DigestFrequency `db:"digest_frequency"`
+	DigestSections  pq.StringArray  `db:"digest_sections"`
+	CreatedAt       time.Time       `db:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at"`
+	DeletedAt       *time.Time      `db:"deleted_at"`
+}
+
+// NotificationChannel is one place a user gets notifications. Events lists
+// the events the channel is for; an empty list takes all of them.
+type NotificationChannel struct {
+	ID        int            `db:"id"`
+	UserID    int            `db:"user_id"`
+	Kind      ChannelKind    `db:"kind"`
+	Target    string         `db:"target"`
+	Token     sql.NullString `db:"token"`
+	Events    pq.StringArray `db:"events"`
+	Enabled   bool           `db:"enabled"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+// Wants reports whether the channel takes the event.
+func (c NotificationChannel) Wants(event NotificationEvent) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationDelivery is a message queued for one channel. It stays
+// pending, and is retried, until it is sent.
+type NotificationDelivery struct {
+	ID            int               `db:"id"`
+	ChannelID     int               `db:"channel_id"`
+	Event         NotificationEvent `db:"event"`
+	Title         string            `db:"title"`
+	Body          string            `db:"body"`
+	HTML          sql.NullString    `db:"html"`
+	URL           sql.NullString    `db:"url"`
+	Attempts      int               `db:"attempts"`
+	LastError     sql.NullString    `db:"last_error"`
+	CreatedAt     time.Time         `db:"created_at"`
+	SentAt        sql.NullTime      `db:"sent_at"`
+	ClaimedAt     sql.NullTime      `db:"claimed_at"`
+	ChannelKind   ChannelKind       `db:"channel_kind"`
+	ChannelTarget string            `db:"channel_target"`
+	UserName      string            `db:"user_name"`
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ParentRole string
+
+const (
+	ParentRoleSeed   ParentRole = "seed"
+	ParentRolePollen ParentRole = "pollen"
+)
+
+// PedigreeNode is a plant found while walking a pedigree, together with its
+// distance from the plant the walk started at.
+type PedigreeNode struct {
+	ID              int            `db:"id"`
+	Name            string         `db:"name"`
+	Species         Species        `db:"species"`
+	IsCross         bool           `db:"is_cross"`
+	Generation      sql.NullString `db:"generation"`
+	SeedParentID    sql.NullInt64  `db:"seed_parent_id"`
+	PollenParentID  sql.NullInt64  `db:"pollen_parent_id"`
+	SeedParentExt   sql.NullString `db:"seed_parent_external"`
+	PollenParentExt sql.NullString `db:"pollen_parent_external"`
+	DeletedAt       *time.Time     `db:"deleted_at"`
+	Depth           int            `db:"depth"`
+}
+
+// Lineage holds both directions of a plant's pedigree.
+type Lineage struct {
+	PlantID     int
+	Ancestors   []PedigreeNode
+	Descendants []PedigreeNode
+}
+
+// PlantOption is the minimal plant projection used to fill parent pickers.
+type PlantOption struct {
+	ID         int            `db:"id"`
+	Name       string         `db:"name"`
+	Species    Species        `db:"species"`
+	Generation sql.NullString `db:"generation"`
+}
+
+// Relationship describes how related two plants are and how inbred a cross
+// between them would be.
+type Relationship struct {
+	PlantA      PlantOption
+	PlantB      PlantOption
+	InbreedingA float64
+	InbreedingB float64
+	Kinship     float64
+	Coefficient float64
+}
+
+// OffspringInbreeding is the inbreeding coefficient of a cross between the
+// two plants, which equals their kinship.
+func (r Relationship) OffspringInbreeding() float64 {
+	return r.Kinship
+}
@@ -23,64 +23,172 @@ const (
 	GrowthStageVegetative GrowthStage = "Vegetative"
 	GrowthStageFlowering  GrowthStage = "Flowering"
 	GrowthStageFruiting   GrowthStage = "Fruiting"
+
+	GrowthStageOverwintering GrowthStage = "Overwintering"
+	GrowthStageDormant       GrowthStage = "Dormant"
+	GrowthStageDead          GrowthStage = "Dead"
+	GrowthStageGivenAway     GrowthStage = "Given Away"
 )
 
-type Species string
+// GrowthStages lists the stages in the order a plant goes through them.
+var GrowthStages = []GrowthStage{
+	GrowthStageSeed,
+	GrowthStageSeedling,
+	GrowthStageVegetative,
+	GrowthStageFlowering,
+	GrowthStageFruiting,
+}
+
+// LifecycleStages are the states a plant moves to outside the growing
+// sequence. Dead and Given Away end its time in the collection.
+var LifecycleStages = []GrowthStage{
+	GrowthStageOverwintering,
+	GrowthStageDormant,
+	GrowthStageDead,
+	GrowthStageGivenAway,
+}
+
+// AllGrowthStages lists the growing stages followed by the lifecycle ones.
+func AllGrowthStages() []GrowthStage {
+	return append(append([]GrowthStage{}, GrowthStages...), LifecycleStages...)
+}
+
+// IsEnd reports whether the plant is no longer in the collection.
+func (g GrowthStage) IsEnd() bool {
+	return g == GrowthStageDead || g == GrowthStageGivenAway
+}
+
+// ReasonLabel names what the reason of a move to g records.
+func (g GrowthStage) ReasonLabel() string {
+	switch g {
+	case GrowthStageDead:
+		return "Cause of death"
+	case GrowthStageGivenAway:
+		return "Given to"
+	default:
+		return "Reason"
+	}
+}
+
+// Next returns the growing stage after g. The second return value is false
+// for the last growing stage and for lifecycle stages.
+func (g GrowthStage) Next() (GrowthStage, bool) {
+	for i, stage := range GrowthStages {
+		if stage == g && i+1 < len(GrowthStages) {
+			return GrowthStages[i+1], true
+		}
+	}
+	return "", false
+}
+
+// SelectionStatus is the breeder's keep/cull decision on a plant.
+type SelectionStatus string
 
 const (
-	SpeciesAnnuum        Species = "Capsicum annuum"
-	SpeciesChinense      Species = "Capsicum chinense"
-	SpeciesBaccatum      Species = "Capsicum baccatum"
-	SpeciesFruitescens   Species = "Capsicum frutescens"
-	SpeciesPubescens     Species = "Capsicum pubescens"
-	SpeciesRhomboideum   Species = "Capsicum rhomboideum"
-	SpeciesPraetermissum Species = "Capsicum praetermissum"
-	SpeciesCardenasii    Species = "Capsicum cardenasii"
-	SpeciesEximium       Species = "Capsicum eximium"
-	SpeciesGalapagoense  Species = "Capsicum galapagoense"
-	SpeciesTovarii       Species = "Capsicum tovarii"
-	SpeciesFlexuosum     Species = "Capsicum flexuosum"
-	SpeciesExile         Species = "Capsicum exile"
+	SelectionUndecided SelectionStatus = "Undecided"
+	SelectionKeep      SelectionStatus = "Keep"
+	SelectionCull      SelectionStatus = "Cull"
 )
 
+// Species is a plant's species name as listed in the species table.
+type Species string
+
 type Plant struct {
-	ID             int            `db:"id"`
-	Name           string         `db:"name"`
-	Species        Species        `db:"species"`
-	Health         PlantHealth    `db:"health"`
-	GrowthStage    GrowthStage    `db:"growth_stage"`
-	PlantingDate   time.Time      `db:"planting_date"`
-	LastWatered    *time.Time     `db:"last_watered_at"`
-	LastFertilized *time.Time     `db:"last_fertilized_at"`
-	ImagePath      string         `db:"image_path"`
-	Notes          string         `db:"notes"`
-	DeletedAt      *time.Time     `db:"deleted_at"`
-	CreatedAt      time.Time      `db:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at"`
-	IsCross        bool           `db:"is_cross"`
-	Generation     sql.NullString `db:"generation"`
-	IsHarvested    bool           `db:"is_harvested"`
-	HarvestedAt    sql.NullTime   `db:"harvested_at"`
+	ID                  int             `db:"id"`
+	Name                string          `db:"name"`
+	Species             Species         `db:"species"`
+	Health              PlantHealth     `db:"health"`
+	GrowthStage         GrowthStage     `db:"growth_stage"`
+	PlantingDate        time.Time       `db:"planting_date"`
+	LastWatered         *time.Time      `db:"last_watered_at"`
+	LastFertilized      *time.Time      `db:"last_fertilized_at"`
+	ImagePath           string          `db:"image_path"`
+	Notes               string          `db:"notes"`
+	DeletedAt           *time.Time      `db:"deleted_at"`
+	CreatedAt           time.Time       `db:"created_at"`
+	UpdatedAt           time.Time       `db:"updated_at"`
+	IsCross             bool            `db:"is_cross"`
+	Generation          sql.NullString  `db:"generation"`
+	SeasonFinished      bool            `db:"season_finished"`
+	SeasonFinishedAt    sql.NullTime    `db:"season_finished_at"`
+	SeedParentID        sql.NullInt64   `db:"seed_parent_id"`
+	PollenParentID      sql.NullInt64   `db:"pollen_parent_id"`
+	SeedParentExt       sql.NullString  `db:"seed_parent_external"`
+	PollenParentExt     sql.NullString  `db:"pollen_parent_external"`
+	SeedLotID           sql.NullInt64   `db:"seed_lot_id"`
+	SelectionStatus     SelectionStatus `db:"selection_status"`
+	VarietyID           sql.NullInt64   `db:"variety_id"`
+	GerminationTrialID  sql.NullInt64   `db:"germination_trial_id"`
+	WateringInterval    sql.NullInt64   `db:"watering_interval_days"`
+	FertilizingInterval sql.NullInt64   `db:"fertilizing_interval_days"`
 }
 
 type PlantWithDates struct {
-	ID              int            `db:"id"`
-	Name            string         `db:"name"`
-	Species         Species        `db:"species"`
-	Health          PlantHealth    `db:"health"`
-	GrowthStage     GrowthStage    `db:"growth_stage"`
-	PlantingDate    time.Time      `db:"planting_date"`
-	ImagePath       string         `db:"image_path"`
-	Notes           string         `db:"notes"`
-	CreatedAt       time.Time      `db:"created_at"`
-	UpdatedAt       time.Time      `db:"updated_at"`
-	DeletedAt       *time.Time     `db:"deleted_at"`
-	LastWatering    *time.Time     `db:"last_watered_at"`
-	LastFertilizing *time.Time     `db:"last_fertilized_at"`
-	IsCross         bool           `db:"is_cross"`
-	Generation      sql.NullString `db:"generation"`
-	IsHarvested     bool           `db:"is_harvested"`
-	HarvestedAt     sql.NullTime   `db:"harvested_at"`
+	ID                  int             `db:"id"`
+	Name                string          `db:"name"`
+	Species             Species         `db:"species"`
+	Health              PlantHealth     `db:"health"`
+	GrowthStage         GrowthStage     `db:"growth_stage"`
+	PlantingDate        time.Time       `db:"planting_date"`
+	ImagePath           string          `db:"image_path"`
+	Notes               string          `db:"notes"`
+	CreatedAt           time.Time       `db:"created_at"`
+	UpdatedAt           time.Time       `db:"updated_at"`
+	DeletedAt           *time.Time      `db:"deleted_at"`
+	LastWatering        *time.Time      `db:"last_watered_at"`
+	LastFertilizing     *time.Time      `db:"last_fertilized_at"`
+	IsCross             bool            `db:"is_cross"`
+	Generation          sql.NullString  `db:"generation"`
+	SeasonFinished      bool            `db:"season_finished"`
+	SeasonFinishedAt    sql.NullTime    `db:"season_finished_at"`
+	SeedParentID        sql.NullInt64   `db:"seed_parent_id"`
+	PollenParentID      sql.NullInt64   `db:"pollen_parent_id"`
+	SeedParentExt       sql.NullString  `db:"seed_parent_external"`
+	PollenParentExt     sql.NullString  `db:"pollen_parent_external"`
+	SeedParentName      sql.NullString  `db:"seed_parent_name"`
+	PollenParentName    sql.NullString  `db:"pollen_parent_name"`
+	VarietyName         sql.NullString  `db:"variety_name"`
+	SeedLotID           sql.NullInt64   `db:"seed_lot_id"`
+	SelectionStatus     SelectionStatus `db:"selection_status"`
+	VarietyID           sql.NullInt64   `db:"variety_id"`
+	GerminationTrialID  sql.NullInt64   `db:"germination_trial_id"`
+	WateringInterval    sql.NullInt64   `db:"watering_interval_days"`
+	FertilizingInterval sql.NullInt64   `db:"fertilizing_interval_days"`
+	HarvestCount        int             `db:"harvest_count"`
+	PodTotal            int             `db:"pod_total"`
+	WeightTotal         float64         `db:"weight_total"`
+	VarietyHarvests     int             `db:"variety_harvest_count"`
+	VarietyPodTotal     int             `db:"variety_pod_total"`
+	VarietyWeightTotal  float64         `db:"variety_weight_total"`
+	HeatScore           sql.NullFloat64 `db:"heat_score"`
+	HeatSHU             sql.NullFloat64 `db:"heat_shu"`
+	HealthTrend         HealthTrend     `db:"health_trend"`
+	WateringDue         sql.NullTime    `db:"watering_due"`
+	WateringStatus      CareStatus      `db:"watering_status"`
+	FertilizingDue      sql.NullTime    `db:"fertilizing_due"`
+	FertilizingStatus   CareStatus      `db:"fertilizing_status"`
+	DueTasks            int             `db:"due_tasks"`
+	OverdueTasks        int             `db:"overdue_tasks"`
+}
+
+// PlantFilters holds the plant list filters. Empty fields are not applied.
+type PlantFilters struct {
+	GrowthStage string
+	Species     string
+	Cross       string
+	Season      string
+	Generation  string
+	Project     int
+	Heat        string
+	Care        string
+	Sort        string
+}
+
+// PlantFilterOptions holds the data-driven choices for the plant list filters.
+type PlantFilterOptions struct {
+	Species     []Species
+	Generations []string
+	Projects    []ProjectOption
 }
 
 type JournalEntry struct {
@@ -123,40 +231,28 @@ func ParseGrowthStage(s string) (GrowthStage, error) {
 		return GrowthStageFlowering, nil
 	case "Fruiting":
 		return GrowthStageFruiting, nil
+	case "Overwintering":
+		return GrowthStageOverwintering, nil
+	case "Dormant":
+		return GrowthStageDormant, nil
+	case "Dead":
+		return GrowthStageDead, nil
+	case "Given Away":
+		return GrowthStageGivenAway, nil
 	default:
 		return "", fmt.Errorf("invalid growth stage value: %s", s)
 	}
 }
 
-func ParseSpecies(s string) (Species, error) {
+func ParseSelectionStatus(s string) (SelectionStatus, error) {
 	switch s {
-	case "Capsicum annuum":
-		return SpeciesAnnuum, nil
-	case "Capsicum chinense":
-		return SpeciesChinense, nil
-	case "Capsicum baccatum":
-		return SpeciesBaccatum, nil
-	case "Capsicum frutescens":
-		return SpeciesFruitescens, nil
-	case "Capsicum pubescens":
-		return SpeciesPubescens, nil
-	case "Capsicum rhomboideum":
-		return SpeciesRhomboideum, nil
-	case "Capsicum praetermissum":
-		return SpeciesPraetermissum, nil
-	case "Capsicum cardenasii":
-		return SpeciesCardenasii, nil
-	case "Capsicum eximium":
-		return SpeciesEximium, nil
-	case "Capsicum galapagoense":
-		return SpeciesGalapagoense, nil
-	case "Capsicum tovarii":
-		return SpeciesTovarii, nil
-	case "Capsicum flexuosum":
-		return SpeciesFlexuosum, nil
-	case "Capsicum exile":
-		return SpeciesExile, nil
+	case "Undecided":
+		return SelectionUndecided, nil
+	case "Keep":
+		return SelectionKeep, nil
+	case "Cull":
+		return SelectionCull, nil
 	default:
-		return "", fmt.Errorf("invalid species value: %s", s)
+		return "", fmt.Errorf("invalid selection status value: %s", s)
 	}
 }
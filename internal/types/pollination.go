@@ -0,0 +1,114 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type IsolationMethod string
+
+const (
+	IsolationBagged  IsolationMethod = "Bagged"
+	IsolationCaged   IsolationMethod = "Caged"
+	IsolationClipped IsolationMethod = "Clipped"
+	IsolationNone    IsolationMethod = "None"
+)
+
+type PollinationOutcome string
+
+const (
+	PollinationPending PollinationOutcome = "Pending"
+	PollinationSet     PollinationOutcome = "Set"
+	PollinationDropped PollinationOutcome = "Dropped"
+)
+
+// Pollination is a hand-pollination of one tagged flower on PlantID. The
+// donor is another plant, the same plant for a self, or an external variety.
+type Pollination struct {
+	ID              int                `db:"id"`
+	PlantID         int                `db:"plant_id"`
+	DonorPlantID    sql.NullInt64      `db:"donor_plant_id"`
+	DonorExternal   sql.NullString     `db:"donor_external"`
+	PollinationDate time.Time          `db:"pollination_date"`
+	FlowerTag       sql.NullString     `db:"flower_tag"`
+	Emasculated     bool               `db:"emasculated"`
+	IsolationMethod IsolationMethod    `db:"isolation_method"`
+	Outcome         PollinationOutcome `db:"outcome"`
+	OutcomeDate     sql.NullTime       `db:"outcome_date"`
+	Notes           sql.NullString     `db:"notes"`
+	PlannedCrossID  sql.NullInt64      `db:"planned_cross_id"`
+	CreatedAt       time.Time          `db:"created_at"`
+	UpdatedAt       time.Time          `db:"updated_at"`
+	DeletedAt       *time.Time         `db:"deleted_at"`
+	PlantName       string             `db:"plant_name"`
+	DonorName       sql.NullString     `db:"donor_name"`
+	SeedLotID       sql.NullInt64      `db:"seed_lot_id"`
+}
+
+// IsSelf reports whether the flower was pollinated with its own plant's pollen.
+func (p Pollination) IsSelf() bool {
+	return p.DonorPlantID.Valid && int(p.DonorPlantID.Int64) == p.PlantID
+}
+
+// DonorLabel names the pollen donor for display.
+func (p Pollination) DonorLabel() string {
+	switch {
+	case p.IsSelf():
+		return "Self"
+	case p.DonorName.Valid:
+		return p.DonorName.String
+	case p.DonorExternal.Valid:
+		return p.DonorExternal.String
+	default:
+		return "Unknown"
+	}
+}
+
+// PollinationStats tallies pollination outcomes for one grouping, such as a
+// donor/recipient pair or an isolation technique.
+type PollinationStats struct {
+	Label    string `db:"label"`
+	Attempts int    `db:"attempts"`
+	Set      int    `db:"set_count"`
+	Dropped  int    `db:"dropped_count"`
+	Pending  int    `db:"pending_count"`
+}
+
+// SuccessRate is the share of resolved pollinations that set fruit. Pending
+// pollinations are left out so fresh attempts do not drag the rate down.
+func (s PollinationStats) SuccessRate() float64 {
+	resolved := s.Set + s.Dropped
+	if resolved == 0 {
+		return 0
+	}
+	return float64(s.Set) / float64(resolved)
+}
+
+func ParseIsolationMethod(s string) (IsolationMethod, error) {
+	switch s {
+	case "Bagged":
+		return IsolationBagged, nil
+	case "Caged":
+		return IsolationCaged, nil
+	case "Clipped":
+		return IsolationClipped, nil
+	case "None":
+		return IsolationNone, nil
+	default:
+		return "", fmt.Errorf("invalid isolation method value: %s", s)
+	}
+}
+
+func ParsePollinationOutcome(s string) (PollinationOutcome, error) {
+	switch s {
+	case "Pending":
+		return PollinationPending, nil
+	case "Set":
+		return PollinationSet, nil
+	case "Dropped":
+		return PollinationDropped, nil
+	default:
+		return "", fmt.Errorf("invalid pollination outcome value: %s", s)
+	}
+}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+type ProjectStatus string
+
+const (
+	ProjectPlanning  ProjectStatus = "Planning"
+	ProjectActive    ProjectStatus = "Active"
+	ProjectPaused    ProjectStatus = "Paused"
+	ProjectCompleted ProjectStatus = "Completed"
+	ProjectAbandoned ProjectStatus = "Abandoned"
+)
+
+// BreedingProject groups the plants of one breeding line, such as the
+// parents, F1s and later selections of a cross, under a common goal.
+type BreedingProject struct {
+	ID         int            `db:"id"`
+	Name       string         `db:"name"`
+	Goal       sql.NullString `db:"goal"`
+	Status     ProjectStatus  `db:"status"`
+	StartedAt  time.Time      `db:"started_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at"`
+	DeletedAt  *time.Time     `db:"deleted_at"`
+	PlantCount int            `db:"plant_count"`
+}
+
+// ProjectOption is the short form of a project used in select boxes.
+type ProjectOption struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+// ProjectTargetTrait is a trait the project selects for. Categorical traits
+// target one state, numeric traits a range.
+type ProjectTargetTrait struct {
+	ProjectID  int             `db:"project_id"`
+	TraitID    int             `db:"trait_id"`
+	StateValue sql.NullInt64   `db:"state_value"`
+	MinValue   sql.NullFloat64 `db:"min_value"`
+	MaxValue   sql.NullFloat64 `db:"max_value"`
+	Notes      sql.NullString  `db:"notes"`
+	TraitName  string          `db:"trait_name"`
+	Unit       sql.NullString  `db:"unit"`
+	StateLabel sql.NullString  `db:"state_label"`
+}
+
+// Target formats the targeted state or range for display.
+func (t ProjectTargetTrait) Target() string {
+	var unit string
+	if t.Unit.Valid {
+		unit = " " + t.Unit.String
+	}
+	switch {
+	case t.StateLabel.Valid:
+		return t.StateLabel.String
+	case t.StateValue.Valid:
+		return strconv.FormatInt(t.StateValue.Int64, 10)
+	case t.MinValue.Valid && t.MaxValue.Valid:
+		return fmt.Sprintf("%g–%g%s", t.MinValue.Float64, t.MaxValue.Float64, unit)
+	case t.MinValue.Valid:
+		return fmt.Sprintf("≥ %g%s", t.MinValue.Float64, unit)
+	case t.MaxValue.Valid:
+		return fmt.Sprintf("≤ %g%s", t.MaxValue.Float64, unit)
+	default:
+		return "Any"
+	}
+}
+
+// ProjectMember is a plant in a project as listed on the dashboard.
+type ProjectMember struct {
+	ID              int             `db:"id"`
+	Name            string          `db:"name"`
+	Species         Species         `db:"species"`
+	Generation      sql.NullString  `db:"generation"`
+	GrowthStage     GrowthStage     `db:"growth_stage"`
+	SeasonFinished  bool            `db:"season_finished"`
+	SelectionStatus SelectionStatus `db:"selection_status"`
+}
+
+// GenerationSummary counts a project's plants and selection decisions in one
+// generation.
+type GenerationSummary struct {
+	Generation string `db:"generation"`
+	Plants     int    `db:"plants"`
+	Keep       int    `db:"keep_count"`
+	Cull       int    `db:"cull_count"`
+	Undecided  int    `db:"undecided_count"`
+}
+
+// KeptPercent is the share of decided plants that were kept. Undecided plants
+// are left out until a decision is made.
+func (g GenerationSummary) KeptPercent() float64 {
+	decided := g.Keep + g.Cull
+	if decided == 0 {
+		return 0
+	}
+	return float64(g.Keep) * 100 / float64(decided)
+}
+
+// SelectionIntensity is the standardized selection intensity i for the kept
+// proportion p, assuming truncation selection on a normally distributed
+// trait: i = φ(z)/p, where z is the truncation point. It is 0 when nothing
+// or everything was kept.
+func (g GenerationSummary) SelectionIntensity() float64 {
+	decided := g.Keep + g.Cull
+	if g.Keep == 0 || g.Keep == decided {
+		return 0
+	}
+	p := float64(g.Keep) / float64(decided)
+	z := math.Sqrt2 * math.Erfinv(1-2*p)
+	return math.Exp(-z*z/2) / math.Sqrt(2*math.Pi) / p
+}
+
+// ProjectDashboard collects everything shown on a project's dashboard.
+type ProjectDashboard struct {
+	Project          BreedingProject
+	Targets          []ProjectTargetTrait
+	Generations      []GenerationSummary
+	Members          []ProjectMember
+	OpenPollinations []Pollination
+}
+
+func ParseProjectStatus(s string) (ProjectStatus, error) {
+	switch s {
+	case "Planning":
+		return ProjectPlanning, nil
+	case "Active":
+		return ProjectActive, nil
+	case "Paused":
+		return ProjectPaused, nil
+	case "Completed":
+		return ProjectCompleted, nil
+	case "Abandoned":
+		return ProjectAbandoned, nil
+	default:
+		return "", fmt.Errorf("invalid project status value: %s", s)
+	}
+}
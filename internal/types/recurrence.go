@@ -0,0 +1,213 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Recurrence is the subset of an iCalendar RRULE that tasks repeat by: FREQ
+// (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL, BYDAY on weekly rules, and
+// COUNT or UNTIL to end the series.
+type Recurrence struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    time.Time
+}
+
+// ParseRecurrence reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH".
+// An "RRULE:" prefix is accepted. Parts outside the supported subset are
+// rejected rather than ignored, so a rule never silently means less than
+// it says.
+func ParseRecurrence(rule string) (Recurrence, error) {
+	r := Recurrence{Interval: 1}
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	if rule == "" {
+		return r, fmt.Errorf("empty recurrence rule")
+	}
+
+	for _, part := range strings.Split(strings.Trim(rule, ";"), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return r, fmt.Errorf("invalid rule part: %s", part)
+		}
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+				r.Freq = Frequency(value)
+			default:
+				return r, fmt.Errorf("unsupported frequency: %s", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return r, fmt.Errorf("invalid interval: %s", value)
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day := -1
+				for i, c := range weekdayCodes {
+					if c == code {
+						day = i
+					}
+				}
+				if day < 0 {
+					return r, fmt.Errorf("unsupported day: %s", code)
+				}
+				r.ByDay = append(r.ByDay, time.Weekday(day))
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return r, fmt.Errorf("invalid count: %s", value)
+			}
+			r.Count = n
+		case "UNTIL":
+			if len(value) < 8 {
+				return r, fmt.Errorf("invalid until date: %s", value)
+			}
+			until, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return r, fmt.Errorf("invalid until date: %s", value)
+			}
+			r.Until = until
+		default:
+			return r, fmt.Errorf("unsupported rule part: %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return r, fmt.Errorf("rule has no FREQ")
+	}
+	if len(r.ByDay) > 0 && r.Freq != FrequencyWeekly {
+		return r, fmt.Errorf("BYDAY is only supported on weekly rules")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return r, fmt.Errorf("a rule cannot have both COUNT and UNTIL")
+	}
+	return r, nil
+}
+
+// String gives the rule back in canonical RRULE form.
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = weekdayCodes[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Describe puts the rule in words, e.g. "Every 2 weeks on Mon, Thu".
+func (r Recurrence) Describe() string {
+	units := map[Frequency]string{
+		FrequencyDaily:   "day",
+		FrequencyWeekly:  "week",
+		FrequencyMonthly: "month",
+		FrequencyYearly:  "year",
+	}
+	text := "Every " + units[r.Freq]
+	if r.Interval > 1 {
+		text = fmt.Sprintf("Every %d %ss", r.Interval, units[r.Freq])
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()[:3]
+		}
+		text += " on " + strings.Join(days, ", ")
+	}
+	if r.Count > 0 {
+		text += fmt.Sprintf(", %d times", r.Count)
+	}
+	if !r.Until.IsZero() {
+		text += " until " + r.Until.Format("Jan 02, 2006")
+	}
+	return text
+}
+
+// after returns the first occurrence strictly after prev, ignoring COUNT
+// and UNTIL.
+func (r Recurrence) after(prev time.Time) time.Time {
+	switch r.Freq {
+	case FrequencyDaily:
+		return prev.AddDate(0, 0, r.Interval)
+	case FrequencyWeekly:
+		if len(r.ByDay) == 0 {
+			return prev.AddDate(0, 0, 7*r.Interval)
+		}
+		// Weeks start on Monday, as RRULE's default WKST does
+		weekStart := func(t time.Time) time.Time {
+			return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+		}
+		base := weekStart(prev)
+		for d := prev.AddDate(0, 0, 1); ; d = d.AddDate(0, 0, 1) {
+			weeks := int(weekStart(d).Sub(base).Round(24*time.Hour).Hours()/24) / 7
+			if weeks%r.Interval != 0 {
+				continue
+			}
+			for _, day := range r.ByDay {
+				if d.Weekday() == day {
+					return d
+				}
+			}
+		}
+	case FrequencyMonthly:
+		return addMonthsClamped(prev, r.Interval)
+	default:
+		return addMonthsClamped(prev, 12*r.Interval)
+	}
+}
+
+// addMonthsClamped moves t on by months, keeping to the last day of a
+// shorter month instead of spilling into the next one.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+}
+
+// Next returns the occurrence after prev given how many have been done so
+// far. The second return value is false once the series has ended.
+func (r Recurrence) Next(prev time.Time, done int) (time.Time, bool) {
+	if r.Count > 0 && done >= r.Count {
+		return time.Time{}, false
+	}
+	next := r.after(prev)
+	if !r.Until.IsZero() && next.After(r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     string
+		describe string
+		wantErr  bool
+	}{
+		{in: "FREQ=DAILY", want: "FREQ=DAILY", describe: "Every day"},
+		{in: "rrule:freq=weekly;interval=2;byday=mo,th;", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", describe: "Every 2 weeks on Mon, Thu"},
+		{in: "FREQ=MONTHLY;INTERVAL=1;COUNT=3", want: "FREQ=MONTHLY;COUNT=3", describe: "Every month, 3 times"},
+		{in: "FREQ=YEARLY;UNTIL=20301231T000000Z", want: "FREQ=YEARLY;UNTIL=20301231", describe: "Every year until Dec 31, 2030"},
+		{in: "", wantErr: true},
+		{in: "INTERVAL=2", wantErr: true},
+		{in: "FREQ=HOURLY", wantErr: true},
+		{in: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{in: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{in: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{in: "FREQ=DAILY;COUNT=2;UNTIL=20300101", wantErr: true},
+		{in: "FREQ=DAILY;BYMONTH=3", wantErr: true},
+		{in: "FREQ=DAILY;UNTIL=2030", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRecurrence(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRecurrence(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseRecurrence(%q).String() = %q, want %q", tt.in, got.String(), tt.want)
+		}
+		if got.Describe() != tt.describe {
+			t.Errorf("ParseRecurrence(%q).Describe() = %q, want %q", tt.in, got.Describe(), tt.describe)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		rule  string
+		start string
+		want  []string
+	}{
+		{rule: "FREQ=DAILY;INTERVAL=3", start: "2026-02-27", want: []string{"2026-03-02", "2026-03-05"}},
+		{rule: "FREQ=WEEKLY", start: "2026-03-04", want: []string{"2026-03-11", "2026-03-18"}},
+		{rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", start: "2026-01-05", want: []string{"2026-01-08", "2026-01-19", "2026-01-22"}},
+		{rule: "FREQ=WEEKLY;BYDAY=SU", start: "2026-01-07", want: []string{"2026-01-11", "2026-01-18"}},
+		{rule: "FREQ=MONTHLY", start: "2026-01-31", want: []string{"2026-02-28", "2026-03-28"}},
+		{rule: "FREQ=YEARLY", start: "2028-02-29", want: []string{"2029-02-28"}},
+		{rule: "FREQ=DAILY;COUNT=3", start: "2026-05-01", want: []string{"2026-05-02", "2026-05-03"}},
+		{rule: "FREQ=WEEKLY;UNTIL=20260520", start: "2026-05-01", want: []string{"2026-05-08", "2026-05-15"}},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q) error: %v", tt.rule, err)
+		}
+
+		prev, done := day(tt.start), 1
+		for _, want := range tt.want {
+			next, ok := r.Next(prev, done)
+			if !ok || !next.Equal(day(want)) {
+				t.Errorf("%s after %s: got %s, %v, want %s", tt.rule, prev.Format("2006-01-02"), next.Format("2006-01-02"), ok, want)
+				break
+			}
+			prev, done = next, done+1
+		}
+		if r.Count > 0 || !r.Until.IsZero() {
+			if next, ok := r.Next(prev, done); ok {
+				t.Errorf("%s: series continued to %s, want it ended", tt.rule, next.Format("2006-01-02"))
+			}
+		}
+	}
+}
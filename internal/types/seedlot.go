@@ -0,0 +1,49 @@
+package types
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SeedLot is a batch of seed saved from one plant. PollenParentID is unset
+// for open-pollinated seed and equal to PlantID for selfed seed.
+type SeedLot struct {
+	ID               int            `db:"id"`
+	PlantID          int            `db:"plant_id"`
+	PollenParentID   sql.NullInt64  `db:"pollen_parent_id"`
+	PollenParentExt  sql.NullString `db:"pollen_parent_external"`
+	SeedCount        int            `db:"seed_count"`
+	HarvestDate      time.Time      `db:"harvest_date"`
+	StorageLocation  sql.NullString `db:"storage_location"`
+	GerminationNotes sql.NullString `db:"germination_notes"`
+	HarvestID        sql.NullInt64  `db:"harvest_id"`
+	PollinationID    sql.NullInt64  `db:"pollination_id"`
+	CreatedAt        time.Time      `db:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at"`
+	DeletedAt        *time.Time     `db:"deleted_at"`
+	PlantName        string         `db:"plant_name"`
+	PlantSpecies     Species        `db:"plant_species"`
+	PlantGeneration  sql.NullString `db:"plant_generation"`
+	PollenParentName sql.NullString `db:"pollen_parent_name"`
+	VarietyID        sql.NullInt64  `db:"variety_id"`
+	VarietyName      sql.NullString `db:"variety_name"`
+	// GerminationRate is the percentage of seed that came up across the
+	// lot's germination trials, unset until one has been sown.
+	GerminationRate sql.NullFloat64 `db:"germination_rate"`
+}
+
+// IsSelfed reports whether the lot came from a self-pollinated flower.
+func (l SeedLot) IsSelfed() bool {
+	return l.PollenParentID.Valid && int(l.PollenParentID.Int64) == l.PlantID
+}
+
+// SeedLotGroup collects the lots of one variety for the inventory page.
+// VarietyID is unset for lots off plants outside the catalog, which are
+// grouped by plant name instead.
+type SeedLotGroup struct {
+	VarietyID  sql.NullInt64
+	Variety    string
+	Species    Species
+	TotalSeeds int
+	Lots       []SeedLot
+}
@@ -0,0 +1,147 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CommonSegregationRatios are the expected ratios offered on the analysis
+// page. Any other ratio can still be typed in.
+var CommonSegregationRatios = []string{
+	"3:1", "1:2:1", "1:1", "9:3:3:1", "1:1:1:1", "9:7", "15:1", "9:3:4", "12:3:1", "13:3",
+}
+
+// SegregationRatio is an expected Mendelian ratio such as 9:3:3:1.
+type SegregationRatio []float64
+
+func ParseSegregationRatio(s string) (SegregationRatio, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid segregation ratio value: %s", s)
+	}
+	ratio := make(SegregationRatio, 0, len(parts))
+	for _, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || f <= 0 {
+			return nil, fmt.Errorf("invalid segregation ratio value: %s", s)
+		}
+		ratio = append(ratio, f)
+	}
+	return ratio, nil
+}
+
+func (r SegregationRatio) String() string {
+	parts := make([]string, len(r))
+	for i, f := range r {
+		parts[i] = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return strings.Join(parts, ":")
+}
+
+// CrossFamily is the offspring of one parent pair. Reciprocal crosses are
+// grouped together, so ParentAID is always the lower plant ID. A selfed
+// family has the same plant on both sides.
+type CrossFamily struct {
+	ParentAID      int            `db:"parent_a_id"`
+	ParentBID      int            `db:"parent_b_id"`
+	ParentAName    string         `db:"parent_a_name"`
+	ParentBName    string         `db:"parent_b_name"`
+	Generation     sql.NullString `db:"generation"`
+	OffspringCount int            `db:"offspring_count"`
+	ScoredCount    int            `db:"scored_count"`
+}
+
+// Key identifies the family in URLs, e.g. "12-15".
+func (f CrossFamily) Key() string {
+	return fmt.Sprintf("%d-%d", f.ParentAID, f.ParentBID)
+}
+
+func (f CrossFamily) Label() string {
+	if f.ParentAID == f.ParentBID {
+		return f.ParentAName + " selfed"
+	}
+	return f.ParentAName + " x " + f.ParentBName
+}
+
+// ParseCrossFamilyKey reads a key produced by CrossFamily.Key.
+func ParseCrossFamilyKey(s string) (int, int, error) {
+	a, b, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid cross family value: %s", s)
+	}
+	parentA, errA := strconv.Atoi(a)
+	parentB, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return 0, 0, fmt.Errorf("invalid cross family value: %s", s)
+	}
+	if parentA > parentB {
+		parentA, parentB = parentB, parentA
+	}
+	return parentA, parentB, nil
+}
+
+// SegregationQuery selects a family, one or two categorical traits and the
+// ratio to test. SecondTraitID is 0 for single-trait analysis. Parts maps
+// each class label to the ratio part it is expected to take; when empty the
+// classes are only matched to the ratio by size and no test is run.
+type SegregationQuery struct {
+	ParentAID     int
+	ParentBID     int
+	TraitID       int
+	SecondTraitID int
+	Ratio         SegregationRatio
+	Parts         map[string]float64
+}
+
+// SegregationClass is one phenotype class with its observed count and the
+// count expected under the tested ratio.
+type SegregationClass struct {
+	Label    string
+	Observed int
+	Part     float64
+	Expected float64
+}
+
+// Contribution is the class's share of the chi-square statistic.
+func (c SegregationClass) Contribution() float64 {
+	if c.Expected <= 0 {
+		return 0
+	}
+	d := float64(c.Observed) - c.Expected
+	return d * d / c.Expected
+}
+
+// SegregationResult holds the class counts and, once each class has been
+// given its ratio part, the chi-square test. AssignedBySize marks a result
+// whose parts are only a suggestion and whose test was not run.
+type SegregationResult struct {
+	Family           CrossFamily
+	Traits           []string
+	Ratio            SegregationRatio
+	Classes          []SegregationClass
+	Total            int
+	Unscored         int
+	ChiSquare        float64
+	DegreesOfFreedom int
+	PValue           float64
+	AssignedBySize   bool
+}
+
+// Fits reports whether the observed counts are consistent with the ratio at
+// the 5% significance level.
+func (r SegregationResult) Fits() bool {
+	return r.PValue >= 0.05
+}
+
+// LowExpected reports whether any class expects fewer than five plants, in
+// which case the chi-square approximation is unreliable.
+func (r SegregationResult) LowExpected() bool {
+	for _, class := range r.Classes {
+		if class.Expected > 0 && class.Expected < 5 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package types
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SelectionDecision records one keep/cull decision on a plant, with the
+// reason and the trait scores that drove it. The latest decision is mirrored
+// in the plant's selection_status.
+type SelectionDecision struct {
+	ID            int             `db:"id"`
+	PlantID       int             `db:"plant_id"`
+	Status        SelectionStatus `db:"status"`
+	Reason        sql.NullString  `db:"reason"`
+	DecidedAt     time.Time       `db:"decided_at"`
+	CreatedAt     time.Time       `db:"created_at"`
+	TraitScoreIDs []int           `db:"-"`
+	Scores        []TraitScore    `db:"-"`
+}
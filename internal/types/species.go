@@ -0,0 +1,64 @@
+package types
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Taxon is an entry in the species table: a Capsicum species or an
+// interspecific hybrid of two of them.
+type Taxon struct {
+	ID          int            `db:"id"`
+	Name        Species        `db:"name"`
+	IsHybrid    bool           `db:"is_hybrid"`
+	ParentAID   sql.NullInt64  `db:"parent_a_id"`
+	ParentBID   sql.NullInt64  `db:"parent_b_id"`
+	Notes       sql.NullString `db:"notes"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+	DeletedAt   *time.Time     `db:"deleted_at"`
+	ParentAName sql.NullString `db:"parent_a_name"`
+	ParentBName sql.NullString `db:"parent_b_name"`
+	Synonyms    string         `db:"synonyms"`
+	PlantCount  int            `db:"plant_count"`
+}
+
+// hybridSeparator matches the cross sign between the parents of a hybrid,
+// typed as "x", "X" or "×".
+var hybridSeparator = regexp.MustCompile(`\s+[xX×]\s+`)
+
+// NormalizeSpeciesName tidies a typed species name: whitespace is collapsed
+// and an abbreviated genus ("C. chinense") is written out.
+func NormalizeSpeciesName(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if rest, ok := strings.CutPrefix(s, "C. "); ok {
+		s = "Capsicum " + rest
+	}
+	return s
+}
+
+// SplitHybrid splits a hybrid name such as "C. chinense x C. frutescens"
+// into its parent species.
+func SplitHybrid(s string) (string, string, bool) {
+	parts := hybridSeparator.Split(strings.TrimSpace(s), -1)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return NormalizeSpeciesName(parts[0]), NormalizeSpeciesName(parts[1]), true
+}
+
+// HybridName names a hybrid after its parents with the genus abbreviated,
+// e.g. "C. chinense x C. frutescens".
+func HybridName(parentA, parentB Species) Species {
+	return Species(ShortSpecies(parentA) + " x " + ShortSpecies(parentB))
+}
+
+// ShortSpecies abbreviates the genus, e.g. "Capsicum chinense" -> "C. chinense".
+func ShortSpecies(species Species) string {
+	if rest, ok := strings.CutPrefix(string(species), "Capsicum "); ok {
+		return "C. " + rest
+	}
+	return string(species)
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"database/sql"
+	"time"
+)
+
+// StageSource records how a stage change was entered.
+type StageSource string
+
+const (
+	StageSourceCreated     StageSource = "Created"
+	StageSourceEdit        StageSource = "Edit"
+	StageSourceAdvance     StageSource = "Advance"
+	StageSourceGermination StageSource = "Germination"
+	StageSourceLifecycle   StageSource = "Lifecycle"
+)
+
+// StageTransition is one change of a plant's growth stage. FromStage is
+// unset for the stage the plant was entered at.
+type StageTransition struct {
+	ID             int            `db:"id"`
+	PlantID        int            `db:"plant_id"`
+	FromStage      sql.NullString `db:"from_stage"`
+	ToStage        GrowthStage    `db:"to_stage"`
+	TransitionedOn time.Time      `db:"transitioned_on"`
+	Source         StageSource    `db:"source"`
+	Reason         sql.NullString `db:"reason"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+// StageDurations are days from sowing to each milestone. For a single plant
+// the counts are 0 or 1; for a variety the durations are averages over the
+// counted plants. Only observed transitions count, not the stage a plant
+// was entered at, and the first ripe pod is the first harvest.
+type StageDurations struct {
+	SeedToSeedling  sql.NullFloat64 `db:"seed_to_seedling"`
+	SeedlingPlants  int             `db:"seedling_plants"`
+	ToFirstFlower   sql.NullFloat64 `db:"to_first_flower"`
+	FloweringPlants int             `db:"flowering_plants"`
+	ToFirstRipePod  sql.NullFloat64 `db:"to_first_ripe_pod"`
+	RipePodPlants   int             `db:"ripe_pod_plants"`
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"database/sql"
+	"time"
+)
+
+// JournalEntryTypes are the journal entry types, and so the kinds of entry a
+// completed task can leave behind.
+var JournalEntryTypes = []string{"General", "Watering", "Fertilizing", "Pruning", "Problem", "Growth"}
+
+type TaskStatus string
+
+const (
+	TaskUpcoming TaskStatus = "Upcoming"
+	TaskDue      TaskStatus = "Due"
+	TaskOverdue  TaskStatus = "Overdue"
+	TaskDone     TaskStatus = "Done"
+)
+
+// Task is a job on one or more plants, either picked one by one or all the
+// plants of a project at the time the task is done. A recurring task moves
+// its due date on each time it is completed.
+type Task struct {
+	ID          int            `db:"id"`
+	Title       string         `db:"title"`
+	Description sql.NullString `db:"description"`
+	EntryType   string         `db:"entry_type"`
+	DueDate     time.Time      `db:"due_date"`
+	RRule       sql.NullString `db:"rrule"`
+	ProjectID   sql.NullInt64  `db:"project_id"`
+	Occurrences int            `db:"occurrences"`
+	CompletedAt sql.NullTime   `db:"completed_at"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+	DeletedAt   *time.Time     `db:"deleted_at"`
+	ProjectName sql.NullString `db:"project_name"`
+	PlantNames  sql.NullString `db:"plant_names"`
+	PlantCount  int            `db:"plant_count"`
+}
+
+// Status places the task relative to the given day.
+func (t Task) Status(asOf time.Time) TaskStatus {
+	if t.CompletedAt.Valid {
+		return TaskDone
+	}
+	due := t.DueDate.Format("2006-01-02")
+	today := asOf.Format("2006-01-02")
+	switch {
+	case due < today:
+		return TaskOverdue
+	case due == today:
+		return TaskDue
+	default:
+		return TaskUpcoming
+	}
+}
+
+// Recurrence parses the task's rule. The second return value is false for
+// a one-off task.
+func (t Task) Recurrence() (Recurrence, bool) {
+	if !t.RRule.Valid {
+		return Recurrence{}, false
+	}
+	r, err := ParseRecurrence(t.RRule.String)
+	return r, err == nil
+}
+
+// TaskCompletion records one time a task was done.
+type TaskCompletion struct {
+	ID          int            `db:"id"`
+	TaskID      int            `db:"task_id"`
+	DueDate     time.Time      `db:"due_date"`
+	CompletedOn time.Time      `db:"completed_on"`
+	Note        sql.NullString `db:"note"`
+	PlantCount  int            `db:"plant_count"`
+	CreatedAt   time.Time      `db:"created_at"`
+	TaskTitle   string         `db:"task_title"`
+}
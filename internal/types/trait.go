@@ -0,0 +1,137 @@
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type TraitGroup string
+
+const (
+	TraitGroupSeedling      TraitGroup = "Seedling"
+	TraitGroupPlant         TraitGroup = "Plant"
+	TraitGroupInflorescence TraitGroup = "Inflorescence"
+	TraitGroupFruit         TraitGroup = "Fruit"
+	TraitGroupSeed          TraitGroup = "Seed"
+)
+
+type TraitScale string
+
+const (
+	TraitScaleCategorical TraitScale = "Categorical"
+	TraitScaleNumeric     TraitScale = "Numeric"
+)
+
+// TraitDefinition is a descriptor plants can be scored against. Categorical
+// traits are scored with one of their States, numeric traits with a measured
+// value between MinValue and MaxValue.
+type TraitDefinition struct {
+	ID          int             `db:"id"`
+	Code        string          `db:"code"`
+	Name        string          `db:"name"`
+	TraitGroup  TraitGroup      `db:"trait_group"`
+	ScaleType   TraitScale      `db:"scale_type"`
+	Unit        sql.NullString  `db:"unit"`
+	MinValue    sql.NullFloat64 `db:"min_value"`
+	MaxValue    sql.NullFloat64 `db:"max_value"`
+	Description sql.NullString  `db:"description"`
+	SortOrder   int             `db:"sort_order"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+	DeletedAt   *time.Time      `db:"deleted_at"`
+	States      []TraitState    `db:"-"`
+}
+
+// TraitState is one value of a categorical scale, e.g. 8 = Red for fruit colour.
+type TraitState struct {
+	ID      int    `db:"id"`
+	TraitID int    `db:"trait_id"`
+	Value   int    `db:"value"`
+	Label   string `db:"label"`
+}
+
+// StateLabel returns the label of the given state value.
+func (t TraitDefinition) StateLabel(value int) (string, bool) {
+	for _, state := range t.States {
+		if state.Value == value {
+			return state.Label, true
+		}
+	}
+	return "", false
+}
+
+// TraitScore is one observation of a trait on a plant. Exactly one of
+// StateValue and NumericValue is set, depending on the trait's scale.
+type TraitScore struct {
+	ID           int             `db:"id"`
+	PlantID      int             `db:"plant_id"`
+	TraitID      int             `db:"trait_id"`
+	StateValue   sql.NullInt64   `db:"state_value"`
+	NumericValue sql.NullFloat64 `db:"numeric_value"`
+	ObservedDate time.Time       `db:"observed_date"`
+	Notes        sql.NullString  `db:"notes"`
+	CreatedAt    time.Time       `db:"created_at"`
+	UpdatedAt    time.Time       `db:"updated_at"`
+	DeletedAt    *time.Time      `db:"deleted_at"`
+	TraitCode    string          `db:"trait_code"`
+	TraitName    string          `db:"trait_name"`
+	Unit         sql.NullString  `db:"unit"`
+	StateLabel   sql.NullString  `db:"state_label"`
+}
+
+// Display formats the scored value for display.
+func (s TraitScore) Display() string {
+	switch {
+	case s.StateLabel.Valid:
+		return s.StateLabel.String
+	case s.StateValue.Valid:
+		return strconv.FormatInt(s.StateValue.Int64, 10)
+	case s.NumericValue.Valid && s.Unit.Valid:
+		return strconv.FormatFloat(s.NumericValue.Float64, 'f', -1, 64) + " " + s.Unit.String
+	case s.NumericValue.Valid:
+		return strconv.FormatFloat(s.NumericValue.Float64, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// FormValue is the raw value used to prefill the scoring form.
+func (s TraitScore) FormValue() string {
+	if s.StateValue.Valid {
+		return strconv.FormatInt(s.StateValue.Int64, 10)
+	}
+	if s.NumericValue.Valid {
+		return strconv.FormatFloat(s.NumericValue.Float64, 'f', -1, 64)
+	}
+	return ""
+}
+
+func ParseTraitGroup(s string) (TraitGroup, error) {
+	switch s {
+	case "Seedling":
+		return TraitGroupSeedling, nil
+	case "Plant":
+		return TraitGroupPlant, nil
+	case "Inflorescence":
+		return TraitGroupInflorescence, nil
+	case "Fruit":
+		return TraitGroupFruit, nil
+	case "Seed":
+		return TraitGroupSeed, nil
+	default:
+		return "", fmt.Errorf("invalid trait group value: %s", s)
+	}
+}
+
+func ParseTraitScale(s string) (TraitScale, error) {
+	switch s {
+	case "Categorical":
+		return TraitScaleCategorical, nil
+	case "Numeric":
+		return TraitScaleNumeric, nil
+	default:
+		return "", fmt.Errorf("invalid trait scale value: %s", s)
+	}
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Variety is a named cultivar that individual plants are grown from.
+type Variety struct {
+	ID                  int            `db:"id"`
+	Name                string         `db:"name"`
+	Species             sql.NullString `db:"species"`
+	Origin              sql.NullString `db:"origin"`
+	TypicalSHU          sql.NullInt64  `db:"typical_shu"`
+	DaysToMaturity      sql.NullInt64  `db:"days_to_maturity"`
+	PodDescription      sql.NullString `db:"pod_description"`
+	SeedSource          sql.NullString `db:"seed_source"`
+	WateringInterval    sql.NullInt64  `db:"watering_interval_days"`
+	FertilizingInterval sql.NullInt64  `db:"fertilizing_interval_days"`
+	CreatedAt           time.Time      `db:"created_at"`
+	UpdatedAt           time.Time      `db:"updated_at"`
+	DeletedAt           *time.Time     `db:"deleted_at"`
+	PlantCount          int            `db:"plant_count"`
+}
+
+// plantNumbering matches the suffix growers use to tell plants of one
+// variety apart: "#3", "No. 3", "(3)", "- 3", "plant 3" or a bare number.
+// Bare numbers and dashes need a space before them so that names such as
+// "Padron F1" and "Numex 6-4" are left alone.
+var plantNumbering = regexp.MustCompile(`(?i)(?:(?:^|\s+)(?:no\.?\s*\d+|[-–]\s*\d+|plant\s*#?\d+|\d+)|\s*(?:[#№]\s*\d+|\(\s*(?:plant|pot)?\s*#?\d+\s*\)))\s*$`)
+
+// VarietyNameFromPlant strips the plant numbering off a plant name, so
+// "Carolina Reaper #3" and "Carolina Reaper (2)" both give "Carolina Reaper".
+// Only one suffix is stripped, so "Trinidad 7 Pot 2" gives "Trinidad 7 Pot".
+// It returns an empty string when nothing but numbering is left.
+func VarietyNameFromPlant(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	return strings.TrimSpace(plantNumbering.ReplaceAllString(name, ""))
+}
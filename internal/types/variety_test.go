@@ -0,0 +1,35 @@
+package types
+
+import "testing"
+
+func TestVarietyNameFromPlant(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Carolina Reaper #3", want: "Carolina Reaper"},
+		{in: "Carolina Reaper (2)", want: "Carolina Reaper"},
+		{in: "Carolina Reaper (plant 2)", want: "Carolina Reaper"},
+		{in: "Carolina Reaper No. 4", want: "Carolina Reaper"},
+		{in: "Carolina Reaper - 5", want: "Carolina Reaper"},
+		{in: "Carolina Reaper plant 6", want: "Carolina Reaper"},
+		{in: "Carolina  Reaper 7 ", want: "Carolina Reaper"},
+		{in: "Carolina Reaper#8", want: "Carolina Reaper"},
+		{in: "Carolina Reaper", want: "Carolina Reaper"},
+		{in: "Jalapeno 3", want: "Jalapeno"},
+		{in: "Padron F1", want: "Padron F1"},
+		{in: "Numex 6-4", want: "Numex 6-4"},
+		{in: "Trinidad 7 Pot", want: "Trinidad 7 Pot"},
+		{in: "Trinidad 7 Pot 2", want: "Trinidad 7 Pot"},
+		{in: "Aji 7 #2", want: "Aji 7"},
+		{in: "#3", want: ""},
+		{in: "12", want: ""},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := VarietyNameFromPlant(tt.in); got != tt.want {
+			t.Errorf("VarietyNameFromPlant(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
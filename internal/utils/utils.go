@@ -22,3 +22,17 @@ func GetRequiredEnvAsInt(key string) int {
 	}
 	return intValue
 }
+
+// GetEnvAsInt reads an optional integer setting, falling back when it is
+// unset.
+func GetEnvAsInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Environment variable %s must be a valid integer: %v", key, err)
+	}
+	return intValue
+}
@@ -1,3 +1,7 @@
+-- Creates the database from scratch. A database created from an older copy
+-- of this script is brought up to date with the scripts in upgrades/, run in
+-- file name order; each one is safe to run again.
+
 CREATE SEQUENCE IF NOT EXISTS journal_entries_id_seq;
 
 -- Table Definition
@@ -26,7 +30,7 @@ CREATE TABLE "public"."plants" (
     "name" varchar(100) NOT NULL,
     "species" varchar(100),
     "health" varchar(20) CHECK ((health)::text = ANY ((ARRAY['Excellent'::character varying, 'Good'::character varying, 'Fair'::character varying, 'Poor'::character varying])::text[])),
-    "growth_stage" varchar(20) CHECK ((growth_stage)::text = ANY (ARRAY[('Seed'::character varying)::text, ('Seedling'::character varying)::text, ('Vegetative'::character varying)::text, ('Flowering'::character varying)::text, ('Fruiting'::character varying)::text])),
+    "growth_stage" varchar(20) CHECK ((growth_stage)::text = ANY (ARRAY[('Seed'::character varying)::text, ('Seedling'::character varying)::text, ('Vegetative'::character varying)::text, ('Flowering'::character varying)::text, ('Fruiting'::character varying)::text, ('Overwintering'::character varying)::text, ('Dormant'::character varying)::text, ('Dead'::character varying)::text, ('Given Away'::character varying)::text])),
     "planting_date" date NOT NULL,
     "image_path" varchar(255),
     "notes" text,
@@ -37,12 +41,550 @@ CREATE TABLE "public"."plants" (
     "last_watered_at" timestamp,
     "is_cross" bool DEFAULT false,
     "generation" varchar(50),
-    "is_harvested" bool NOT NULL DEFAULT false,
-    "harvested_at" timestamptz,
+    "season_finished" bool NOT NULL DEFAULT false,
+    "season_finished_at" timestamptz,
+    "seed_parent_id" int4,
+    "pollen_parent_id" int4,
+    "seed_parent_external" varchar(100),
+    "pollen_parent_external" varchar(100),
+    "seed_lot_id" int4,
+    "selection_status" varchar(20) NOT NULL DEFAULT 'Undecided' CHECK ((selection_status)::text = ANY ((ARRAY['Undecided'::character varying, 'Keep'::character varying, 'Cull'::character varying])::text[])),
+    "variety_id" int4,
+    "germination_trial_id" int4,
+    "watering_interval_days" int4 CHECK (watering_interval_days >= 0),
+    "fertilizing_interval_days" int4 CHECK (fertilizing_interval_days >= 0),
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS seed_lots_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."seed_lots" (
+    "id" int4 NOT NULL DEFAULT nextval('seed_lots_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "pollen_parent_id" int4,
+    "pollen_parent_external" varchar(100),
+    "seed_count" int4 NOT NULL DEFAULT 0 CHECK (seed_count >= 0),
+    "harvest_date" date NOT NULL,
+    "storage_location" varchar(100),
+    "germination_notes" text,
+    "harvest_id" int4,
+    "pollination_id" int4,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS harvests_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."harvests" (
+    "id" int4 NOT NULL DEFAULT nextval('harvests_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "harvest_date" date NOT NULL,
+    "pod_count" int4 NOT NULL DEFAULT 0 CHECK (pod_count >= 0),
+    "total_weight_grams" numeric(10,2) CHECK (total_weight_grams >= 0),
+    "quality_grade" varchar(10) NOT NULL DEFAULT 'A' CHECK ((quality_grade)::text = ANY ((ARRAY['A'::character varying, 'B'::character varying, 'C'::character varying, 'Cull'::character varying])::text[])),
+    "image_path" varchar(255),
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS pollinations_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."pollinations" (
+    "id" int4 NOT NULL DEFAULT nextval('pollinations_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "donor_plant_id" int4,
+    "donor_external" varchar(100),
+    "pollination_date" date NOT NULL,
+    "flower_tag" varchar(50),
+    "emasculated" bool NOT NULL DEFAULT false,
+    "isolation_method" varchar(20) NOT NULL DEFAULT 'Bagged' CHECK ((isolation_method)::text = ANY ((ARRAY['Bagged'::character varying, 'Caged'::character varying, 'Clipped'::character varying, 'None'::character varying])::text[])),
+    "outcome" varchar(20) NOT NULL DEFAULT 'Pending' CHECK ((outcome)::text = ANY ((ARRAY['Pending'::character varying, 'Set'::character varying, 'Dropped'::character varying])::text[])),
+    "outcome_date" date,
+    "notes" text,
+    "planned_cross_id" int4,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS trait_definitions_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."trait_definitions" (
+    "id" int4 NOT NULL DEFAULT nextval('trait_definitions_id_seq'::regclass),
+    "code" varchar(50) NOT NULL UNIQUE,
+    "name" varchar(100) NOT NULL,
+    "trait_group" varchar(20) NOT NULL DEFAULT 'Plant' CHECK ((trait_group)::text = ANY ((ARRAY['Seedling'::character varying, 'Plant'::character varying, 'Inflorescence'::character varying, 'Fruit'::character varying, 'Seed'::character varying])::text[])),
+    "scale_type" varchar(20) NOT NULL CHECK ((scale_type)::text = ANY ((ARRAY['Categorical'::character varying, 'Numeric'::character varying])::text[])),
+    "unit" varchar(20),
+    "min_value" numeric(10,2),
+    "max_value" numeric(10,2),
+    "description" text,
+    "sort_order" int4 NOT NULL DEFAULT 0,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS trait_states_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."trait_states" (
+    "id" int4 NOT NULL DEFAULT nextval('trait_states_id_seq'::regclass),
+    "trait_id" int4 NOT NULL,
+    "value" int4 NOT NULL,
+    "label" varchar(100) NOT NULL,
+    PRIMARY KEY ("id"),
+    UNIQUE ("trait_id", "value")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS trait_scores_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."trait_scores" (
+    "id" int4 NOT NULL DEFAULT nextval('trait_scores_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "trait_id" int4 NOT NULL,
+    "state_value" int4,
+    "numeric_value" numeric(10,2),
+    "observed_date" date NOT NULL DEFAULT CURRENT_DATE,
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id"),
+    CHECK ((state_value IS NULL) <> (numeric_value IS NULL))
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS breeding_projects_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."breeding_projects" (
+    "id" int4 NOT NULL DEFAULT nextval('breeding_projects_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "goal" text,
+    "status" varchar(20) NOT NULL DEFAULT 'Active' CHECK ((status)::text = ANY ((ARRAY['Planning'::character varying, 'Active'::character varying, 'Paused'::character varying, 'Completed'::character varying, 'Abandoned'::character varying])::text[])),
+    "started_at" date NOT NULL DEFAULT CURRENT_DATE,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Table Definition
+CREATE TABLE "public"."project_plants" (
+    "project_id" int4 NOT NULL,
+    "plant_id" int4 NOT NULL,
+    "added_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("project_id", "plant_id")
+);
+
+-- Table Definition
+CREATE TABLE "public"."project_target_traits" (
+    "project_id" int4 NOT NULL,
+    "trait_id" int4 NOT NULL,
+    "state_value" int4,
+    "min_value" numeric(10,2),
+    "max_value" numeric(10,2),
+    "notes" text,
+    PRIMARY KEY ("project_id", "trait_id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS selection_decisions_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."selection_decisions" (
+    "id" int4 NOT NULL DEFAULT nextval('selection_decisions_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "status" varchar(20) NOT NULL CHECK ((status)::text = ANY ((ARRAY['Undecided'::character varying, 'Keep'::character varying, 'Cull'::character varying])::text[])),
+    "reason" text,
+    "decided_at" date NOT NULL DEFAULT CURRENT_DATE,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+-- Table Definition
+CREATE TABLE "public"."selection_decision_scores" (
+    "decision_id" int4 NOT NULL,
+    "trait_score_id" int4 NOT NULL,
+    PRIMARY KEY ("decision_id", "trait_score_id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS planned_crosses_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."planned_crosses" (
+    "id" int4 NOT NULL DEFAULT nextval('planned_crosses_id_seq'::regclass),
+    "seed_parent_id" int4 NOT NULL,
+    "pollen_parent_id" int4 NOT NULL,
+    "project_id" int4,
+    "status" varchar(20) NOT NULL DEFAULT 'Planned' CHECK ((status)::text = ANY ((ARRAY['Planned'::character varying, 'Attempted'::character varying, 'Completed'::character varying, 'Abandoned'::character varying])::text[])),
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS plant_genotypes_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."plant_genotypes" (
+    "id" int4 NOT NULL DEFAULT nextval('plant_genotypes_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "locus" varchar(20) NOT NULL,
+    "genotype" varchar(10) NOT NULL CHECK ((genotype)::text = ANY ((ARRAY['AA'::character varying, 'Aa'::character varying, 'aa'::character varying, 'Unknown'::character varying])::text[])),
+    "source" varchar(20) NOT NULL CHECK ((source)::text = ANY ((ARRAY['Test Cross'::character varying, 'Progeny'::character varying, 'Marker'::character varying])::text[])),
+    "called_at" date NOT NULL DEFAULT CURRENT_DATE,
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS heat_tests_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."heat_tests" (
+    "id" int4 NOT NULL DEFAULT nextval('heat_tests_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "harvest_id" int4,
+    "tested_at" date NOT NULL DEFAULT CURRENT_DATE,
+    "panel_score" int4 NOT NULL CHECK ((panel_score >= 0) AND (panel_score <= 10)),
+    "shu" int4 CHECK (shu >= 0),
+    "capsaicinoids_mg_g" numeric(10,3) CHECK (capsaicinoids_mg_g >= 0),
+    "tester" varchar(100),
+    "ripeness" varchar(20) NOT NULL DEFAULT 'Ripe' CHECK ((ripeness)::text = ANY ((ARRAY['Green'::character varying, 'Breaker'::character varying, 'Ripe'::character varying, 'Overripe'::character varying])::text[])),
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS varieties_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."varieties" (
+    "id" int4 NOT NULL DEFAULT nextval('varieties_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "species" varchar(100),
+    "origin" varchar(100),
+    "typical_shu" int4 CHECK (typical_shu >= 0),
+    "days_to_maturity" int4 CHECK (days_to_maturity > 0),
+    "pod_description" text,
+    "seed_source" varchar(255),
+    "watering_interval_days" int4 CHECK (watering_interval_days >= 0),
+    "fertilizing_interval_days" int4 CHECK (fertilizing_interval_days >= 0),
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS species_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."species" (
+    "id" int4 NOT NULL DEFAULT nextval('species_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "is_hybrid" bool NOT NULL DEFAULT false,
+    "parent_a_id" int4,
+    "parent_b_id" int4,
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id"),
+    CHECK (NOT is_hybrid OR (parent_a_id IS NOT NULL AND parent_b_id IS NOT NULL))
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS species_synonyms_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."species_synonyms" (
+    "id" int4 NOT NULL DEFAULT nextval('species_synonyms_id_seq'::regclass),
+    "species_id" int4 NOT NULL,
+    "synonym" varchar(100) NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS germination_trials_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."germination_trials" (
+    "id" int4 NOT NULL DEFAULT nextval('germination_trials_id_seq'::regclass),
+    "seed_lot_id" int4,
+    "variety_id" int4,
+    "seeds_sown" int4 NOT NULL CHECK (seeds_sown > 0),
+    "method" varchar(20) NOT NULL CHECK ((method)::text = ANY ((ARRAY['Paper Towel'::character varying, 'Cell Tray'::character varying, 'Rockwool'::character varying, 'Direct Sow'::character varying])::text[])),
+    "temperature_c" numeric(4,1),
+    "sow_date" date NOT NULL DEFAULT CURRENT_DATE,
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id"),
+    CHECK (seed_lot_id IS NOT NULL OR variety_id IS NOT NULL)
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS germination_counts_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."germination_counts" (
+    "id" int4 NOT NULL DEFAULT nextval('germination_counts_id_seq'::regclass),
+    "trial_id" int4 NOT NULL,
+    "counted_on" date NOT NULL,
+    "germinated" int4 NOT NULL CHECK (germinated >= 0),
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS plant_stage_transitions_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."plant_stage_transitions" (
+    "id" int4 NOT NULL DEFAULT nextval('plant_stage_transitions_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "from_stage" varchar(20),
+    "to_stage" varchar(20) NOT NULL CHECK ((to_stage)::text = ANY (ARRAY[('Seed'::character varying)::text, ('Seedling'::character varying)::text, ('Vegetative'::character varying)::text, ('Flowering'::character varying)::text, ('Fruiting'::character varying)::text, ('Overwintering'::character varying)::text, ('Dormant'::character varying)::text, ('Dead'::character varying)::text, ('Given Away'::character varying)::text])),
+    "transitioned_on" date NOT NULL DEFAULT CURRENT_DATE,
+    "source" varchar(20) NOT NULL CHECK ((source)::text = ANY ((ARRAY['Created'::character varying, 'Edit'::character varying, 'Advance'::character varying, 'Germination'::character varying, 'Lifecycle'::character varying])::text[])),
+    "reason" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS plant_health_changes_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."plant_health_changes" (
+    "id" int4 NOT NULL DEFAULT nextval('plant_health_changes_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "from_health" varchar(20),
+    "to_health" varchar(20) NOT NULL CHECK ((to_health)::text = ANY ((ARRAY['Excellent'::character varying, 'Good'::character varying, 'Fair'::character varying, 'Poor'::character varying])::text[])),
+    "changed_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "journal_entry_id" int4,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS health_alerts_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."health_alerts" (
+    "id" int4 NOT NULL DEFAULT nextval('health_alerts_id_seq'::regclass),
+    "plant_id" int4 NOT NULL,
+    "peak_health" varchar(20) NOT NULL,
+    "health" varchar(20) NOT NULL,
+    "window_days" int4 NOT NULL,
+    "detected_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "acknowledged_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS tasks_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."tasks" (
+    "id" int4 NOT NULL DEFAULT nextval('tasks_id_seq'::regclass),
+    "title" varchar(200) NOT NULL,
+    "description" text,
+    "entry_type" varchar(50) NOT NULL DEFAULT 'General'::character varying,
+    "due_date" date NOT NULL,
+    "rrule" varchar(255),
+    "project_id" int4,
+    "occurrences" int4 NOT NULL DEFAULT 0,
+    "completed_at" timestamptz,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+-- Table Definition
+CREATE TABLE "public"."task_plants" (
+    "task_id" int4 NOT NULL,
+    "plant_id" int4 NOT NULL,
+    PRIMARY KEY ("task_id", "plant_id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS task_completions_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."task_completions" (
+    "id" int4 NOT NULL DEFAULT nextval('task_completions_id_seq'::regclass),
+    "task_id" int4 NOT NULL,
+    "due_date" date NOT NULL,
+    "completed_on" date NOT NULL,
+    "note" text,
+    "plant_count" int4 NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS users_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."users" (
+    "id" int4 NOT NULL DEFAULT nextval('users_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "quiet_start" time,
+    "quiet_end" time,
+    "timezone" varchar(64) NOT NULL DEFAULT 'UTC'::character varying,
+    "digest_frequency" varchar(10) NOT NULL DEFAULT 'Off'::character varying CHECK ((digest_frequency)::text = ANY ((ARRAY['Off'::character varying, 'Daily'::character varying, 'Weekly'::character varying])::text[])),
+    "digest_sections" text[] NOT NULL DEFAULT '{}'::text[],
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id"),
+    CHECK ((quiet_start IS NULL) = (quiet_end IS NULL))
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS notification_channels_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."notification_channels" (
+    "id" int4 NOT NULL DEFAULT nextval('notification_channels_id_seq'::regclass),
+    "user_id" int4 NOT NULL,
+    "kind" varchar(20) NOT NULL CHECK ((kind)::text = ANY ((ARRAY['SMTP'::character varying, 'Ntfy'::character varying, 'Gotify'::character varying, 'Webhook'::character varying])::text[])),
+    "target" varchar(500) NOT NULL,
+    "token" varchar(255),
+    "events" text[] NOT NULL DEFAULT '{}'::text[],
+    "enabled" bool NOT NULL DEFAULT true,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS notification_deliveries_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."notification_deliveries" (
+    "id" int4 NOT NULL DEFAULT nextval('notification_deliveries_id_seq'::regclass),
+    "channel_id" int4 NOT NULL,
+    "event" varchar(30) NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "body" text NOT NULL,
+    "html" text,
+    "url" varchar(500),
+    "attempts" int4 NOT NULL DEFAULT 0,
+    "last_error" text,
+    "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "sent_at" timestamptz,
+    "claimed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+-- Table Definition
+CREATE TABLE "public"."scheduled_jobs" (
+    "name" varchar(50) NOT NULL,
+    "description" varchar(255) NOT NULL DEFAULT ''::character varying,
+    "schedule" varchar(100) NOT NULL,
+    "enabled" bool NOT NULL DEFAULT true,
+    "last_run_at" timestamptz,
+    "next_run_at" timestamptz,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("name")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS job_runs_id_seq;
+
+-- Table Definition
+CREATE TABLE "public"."job_runs" (
+    "id" int4 NOT NULL DEFAULT nextval('job_runs_id_seq'::regclass),
+    "job_name" varchar(50) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'Running'::character varying CHECK ((status)::text = ANY ((ARRAY['Running'::character varying, 'Succeeded'::character varying, 'Failed'::character varying])::text[])),
+    "message" text,
+    "host" varchar(255) NOT NULL,
+    "started_at" timestamptz NOT NULL,
+    "finished_at" timestamptz,
     PRIMARY KEY ("id")
 );
 
 ALTER TABLE "public"."journal_entries" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."plants" ADD FOREIGN KEY ("seed_parent_id") REFERENCES "public"."plants"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."plants" ADD FOREIGN KEY ("pollen_parent_id") REFERENCES "public"."plants"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."plants" ADD FOREIGN KEY ("seed_lot_id") REFERENCES "public"."seed_lots"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."seed_lots" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."seed_lots" ADD FOREIGN KEY ("pollen_parent_id") REFERENCES "public"."plants"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."seed_lots" ADD FOREIGN KEY ("harvest_id") REFERENCES "public"."harvests"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."harvests" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."seed_lots" ADD FOREIGN KEY ("pollination_id") REFERENCES "public"."pollinations"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."pollinations" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."pollinations" ADD FOREIGN KEY ("donor_plant_id") REFERENCES "public"."plants"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."trait_states" ADD FOREIGN KEY ("trait_id") REFERENCES "public"."trait_definitions"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."trait_scores" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."trait_scores" ADD FOREIGN KEY ("trait_id") REFERENCES "public"."trait_definitions"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."project_plants" ADD FOREIGN KEY ("project_id") REFERENCES "public"."breeding_projects"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."project_plants" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."project_target_traits" ADD FOREIGN KEY ("project_id") REFERENCES "public"."breeding_projects"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."selection_decisions" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."selection_decision_scores" ADD FOREIGN KEY ("decision_id") REFERENCES "public"."selection_decisions"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."selection_decision_scores" ADD FOREIGN KEY ("trait_score_id") REFERENCES "public"."trait_scores"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."project_target_traits" ADD FOREIGN KEY ("trait_id") REFERENCES "public"."trait_definitions"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."planned_crosses" ADD FOREIGN KEY ("seed_parent_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."planned_crosses" ADD FOREIGN KEY ("pollen_parent_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."planned_crosses" ADD FOREIGN KEY ("project_id") REFERENCES "public"."breeding_projects"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."plant_genotypes" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."heat_tests" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."heat_tests" ADD FOREIGN KEY ("harvest_id") REFERENCES "public"."harvests"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."plants" ADD FOREIGN KEY ("variety_id") REFERENCES "public"."varieties"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."species" ADD FOREIGN KEY ("parent_a_id") REFERENCES "public"."species"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."species" ADD FOREIGN KEY ("parent_b_id") REFERENCES "public"."species"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."species_synonyms" ADD FOREIGN KEY ("species_id") REFERENCES "public"."species"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."germination_trials" ADD FOREIGN KEY ("seed_lot_id") REFERENCES "public"."seed_lots"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."germination_trials" ADD FOREIGN KEY ("variety_id") REFERENCES "public"."varieties"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."germination_counts" ADD FOREIGN KEY ("trial_id") REFERENCES "public"."germination_trials"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."plants" ADD FOREIGN KEY ("germination_trial_id") REFERENCES "public"."germination_trials"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."plant_stage_transitions" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."plant_health_changes" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."plant_health_changes" ADD FOREIGN KEY ("journal_entry_id") REFERENCES "public"."journal_entries"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."health_alerts" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."tasks" ADD FOREIGN KEY ("project_id") REFERENCES "public"."breeding_projects"("id") ON DELETE SET NULL;
+ALTER TABLE "public"."task_plants" ADD FOREIGN KEY ("task_id") REFERENCES "public"."tasks"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."task_plants" ADD FOREIGN KEY ("plant_id") REFERENCES "public"."plants"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."task_completions" ADD FOREIGN KEY ("task_id") REFERENCES "public"."tasks"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."notification_channels" ADD FOREIGN KEY ("user_id") REFERENCES "public"."users"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."notification_deliveries" ADD FOREIGN KEY ("channel_id") REFERENCES "public"."notification_channels"("id") ON DELETE CASCADE;
+ALTER TABLE "public"."job_runs" ADD FOREIGN KEY ("job_name") REFERENCES "public"."scheduled_jobs"("name") ON DELETE CASCADE;
+ALTER TABLE "public"."pollinations" ADD FOREIGN KEY ("planned_cross_id") REFERENCES "public"."planned_crosses"("id") ON DELETE SET NULL;
 
 
 -- Indices
@@ -54,3 +596,166 @@ CREATE INDEX idx_journal_created_at ON public.journal_entries USING btree (creat
 CREATE INDEX idx_plants_planting_date ON public.plants USING btree (planting_date);
 CREATE INDEX idx_plants_health ON public.plants USING btree (health);
 CREATE INDEX idx_plants_growth_stage ON public.plants USING btree (growth_stage);
+CREATE INDEX idx_plants_seed_parent_id ON public.plants USING btree (seed_parent_id);
+CREATE INDEX idx_plants_pollen_parent_id ON public.plants USING btree (pollen_parent_id);
+
+
+-- Indices
+CREATE INDEX idx_seed_lots_plant_id ON public.seed_lots USING btree (plant_id);
+CREATE UNIQUE INDEX idx_seed_lots_pollination_id ON public.seed_lots USING btree (pollination_id) WHERE (deleted_at IS NULL);
+CREATE INDEX idx_harvests_plant_id ON public.harvests USING btree (plant_id);
+CREATE INDEX idx_harvests_harvest_date ON public.harvests USING btree (harvest_date);
+CREATE INDEX idx_pollinations_plant_id ON public.pollinations USING btree (plant_id);
+CREATE INDEX idx_pollinations_donor_plant_id ON public.pollinations USING btree (donor_plant_id);
+CREATE INDEX idx_trait_scores_plant_id ON public.trait_scores USING btree (plant_id);
+CREATE INDEX idx_trait_scores_trait_id ON public.trait_scores USING btree (trait_id);
+CREATE INDEX idx_project_plants_plant_id ON public.project_plants USING btree (plant_id);
+CREATE INDEX idx_selection_decisions_plant_id ON public.selection_decisions USING btree (plant_id);
+CREATE INDEX idx_pollinations_planned_cross_id ON public.pollinations USING btree (planned_cross_id);
+CREATE INDEX idx_plant_genotypes_plant_id ON public.plant_genotypes USING btree (plant_id);
+CREATE INDEX idx_heat_tests_plant_id ON public.heat_tests USING btree (plant_id);
+CREATE INDEX idx_plants_variety_id ON public.plants USING btree (variety_id);
+CREATE UNIQUE INDEX idx_varieties_name ON public.varieties USING btree (lower((name)::text)) WHERE (deleted_at IS NULL);
+CREATE UNIQUE INDEX idx_species_name ON public.species USING btree (lower((name)::text)) WHERE (deleted_at IS NULL);
+CREATE UNIQUE INDEX idx_species_synonyms_synonym ON public.species_synonyms USING btree (lower((synonym)::text));
+CREATE INDEX idx_plants_species ON public.plants USING btree (species);
+CREATE INDEX idx_germination_trials_seed_lot_id ON public.germination_trials USING btree (seed_lot_id);
+CREATE UNIQUE INDEX idx_germination_counts_trial_day ON public.germination_counts USING btree (trial_id, counted_on);
+CREATE INDEX idx_plants_germination_trial_id ON public.plants USING btree (germination_trial_id);
+CREATE INDEX idx_plant_stage_transitions_plant_id ON public.plant_stage_transitions USING btree (plant_id, transitioned_on);
+CREATE INDEX idx_plant_health_changes_plant_id ON public.plant_health_changes USING btree (plant_id, changed_at);
+CREATE UNIQUE INDEX idx_health_alerts_open ON public.health_alerts USING btree (plant_id) WHERE (acknowledged_at IS NULL);
+CREATE INDEX idx_tasks_due_date ON public.tasks USING btree (due_date) WHERE (completed_at IS NULL AND deleted_at IS NULL);
+CREATE INDEX idx_task_plants_plant_id ON public.task_plants USING btree (plant_id);
+CREATE INDEX idx_task_completions_task_id ON public.task_completions USING btree (task_id);
+CREATE INDEX idx_notification_channels_user_id ON public.notification_channels USING btree (user_id);
+CREATE INDEX idx_notification_deliveries_pending ON public.notification_deliveries USING btree (created_at) WHERE (sent_at IS NULL);
+CREATE INDEX idx_job_runs_job_name_started_at ON public.job_runs USING btree (job_name, started_at DESC);
+
+
+-- Trait definitions from the IPGRI Descriptors for Capsicum (1995)
+INSERT INTO "public"."trait_definitions" ("code", "name", "trait_group", "scale_type", "unit", "min_value", "max_value", "description", "sort_order") VALUES
+('hypocotyl_color', 'Hypocotyl colour', 'Seedling', 'Categorical', NULL, NULL, NULL, 'Recorded on seedlings when the cotyledons are fully expanded', 10),
+('stem_color', 'Stem colour', 'Plant', 'Categorical', NULL, NULL, NULL, 'Recorded on young plants before transplanting', 20),
+('plant_growth_habit', 'Plant growth habit', 'Plant', 'Categorical', NULL, NULL, NULL, 'Recorded when the first fruits ripen', 30),
+('plant_height', 'Plant height', 'Plant', 'Numeric', 'cm', 0, 300, 'Recorded when the first fruits ripen', 40),
+('leaf_pubescence', 'Leaf pubescence', 'Plant', 'Categorical', NULL, NULL, NULL, 'Recorded on young leaves of mature plants', 50),
+('days_to_flowering', 'Days to flowering', 'Inflorescence', 'Numeric', 'days', 0, 365, 'Days from sowing until the first open flower', 60),
+('flowers_per_axil', 'Number of flowers per axil', 'Inflorescence', 'Categorical', NULL, NULL, NULL, NULL, 70),
+('corolla_color', 'Corolla colour', 'Inflorescence', 'Categorical', NULL, NULL, NULL, 'Recorded on fully opened flowers', 80),
+('anther_color', 'Anther colour', 'Inflorescence', 'Categorical', NULL, NULL, NULL, 'Recorded immediately after the flower opens, before anthesis', 90),
+('calyx_margin', 'Calyx margin', 'Inflorescence', 'Categorical', NULL, NULL, NULL, NULL, 100),
+('days_to_fruiting', 'Days to fruiting', 'Fruit', 'Numeric', 'days', 0, 365, 'Days from sowing until the first mature fruit', 110),
+('fruit_position', 'Fruit position', 'Fruit', 'Categorical', NULL, NULL, NULL, NULL, 120),
+('fruit_color_mature', 'Fruit colour at mature stage', 'Fruit', 'Categorical', NULL, NULL, NULL, 'Recorded on fully ripe fruit', 130),
+('fruit_shape', 'Fruit shape', 'Fruit', 'Categorical', NULL, NULL, NULL, NULL, 140),
+('fruit_length', 'Fruit length', 'Fruit', 'Numeric', 'cm', 0, 50, 'Average of ten ripe fruits', 150),
+('fruit_width', 'Fruit width', 'Fruit', 'Numeric', 'cm', 0, 20, 'Measured at the widest point, average of ten ripe fruits', 160),
+('fruit_surface', 'Fruit surface', 'Fruit', 'Categorical', NULL, NULL, NULL, NULL, 170),
+('placenta_capsaicin', 'Capsaicin in placenta', 'Fruit', 'Categorical', NULL, NULL, NULL, 'Whether the placenta of a ripe fruit is pungent', 175),
+('seed_color', 'Seed colour', 'Seed', 'Categorical', NULL, NULL, NULL, NULL, 180);
+
+INSERT INTO "public"."trait_states" ("trait_id", "value", "label")
+SELECT t.id, v.value, v.label
+FROM "public"."trait_definitions" t
+JOIN (VALUES
+    ('hypocotyl_color', 1, 'White'),
+    ('hypocotyl_color', 2, 'Green'),
+    ('hypocotyl_color', 3, 'Purple'),
+    ('stem_color', 1, 'Green'),
+    ('stem_color', 2, 'Green with purple stripes'),
+    ('stem_color', 3, 'Purple'),
+    ('stem_color', 4, 'Other'),
+    ('plant_growth_habit', 3, 'Prostrate'),
+    ('plant_growth_habit', 5, 'Intermediate (compact)'),
+    ('plant_growth_habit', 7, 'Erect'),
+    ('plant_growth_habit', 9, 'Other'),
+    ('leaf_pubescence', 3, 'Sparse'),
+    ('leaf_pubescence', 5, 'Intermediate'),
+    ('leaf_pubescence', 7, 'Dense'),
+    ('flowers_per_axil', 1, 'One'),
+    ('flowers_per_axil', 2, 'Two'),
+    ('flowers_per_axil', 3, 'Three or more'),
+    ('flowers_per_axil', 4, 'Many in bunches, each in its own axil'),
+    ('corolla_color', 1, 'White'),
+    ('corolla_color', 2, 'Light yellow'),
+    ('corolla_color', 3, 'Yellow'),
+    ('corolla_color', 4, 'Yellow-green'),
+    ('corolla_color', 5, 'Purple with white base'),
+    ('corolla_color', 6, 'White with purple base'),
+    ('corolla_color', 7, 'White with purple margin'),
+    ('corolla_color', 8, 'Purple'),
+    ('corolla_color', 9, 'Other'),
+    ('anther_color', 1, 'White'),
+    ('anther_color', 2, 'Yellow'),
+    ('anther_color', 3, 'Pale blue'),
+    ('anther_color', 4, 'Blue'),
+    ('anther_color', 5, 'Purple'),
+    ('anther_color', 6, 'Other'),
+    ('calyx_margin', 1, 'Entire'),
+    ('calyx_margin', 2, 'Intermediate'),
+    ('calyx_margin', 3, 'Dentate'),
+    ('calyx_margin', 4, 'Other'),
+    ('fruit_position', 3, 'Pendant'),
+    ('fruit_position', 5, 'Intermediate'),
+    ('fruit_position', 7, 'Erect'),
+    ('fruit_color_mature', 1, 'White'),
+    ('fruit_color_mature', 2, 'Lemon-yellow'),
+    ('fruit_color_mature', 3, 'Pale orange-yellow'),
+    ('fruit_color_mature', 4, 'Orange-yellow'),
+    ('fruit_color_mature', 5, 'Pale orange'),
+    ('fruit_color_mature', 6, 'Orange'),
+    ('fruit_color_mature', 7, 'Light red'),
+    ('fruit_color_mature', 8, 'Red'),
+    ('fruit_color_mature', 9, 'Dark red'),
+    ('fruit_color_mature', 10, 'Purple'),
+    ('fruit_color_mature', 11, 'Brown'),
+    ('fruit_color_mature', 12, 'Black'),
+    ('fruit_color_mature', 13, 'Other'),
+    ('fruit_shape', 1, 'Elongate'),
+    ('fruit_shape', 2, 'Almost round'),
+    ('fruit_shape', 3, 'Triangular'),
+    ('fruit_shape', 4, 'Campanulate'),
+    ('fruit_shape', 5, 'Blocky'),
+    ('fruit_shape', 6, 'Other'),
+    ('fruit_surface', 1, 'Smooth'),
+    ('fruit_surface', 2, 'Semiwrinkled'),
+    ('fruit_surface', 3, 'Wrinkled'),
+    ('placenta_capsaicin', 0, 'Absent'),
+    ('placenta_capsaicin', 1, 'Present'),
+    ('seed_color', 1, 'Straw'),
+    ('seed_color', 2, 'Brown'),
+    ('seed_color', 3, 'Black'),
+    ('seed_color', 4, 'Other')
+) AS v(code, value, label) ON t.code = v.code;
+
+
+-- Capsicum species, replacing the list that used to be hardcoded in the app
+INSERT INTO "public"."species" ("name") VALUES
+('Capsicum annuum'),
+('Capsicum chinense'),
+('Capsicum baccatum'),
+('Capsicum frutescens'),
+('Capsicum pubescens'),
+('Capsicum rhomboideum'),
+('Capsicum praetermissum'),
+('Capsicum cardenasii'),
+('Capsicum eximium'),
+('Capsicum galapagoense'),
+('Capsicum tovarii'),
+('Capsicum flexuosum'),
+('Capsicum exile');
+
+INSERT INTO "public"."species_synonyms" ("species_id", "synonym")
+SELECT s.id, v.synonym
+FROM "public"."species" s
+JOIN (VALUES
+    ('Capsicum annuum', 'Capsicum annuum var. glabriusculum'),
+    ('Capsicum annuum', 'Capsicum annuum var. aviculare'),
+    ('Capsicum chinense', 'Capsicum sinense'),
+    ('Capsicum baccatum', 'Capsicum baccatum var. pendulum'),
+    ('Capsicum baccatum', 'Capsicum pendulum'),
+    ('Capsicum frutescens', 'Capsicum minimum'),
+    ('Capsicum frutescens', 'Capsicum fruitescens'),
+    ('Capsicum praetermissum', 'Capsicum baccatum var. praetermissum')
+) AS v(name, synonym) ON s.name = v.name;
@@ -0,0 +1,13 @@
+-- Seed and pollen parents for pedigree lineage
+BEGIN;
+
+ALTER TABLE "public"."plants"
+    ADD COLUMN IF NOT EXISTS "seed_parent_id" int4 REFERENCES "public"."plants"("id") ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS "pollen_parent_id" int4 REFERENCES "public"."plants"("id") ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS "seed_parent_external" varchar(100),
+    ADD COLUMN IF NOT EXISTS "pollen_parent_external" varchar(100);
+
+CREATE INDEX IF NOT EXISTS idx_plants_seed_parent_id ON public.plants USING btree (seed_parent_id);
+CREATE INDEX IF NOT EXISTS idx_plants_pollen_parent_id ON public.plants USING btree (pollen_parent_id);
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- Seed lot inventory and sowing from saved seed
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS seed_lots_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."seed_lots" (
+    "id" int4 NOT NULL DEFAULT nextval('seed_lots_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "pollen_parent_id" int4 REFERENCES "public"."plants"("id") ON DELETE SET NULL,
+    "pollen_parent_external" varchar(100),
+    "seed_count" int4 NOT NULL DEFAULT 0 CHECK (seed_count >= 0),
+    "harvest_date" date NOT NULL,
+    "storage_location" varchar(100),
+    "germination_notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+ALTER TABLE "public"."plants"
+    ADD COLUMN IF NOT EXISTS "seed_lot_id" int4 REFERENCES "public"."seed_lots"("id") ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_seed_lots_plant_id ON public.seed_lots USING btree (plant_id);
+
+COMMIT;
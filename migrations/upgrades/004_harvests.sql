@@ -0,0 +1,39 @@
+-- Repeated harvests, with the old harvested flag renamed to season finished
+BEGIN;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_schema = 'public' AND table_name = 'plants' AND column_name = 'is_harvested') THEN
+        ALTER TABLE "public"."plants" RENAME COLUMN "is_harvested" TO "season_finished";
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_schema = 'public' AND table_name = 'plants' AND column_name = 'harvested_at') THEN
+        ALTER TABLE "public"."plants" RENAME COLUMN "harvested_at" TO "season_finished_at";
+    END IF;
+END $$;
+
+CREATE SEQUENCE IF NOT EXISTS harvests_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."harvests" (
+    "id" int4 NOT NULL DEFAULT nextval('harvests_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "harvest_date" date NOT NULL,
+    "pod_count" int4 NOT NULL DEFAULT 0 CHECK (pod_count >= 0),
+    "total_weight_grams" numeric(10,2) CHECK (total_weight_grams >= 0),
+    "quality_grade" varchar(10) NOT NULL DEFAULT 'A' CHECK ((quality_grade)::text = ANY ((ARRAY['A'::character varying, 'B'::character varying, 'C'::character varying, 'Cull'::character varying])::text[])),
+    "image_path" varchar(255),
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+ALTER TABLE "public"."seed_lots"
+    ADD COLUMN IF NOT EXISTS "harvest_id" int4 REFERENCES "public"."harvests"("id") ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_harvests_plant_id ON public.harvests USING btree (plant_id);
+CREATE INDEX IF NOT EXISTS idx_harvests_harvest_date ON public.harvests USING btree (harvest_date);
+
+COMMIT;
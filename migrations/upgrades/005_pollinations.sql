@@ -0,0 +1,32 @@
+-- Hand pollinations, and the seed lot saved from each successful one
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS pollinations_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."pollinations" (
+    "id" int4 NOT NULL DEFAULT nextval('pollinations_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "donor_plant_id" int4 REFERENCES "public"."plants"("id") ON DELETE SET NULL,
+    "donor_external" varchar(100),
+    "pollination_date" date NOT NULL,
+    "flower_tag" varchar(50),
+    "emasculated" bool NOT NULL DEFAULT false,
+    "isolation_method" varchar(20) NOT NULL DEFAULT 'Bagged' CHECK ((isolation_method)::text = ANY ((ARRAY['Bagged'::character varying, 'Caged'::character varying, 'Clipped'::character varying, 'None'::character varying])::text[])),
+    "outcome" varchar(20) NOT NULL DEFAULT 'Pending' CHECK ((outcome)::text = ANY ((ARRAY['Pending'::character varying, 'Set'::character varying, 'Dropped'::character varying])::text[])),
+    "outcome_date" date,
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+ALTER TABLE "public"."seed_lots"
+    ADD COLUMN IF NOT EXISTS "pollination_id" int4 REFERENCES "public"."pollinations"("id") ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pollinations_plant_id ON public.pollinations USING btree (plant_id);
+CREATE INDEX IF NOT EXISTS idx_pollinations_donor_plant_id ON public.pollinations USING btree (donor_plant_id);
+-- Fails if a pollination already has two live seed lots; delete one first
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seed_lots_pollination_id ON public.seed_lots USING btree (pollination_id) WHERE (deleted_at IS NULL);
+
+COMMIT;
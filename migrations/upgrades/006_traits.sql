@@ -0,0 +1,149 @@
+-- IPGRI Capsicum trait descriptors and plant scores
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS trait_definitions_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."trait_definitions" (
+    "id" int4 NOT NULL DEFAULT nextval('trait_definitions_id_seq'::regclass),
+    "code" varchar(50) NOT NULL UNIQUE,
+    "name" varchar(100) NOT NULL,
+    "trait_group" varchar(20) NOT NULL DEFAULT 'Plant' CHECK ((trait_group)::text = ANY ((ARRAY['Seedling'::character varying, 'Plant'::character varying, 'Inflorescence'::character varying, 'Fruit'::character varying, 'Seed'::character varying])::text[])),
+    "scale_type" varchar(20) NOT NULL CHECK ((scale_type)::text = ANY ((ARRAY['Categorical'::character varying, 'Numeric'::character varying])::text[])),
+    "unit" varchar(20),
+    "min_value" numeric(10,2),
+    "max_value" numeric(10,2),
+    "description" text,
+    "sort_order" int4 NOT NULL DEFAULT 0,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS trait_states_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."trait_states" (
+    "id" int4 NOT NULL DEFAULT nextval('trait_states_id_seq'::regclass),
+    "trait_id" int4 NOT NULL REFERENCES "public"."trait_definitions"("id") ON DELETE CASCADE,
+    "value" int4 NOT NULL,
+    "label" varchar(100) NOT NULL,
+    PRIMARY KEY ("id"),
+    UNIQUE ("trait_id", "value")
+);
+
+CREATE SEQUENCE IF NOT EXISTS trait_scores_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."trait_scores" (
+    "id" int4 NOT NULL DEFAULT nextval('trait_scores_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "trait_id" int4 NOT NULL REFERENCES "public"."trait_definitions"("id") ON DELETE CASCADE,
+    "state_value" int4,
+    "numeric_value" numeric(10,2),
+    "observed_date" date NOT NULL DEFAULT CURRENT_DATE,
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id"),
+    CHECK ((state_value IS NULL) <> (numeric_value IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_trait_scores_plant_id ON public.trait_scores USING btree (plant_id);
+CREATE INDEX IF NOT EXISTS idx_trait_scores_trait_id ON public.trait_scores USING btree (trait_id);
+
+-- Trait definitions from the IPGRI Descriptors for Capsicum (1995)
+INSERT INTO "public"."trait_definitions" ("code", "name", "trait_group", "scale_type", "unit", "min_value", "max_value", "description", "sort_order") VALUES
+('hypocotyl_color', 'Hypocotyl colour', 'Seedling', 'Categorical', NULL, NULL, NULL, 'Recorded on seedlings when the cotyledons are fully expanded', 10),
+('stem_color', 'Stem colour', 'Plant', 'Categorical', NULL, NULL, NULL, 'Recorded on young plants before transplanting', 20),
+('plant_growth_habit', 'Plant growth habit', 'Plant', 'Categorical', NULL, NULL, NULL, 'Recorded when the first fruits ripen', 30),
+('plant_height', 'Plant height', 'Plant', 'Numeric', 'cm', 0, 300, 'Recorded when the first fruits ripen', 40),
+('leaf_pubescence', 'Leaf pubescence', 'Plant', 'Categorical', NULL, NULL, NULL, 'Recorded on young leaves of mature plants', 50),
+('days_to_flowering', 'Days to flowering', 'Inflorescence', 'Numeric', 'days', 0, 365, 'Days from sowing until the first open flower', 60),
+('flowers_per_axil', 'Number of flowers per axil', 'Inflorescence', 'Categorical', NULL, NULL, NULL, NULL, 70),
+('corolla_color', 'Corolla colour', 'Inflorescence', 'Categorical', NULL, NULL, NULL, 'Recorded on fully opened flowers', 80),
+('anther_color', 'Anther colour', 'Inflorescence', 'Categorical', NULL, NULL, NULL, 'Recorded immediately after the flower opens, before anthesis', 90),
+('calyx_margin', 'Calyx margin', 'Inflorescence', 'Categorical', NULL, NULL, NULL, NULL, 100),
+('days_to_fruiting', 'Days to fruiting', 'Fruit', 'Numeric', 'days', 0, 365, 'Days from sowing until the first mature fruit', 110),
+('fruit_position', 'Fruit position', 'Fruit', 'Categorical', NULL, NULL, NULL, NULL, 120),
+('fruit_color_mature', 'Fruit colour at mature stage', 'Fruit', 'Categorical', NULL, NULL, NULL, 'Recorded on fully ripe fruit', 130),
+('fruit_shape', 'Fruit shape', 'Fruit', 'Categorical', NULL, NULL, NULL, NULL, 140),
+('fruit_length', 'Fruit length', 'Fruit', 'Numeric', 'cm', 0, 50, 'Average of ten ripe fruits', 150),
+('fruit_width', 'Fruit width', 'Fruit', 'Numeric', 'cm', 0, 20, 'Measured at the widest point, average of ten ripe fruits', 160),
+('fruit_surface', 'Fruit surface', 'Fruit', 'Categorical', NULL, NULL, NULL, NULL, 170),
+('seed_color', 'Seed colour', 'Seed', 'Categorical', NULL, NULL, NULL, NULL, 180)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "public"."trait_states" ("trait_id", "value", "label")
+SELECT t.id, v.value, v.label
+FROM "public"."trait_definitions" t
+JOIN (VALUES
+    ('hypocotyl_color', 1, 'White'),
+    ('hypocotyl_color', 2, 'Green'),
+    ('hypocotyl_color', 3, 'Purple'),
+    ('stem_color', 1, 'Green'),
+    ('stem_color', 2, 'Green with purple stripes'),
+    ('stem_color', 3, 'Purple'),
+    ('stem_color', 4, 'Other'),
+    ('plant_growth_habit', 3, 'Prostrate'),
+    ('plant_growth_habit', 5, 'Intermediate (compact)'),
+    ('plant_growth_habit', 7, 'Erect'),
+    ('plant_growth_habit', 9, 'Other'),
+    ('leaf_pubescence', 3, 'Sparse'),
+    ('leaf_pubescence', 5, 'Intermediate'),
+    ('leaf_pubescence', 7, 'Dense'),
+    ('flowers_per_axil', 1, 'One'),
+    ('flowers_per_axil', 2, 'Two'),
+    ('flowers_per_axil', 3, 'Three or more'),
+    ('flowers_per_axil', 4, 'Many in bunches, each in its own axil'),
+    ('corolla_color', 1, 'White'),
+    ('corolla_color', 2, 'Light yellow'),
+    ('corolla_color', 3, 'Yellow'),
+    ('corolla_color', 4, 'Yellow-green'),
+    ('corolla_color', 5, 'Purple with white base'),
+    ('corolla_color', 6, 'White with purple base'),
+    ('corolla_color', 7, 'White with purple margin'),
+    ('corolla_color', 8, 'Purple'),
+    ('corolla_color', 9, 'Other'),
+    ('anther_color', 1, 'White'),
+    ('anther_color', 2, 'Yellow'),
+    ('anther_color', 3, 'Pale blue'),
+    ('anther_color', 4, 'Blue'),
+    ('anther_color', 5, 'Purple'),
+    ('anther_color', 6, 'Other'),
+    ('calyx_margin', 1, 'Entire'),
+    ('calyx_margin', 2, 'Intermediate'),
+    ('calyx_margin', 3, 'Dentate'),
+    ('calyx_margin', 4, 'Other'),
+    ('fruit_position', 3, 'Pendant'),
+    ('fruit_position', 5, 'Intermediate'),
+    ('fruit_position', 7, 'Erect'),
+    ('fruit_color_mature', 1, 'White'),
+    ('fruit_color_mature', 2, 'Lemon-yellow'),
+    ('fruit_color_mature', 3, 'Pale orange-yellow'),
+    ('fruit_color_mature', 4, 'Orange-yellow'),
+    ('fruit_color_mature', 5, 'Pale orange'),
+    ('fruit_color_mature', 6, 'Orange'),
+    ('fruit_color_mature', 7, 'Light red'),
+    ('fruit_color_mature', 8, 'Red'),
+    ('fruit_color_mature', 9, 'Dark red'),
+    ('fruit_color_mature', 10, 'Purple'),
+    ('fruit_color_mature', 11, 'Brown'),
+    ('fruit_color_mature', 12, 'Black'),
+    ('fruit_color_mature', 13, 'Other'),
+    ('fruit_shape', 1, 'Elongate'),
+    ('fruit_shape', 2, 'Almost round'),
+    ('fruit_shape', 3, 'Triangular'),
+    ('fruit_shape', 4, 'Campanulate'),
+    ('fruit_shape', 5, 'Blocky'),
+    ('fruit_shape', 6, 'Other'),
+    ('fruit_surface', 1, 'Smooth'),
+    ('fruit_surface', 2, 'Semiwrinkled'),
+    ('fruit_surface', 3, 'Wrinkled'),
+    ('seed_color', 1, 'Straw'),
+    ('seed_color', 2, 'Brown'),
+    ('seed_color', 3, 'Black'),
+    ('seed_color', 4, 'Other')
+) AS v(code, value, label) ON t.code = v.code
+ON CONFLICT DO NOTHING;
+
+COMMIT;
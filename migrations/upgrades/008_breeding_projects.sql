@@ -0,0 +1,40 @@
+-- Breeding projects with member plants, target traits and selection status
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS breeding_projects_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."breeding_projects" (
+    "id" int4 NOT NULL DEFAULT nextval('breeding_projects_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "goal" text,
+    "status" varchar(20) NOT NULL DEFAULT 'Active' CHECK ((status)::text = ANY ((ARRAY['Planning'::character varying, 'Active'::character varying, 'Paused'::character varying, 'Completed'::character varying, 'Abandoned'::character varying])::text[])),
+    "started_at" date NOT NULL DEFAULT CURRENT_DATE,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."project_plants" (
+    "project_id" int4 NOT NULL REFERENCES "public"."breeding_projects"("id") ON DELETE CASCADE,
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "added_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("project_id", "plant_id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."project_target_traits" (
+    "project_id" int4 NOT NULL REFERENCES "public"."breeding_projects"("id") ON DELETE CASCADE,
+    "trait_id" int4 NOT NULL REFERENCES "public"."trait_definitions"("id") ON DELETE CASCADE,
+    "state_value" int4,
+    "min_value" numeric(10,2),
+    "max_value" numeric(10,2),
+    "notes" text,
+    PRIMARY KEY ("project_id", "trait_id")
+);
+
+ALTER TABLE "public"."plants"
+    ADD COLUMN IF NOT EXISTS "selection_status" varchar(20) NOT NULL DEFAULT 'Undecided' CHECK ((selection_status)::text = ANY ((ARRAY['Undecided'::character varying, 'Keep'::character varying, 'Cull'::character varying])::text[]));
+
+CREATE INDEX IF NOT EXISTS idx_project_plants_plant_id ON public.project_plants USING btree (plant_id);
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- Keep/cull decisions with the trait scores behind them
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS selection_decisions_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."selection_decisions" (
+    "id" int4 NOT NULL DEFAULT nextval('selection_decisions_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "status" varchar(20) NOT NULL CHECK ((status)::text = ANY ((ARRAY['Undecided'::character varying, 'Keep'::character varying, 'Cull'::character varying])::text[])),
+    "reason" text,
+    "decided_at" date NOT NULL DEFAULT CURRENT_DATE,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."selection_decision_scores" (
+    "decision_id" int4 NOT NULL REFERENCES "public"."selection_decisions"("id") ON DELETE CASCADE,
+    "trait_score_id" int4 NOT NULL REFERENCES "public"."trait_scores"("id") ON DELETE CASCADE,
+    PRIMARY KEY ("decision_id", "trait_score_id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_selection_decisions_plant_id ON public.selection_decisions USING btree (plant_id);
+
+COMMIT;
@@ -0,0 +1,37 @@
+-- Cross planner, and the capsaicin trait its loci are scored with
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS planned_crosses_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."planned_crosses" (
+    "id" int4 NOT NULL DEFAULT nextval('planned_crosses_id_seq'::regclass),
+    "seed_parent_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "pollen_parent_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "project_id" int4 REFERENCES "public"."breeding_projects"("id") ON DELETE SET NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'Planned' CHECK ((status)::text = ANY ((ARRAY['Planned'::character varying, 'Attempted'::character varying, 'Completed'::character varying, 'Abandoned'::character varying])::text[])),
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+ALTER TABLE "public"."pollinations"
+    ADD COLUMN IF NOT EXISTS "planned_cross_id" int4 REFERENCES "public"."planned_crosses"("id") ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pollinations_planned_cross_id ON public.pollinations USING btree (planned_cross_id);
+
+INSERT INTO "public"."trait_definitions" ("code", "name", "trait_group", "scale_type", "unit", "min_value", "max_value", "description", "sort_order") VALUES
+('placenta_capsaicin', 'Capsaicin in placenta', 'Fruit', 'Categorical', NULL, NULL, NULL, 'Whether the placenta of a ripe fruit is pungent', 175)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "public"."trait_states" ("trait_id", "value", "label")
+SELECT t.id, v.value, v.label
+FROM "public"."trait_definitions" t
+JOIN (VALUES
+    ('placenta_capsaicin', 0, 'Absent'),
+    ('placenta_capsaicin', 1, 'Present')
+) AS v(code, value, label) ON t.code = v.code
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- Genotype calls at known loci
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS plant_genotypes_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."plant_genotypes" (
+    "id" int4 NOT NULL DEFAULT nextval('plant_genotypes_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "locus" varchar(20) NOT NULL,
+    "genotype" varchar(10) NOT NULL CHECK ((genotype)::text = ANY ((ARRAY['AA'::character varying, 'Aa'::character varying, 'aa'::character varying, 'Unknown'::character varying])::text[])),
+    "source" varchar(20) NOT NULL CHECK ((source)::text = ANY ((ARRAY['Test Cross'::character varying, 'Progeny'::character varying, 'Marker'::character varying])::text[])),
+    "called_at" date NOT NULL DEFAULT CURRENT_DATE,
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_plant_genotypes_plant_id ON public.plant_genotypes USING btree (plant_id);
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- Heat tests with panel scores and lab SHU
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS heat_tests_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."heat_tests" (
+    "id" int4 NOT NULL DEFAULT nextval('heat_tests_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "harvest_id" int4 REFERENCES "public"."harvests"("id") ON DELETE SET NULL,
+    "tested_at" date NOT NULL DEFAULT CURRENT_DATE,
+    "panel_score" int4 NOT NULL CHECK ((panel_score >= 0) AND (panel_score <= 10)),
+    "shu" int4 CHECK (shu >= 0),
+    "capsaicinoids_mg_g" numeric(10,3) CHECK (capsaicinoids_mg_g >= 0),
+    "tester" varchar(100),
+    "ripeness" varchar(20) NOT NULL DEFAULT 'Ripe' CHECK ((ripeness)::text = ANY ((ARRAY['Green'::character varying, 'Breaker'::character varying, 'Ripe'::character varying, 'Overripe'::character varying])::text[])),
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_heat_tests_plant_id ON public.heat_tests USING btree (plant_id);
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- Variety catalog, with plants linked to it
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS varieties_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."varieties" (
+    "id" int4 NOT NULL DEFAULT nextval('varieties_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "species" varchar(100),
+    "origin" varchar(100),
+    "typical_shu" int4 CHECK (typical_shu >= 0),
+    "days_to_maturity" int4 CHECK (days_to_maturity > 0),
+    "pod_description" text,
+    "seed_source" varchar(255),
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+ALTER TABLE "public"."plants"
+    ADD COLUMN IF NOT EXISTS "variety_id" int4 REFERENCES "public"."varieties"("id") ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_plants_variety_id ON public.plants USING btree (variety_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_varieties_name ON public.varieties USING btree (lower((name)::text)) WHERE (deleted_at IS NULL);
+
+COMMIT;
@@ -0,0 +1,66 @@
+-- Species taxonomy with synonyms and hybrids
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS species_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."species" (
+    "id" int4 NOT NULL DEFAULT nextval('species_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "is_hybrid" bool NOT NULL DEFAULT false,
+    "parent_a_id" int4 REFERENCES "public"."species"("id") ON DELETE SET NULL,
+    "parent_b_id" int4 REFERENCES "public"."species"("id") ON DELETE SET NULL,
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id"),
+    CHECK (NOT is_hybrid OR (parent_a_id IS NOT NULL AND parent_b_id IS NOT NULL))
+);
+
+CREATE SEQUENCE IF NOT EXISTS species_synonyms_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."species_synonyms" (
+    "id" int4 NOT NULL DEFAULT nextval('species_synonyms_id_seq'::regclass),
+    "species_id" int4 NOT NULL REFERENCES "public"."species"("id") ON DELETE CASCADE,
+    "synonym" varchar(100) NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_species_name ON public.species USING btree (lower((name)::text)) WHERE (deleted_at IS NULL);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_species_synonyms_synonym ON public.species_synonyms USING btree (lower((synonym)::text));
+CREATE INDEX IF NOT EXISTS idx_plants_species ON public.plants USING btree (species);
+
+-- Capsicum species, replacing the list that used to be hardcoded in the app
+INSERT INTO "public"."species" ("name") VALUES
+('Capsicum annuum'),
+('Capsicum chinense'),
+('Capsicum baccatum'),
+('Capsicum frutescens'),
+('Capsicum pubescens'),
+('Capsicum rhomboideum'),
+('Capsicum praetermissum'),
+('Capsicum cardenasii'),
+('Capsicum eximium'),
+('Capsicum galapagoense'),
+('Capsicum tovarii'),
+('Capsicum flexuosum'),
+('Capsicum exile')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "public"."species_synonyms" ("species_id", "synonym")
+SELECT s.id, v.synonym
+FROM "public"."species" s
+JOIN (VALUES
+    ('Capsicum annuum', 'Capsicum annuum var. glabriusculum'),
+    ('Capsicum annuum', 'Capsicum annuum var. aviculare'),
+    ('Capsicum chinense', 'Capsicum sinense'),
+    ('Capsicum baccatum', 'Capsicum baccatum var. pendulum'),
+    ('Capsicum baccatum', 'Capsicum pendulum'),
+    ('Capsicum frutescens', 'Capsicum minimum'),
+    ('Capsicum frutescens', 'Capsicum fruitescens'),
+    ('Capsicum praetermissum', 'Capsicum baccatum var. praetermissum')
+) AS v(name, synonym) ON s.name = v.name
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,40 @@
+-- Germination trials, and the plants promoted from them
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS germination_trials_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."germination_trials" (
+    "id" int4 NOT NULL DEFAULT nextval('germination_trials_id_seq'::regclass),
+    "seed_lot_id" int4 REFERENCES "public"."seed_lots"("id") ON DELETE SET NULL,
+    "variety_id" int4 REFERENCES "public"."varieties"("id") ON DELETE SET NULL,
+    "seeds_sown" int4 NOT NULL CHECK (seeds_sown > 0),
+    "method" varchar(20) NOT NULL CHECK ((method)::text = ANY ((ARRAY['Paper Towel'::character varying, 'Cell Tray'::character varying, 'Rockwool'::character varying, 'Direct Sow'::character varying])::text[])),
+    "temperature_c" numeric(4,1),
+    "sow_date" date NOT NULL DEFAULT CURRENT_DATE,
+    "notes" text,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id"),
+    CHECK (seed_lot_id IS NOT NULL OR variety_id IS NOT NULL)
+);
+
+CREATE SEQUENCE IF NOT EXISTS germination_counts_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."germination_counts" (
+    "id" int4 NOT NULL DEFAULT nextval('germination_counts_id_seq'::regclass),
+    "trial_id" int4 NOT NULL REFERENCES "public"."germination_trials"("id") ON DELETE CASCADE,
+    "counted_on" date NOT NULL,
+    "germinated" int4 NOT NULL CHECK (germinated >= 0),
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+ALTER TABLE "public"."plants"
+    ADD COLUMN IF NOT EXISTS "germination_trial_id" int4 REFERENCES "public"."germination_trials"("id") ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_germination_trials_seed_lot_id ON public.germination_trials USING btree (seed_lot_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_germination_counts_trial_day ON public.germination_counts USING btree (trial_id, counted_on);
+CREATE INDEX IF NOT EXISTS idx_plants_germination_trial_id ON public.plants USING btree (germination_trial_id);
+
+COMMIT;
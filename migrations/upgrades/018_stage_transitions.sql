@@ -0,0 +1,19 @@
+-- Growth stage transitions
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS plant_stage_transitions_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."plant_stage_transitions" (
+    "id" int4 NOT NULL DEFAULT nextval('plant_stage_transitions_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "from_stage" varchar(20),
+    "to_stage" varchar(20) NOT NULL CHECK ((to_stage)::text = ANY (ARRAY[('Seed'::character varying)::text, ('Seedling'::character varying)::text, ('Vegetative'::character varying)::text, ('Flowering'::character varying)::text, ('Fruiting'::character varying)::text])),
+    "transitioned_on" date NOT NULL DEFAULT CURRENT_DATE,
+    "source" varchar(20) NOT NULL CHECK ((source)::text = ANY ((ARRAY['Created'::character varying, 'Edit'::character varying, 'Advance'::character varying, 'Germination'::character varying])::text[])),
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_plant_stage_transitions_plant_id ON public.plant_stage_transitions USING btree (plant_id, transitioned_on);
+
+COMMIT;
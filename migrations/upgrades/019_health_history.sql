@@ -0,0 +1,32 @@
+-- Health history and decline alerts
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS plant_health_changes_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."plant_health_changes" (
+    "id" int4 NOT NULL DEFAULT nextval('plant_health_changes_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "from_health" varchar(20),
+    "to_health" varchar(20) NOT NULL CHECK ((to_health)::text = ANY ((ARRAY['Excellent'::character varying, 'Good'::character varying, 'Fair'::character varying, 'Poor'::character varying])::text[])),
+    "changed_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "journal_entry_id" int4 REFERENCES "public"."journal_entries"("id") ON DELETE SET NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS health_alerts_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."health_alerts" (
+    "id" int4 NOT NULL DEFAULT nextval('health_alerts_id_seq'::regclass),
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    "peak_health" varchar(20) NOT NULL,
+    "health" varchar(20) NOT NULL,
+    "window_days" int4 NOT NULL,
+    "detected_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "acknowledged_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_plant_health_changes_plant_id ON public.plant_health_changes USING btree (plant_id, changed_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_health_alerts_open ON public.health_alerts USING btree (plant_id) WHERE (acknowledged_at IS NULL);
+
+COMMIT;
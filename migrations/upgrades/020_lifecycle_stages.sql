@@ -0,0 +1,20 @@
+-- Overwintering, dormant, dead and given away stages, and why a plant left the garden
+BEGIN;
+
+-- The stage checks are the ones Postgres named when the columns were created
+ALTER TABLE "public"."plants"
+    DROP CONSTRAINT IF EXISTS "plants_growth_stage_check",
+    ADD CONSTRAINT "plants_growth_stage_check" CHECK ((growth_stage)::text = ANY (ARRAY[('Seed'::character varying)::text, ('Seedling'::character varying)::text, ('Vegetative'::character varying)::text, ('Flowering'::character varying)::text, ('Fruiting'::character varying)::text, ('Overwintering'::character varying)::text, ('Dormant'::character varying)::text, ('Dead'::character varying)::text, ('Given Away'::character varying)::text]));
+
+ALTER TABLE "public"."plant_stage_transitions"
+    DROP CONSTRAINT IF EXISTS "plant_stage_transitions_to_stage_check",
+    ADD CONSTRAINT "plant_stage_transitions_to_stage_check" CHECK ((to_stage)::text = ANY (ARRAY[('Seed'::character varying)::text, ('Seedling'::character varying)::text, ('Vegetative'::character varying)::text, ('Flowering'::character varying)::text, ('Fruiting'::character varying)::text, ('Overwintering'::character varying)::text, ('Dormant'::character varying)::text, ('Dead'::character varying)::text, ('Given Away'::character varying)::text]));
+
+ALTER TABLE "public"."plant_stage_transitions"
+    DROP CONSTRAINT IF EXISTS "plant_stage_transitions_source_check",
+    ADD CONSTRAINT "plant_stage_transitions_source_check" CHECK ((source)::text = ANY ((ARRAY['Created'::character varying, 'Edit'::character varying, 'Advance'::character varying, 'Germination'::character varying, 'Lifecycle'::character varying])::text[]));
+
+ALTER TABLE "public"."plant_stage_transitions"
+    ADD COLUMN IF NOT EXISTS "reason" text;
+
+COMMIT;
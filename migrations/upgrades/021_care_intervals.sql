@@ -0,0 +1,12 @@
+-- Watering and fertilizing intervals per plant, with variety defaults
+BEGIN;
+
+ALTER TABLE "public"."plants"
+    ADD COLUMN IF NOT EXISTS "watering_interval_days" int4 CHECK (watering_interval_days >= 0),
+    ADD COLUMN IF NOT EXISTS "fertilizing_interval_days" int4 CHECK (fertilizing_interval_days >= 0);
+
+ALTER TABLE "public"."varieties"
+    ADD COLUMN IF NOT EXISTS "watering_interval_days" int4 CHECK (watering_interval_days >= 0),
+    ADD COLUMN IF NOT EXISTS "fertilizing_interval_days" int4 CHECK (fertilizing_interval_days >= 0);
+
+COMMIT;
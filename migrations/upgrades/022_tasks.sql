@@ -0,0 +1,45 @@
+-- Recurring plant tasks
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS tasks_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."tasks" (
+    "id" int4 NOT NULL DEFAULT nextval('tasks_id_seq'::regclass),
+    "title" varchar(200) NOT NULL,
+    "description" text,
+    "entry_type" varchar(50) NOT NULL DEFAULT 'General'::character varying,
+    "due_date" date NOT NULL,
+    "rrule" varchar(255),
+    "project_id" int4 REFERENCES "public"."breeding_projects"("id") ON DELETE SET NULL,
+    "occurrences" int4 NOT NULL DEFAULT 0,
+    "completed_at" timestamptz,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."task_plants" (
+    "task_id" int4 NOT NULL REFERENCES "public"."tasks"("id") ON DELETE CASCADE,
+    "plant_id" int4 NOT NULL REFERENCES "public"."plants"("id") ON DELETE CASCADE,
+    PRIMARY KEY ("task_id", "plant_id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS task_completions_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."task_completions" (
+    "id" int4 NOT NULL DEFAULT nextval('task_completions_id_seq'::regclass),
+    "task_id" int4 NOT NULL REFERENCES "public"."tasks"("id") ON DELETE CASCADE,
+    "due_date" date NOT NULL,
+    "completed_on" date NOT NULL,
+    "note" text,
+    "plant_count" int4 NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON public.tasks USING btree (due_date) WHERE (completed_at IS NULL AND deleted_at IS NULL);
+CREATE INDEX IF NOT EXISTS idx_task_plants_plant_id ON public.task_plants USING btree (plant_id);
+CREATE INDEX IF NOT EXISTS idx_task_completions_task_id ON public.task_completions USING btree (task_id);
+
+COMMIT;
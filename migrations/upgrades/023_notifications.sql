@@ -0,0 +1,58 @@
+-- Users, their notification channels and the delivery queue
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS users_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."users" (
+    "id" int4 NOT NULL DEFAULT nextval('users_id_seq'::regclass),
+    "name" varchar(100) NOT NULL,
+    "quiet_start" time,
+    "quiet_end" time,
+    "timezone" varchar(64) NOT NULL DEFAULT 'UTC'::character varying,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" timestamp,
+    PRIMARY KEY ("id"),
+    CHECK ((quiet_start IS NULL) = (quiet_end IS NULL))
+);
+
+CREATE SEQUENCE IF NOT EXISTS notification_channels_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."notification_channels" (
+    "id" int4 NOT NULL DEFAULT nextval('notification_channels_id_seq'::regclass),
+    "user_id" int4 NOT NULL REFERENCES "public"."users"("id") ON DELETE CASCADE,
+    "kind" varchar(20) NOT NULL CHECK ((kind)::text = ANY ((ARRAY['SMTP'::character varying, 'Ntfy'::character varying, 'Gotify'::character varying, 'Webhook'::character varying])::text[])),
+    "target" varchar(500) NOT NULL,
+    "token" varchar(255),
+    "events" text[] NOT NULL DEFAULT '{}'::text[],
+    "enabled" bool NOT NULL DEFAULT true,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS notification_deliveries_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."notification_deliveries" (
+    "id" int4 NOT NULL DEFAULT nextval('notification_deliveries_id_seq'::regclass),
+    "channel_id" int4 NOT NULL REFERENCES "public"."notification_channels"("id") ON DELETE CASCADE,
+    "event" varchar(30) NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "body" text NOT NULL,
+    "html" text,
+    "url" varchar(500),
+    "attempts" int4 NOT NULL DEFAULT 0,
+    "last_error" text,
+    "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "sent_at" timestamptz,
+    "claimed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+-- Queues created before deliveries were claimed
+ALTER TABLE "public"."notification_deliveries"
+    ADD COLUMN IF NOT EXISTS "claimed_at" timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_notification_channels_user_id ON public.notification_channels USING btree (user_id);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_pending ON public.notification_deliveries USING btree (created_at) WHERE (sent_at IS NULL);
+
+COMMIT;
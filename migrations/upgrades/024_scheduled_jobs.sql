@@ -0,0 +1,31 @@
+-- Background job schedules and run history
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "public"."scheduled_jobs" (
+    "name" varchar(50) NOT NULL,
+    "description" varchar(255) NOT NULL DEFAULT ''::character varying,
+    "schedule" varchar(100) NOT NULL,
+    "enabled" bool NOT NULL DEFAULT true,
+    "last_run_at" timestamptz,
+    "next_run_at" timestamptz,
+    "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("name")
+);
+
+CREATE SEQUENCE IF NOT EXISTS job_runs_id_seq;
+
+CREATE TABLE IF NOT EXISTS "public"."job_runs" (
+    "id" int4 NOT NULL DEFAULT nextval('job_runs_id_seq'::regclass),
+    "job_name" varchar(50) NOT NULL REFERENCES "public"."scheduled_jobs"("name") ON DELETE CASCADE,
+    "status" varchar(20) NOT NULL DEFAULT 'Running'::character varying CHECK ((status)::text = ANY ((ARRAY['Running'::character varying, 'Succeeded'::character varying, 'Failed'::character varying])::text[])),
+    "message" text,
+    "host" varchar(255) NOT NULL,
+    "started_at" timestamptz NOT NULL,
+    "finished_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name_started_at ON public.job_runs USING btree (job_name, started_at DESC);
+
+COMMIT;
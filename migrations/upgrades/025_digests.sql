@@ -0,0 +1,8 @@
+-- Daily and weekly garden digests
+BEGIN;
+
+ALTER TABLE "public"."users"
+    ADD COLUMN IF NOT EXISTS "digest_frequency" varchar(10) NOT NULL DEFAULT 'Off'::character varying CHECK ((digest_frequency)::text = ANY ((ARRAY['Off'::character varying, 'Daily'::character varying, 'Weekly'::character varying])::text[])),
+    ADD COLUMN IF NOT EXISTS "digest_sections" text[] NOT NULL DEFAULT '{}'::text[];
+
+COMMIT;
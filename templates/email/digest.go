@@ -0,0 +1,130 @@
+// Package email holds the templates for messages sent by email. They are
+// styled inline, since mail clients drop stylesheets.
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"pepper-analytics-ai/internal/notify"
+	"pepper-analytics-ai/internal/types"
+	"strings"
+)
+
+// Title is the digest's subject line.
+func Title(d types.Digest) string {
+	kind := "Garden digest"
+	if d.Frequency == types.DigestWeekly {
+		kind = "Weekly garden digest"
+	} else if d.Frequency == types.DigestDaily {
+		kind = "Daily garden digest"
+	}
+	return kind + " · " + d.AsOf.Format("Mon Jan 02")
+}
+
+// RenderDigest renders the digest as a message with an HTML body and a
+// plain text one.
+func RenderDigest(ctx context.Context, d types.Digest) (notify.Message, error) {
+	var buf bytes.Buffer
+	if err := Digest(d).Render(ctx, &buf); err != nil {
+		return notify.Message{}, fmt.Errorf("error rendering digest: %w", err)
+	}
+	return notify.Message{
+		Title: Title(d),
+		Body:  DigestText(d),
+		HTML:  buf.String(),
+	}, nil
+}
+
+// DigestText is the plain text version of the digest, for mail clients
+// without HTML and for push and webhook channels.
+func DigestText(d types.Digest) string {
+	var b strings.Builder
+	section := func(s types.DigestSection, count int) bool {
+		if !d.Has(s) {
+			return false
+		}
+		fmt.Fprintf(&b, "\n%s (%d)\n", s.Label(), count)
+		if count == 0 {
+			b.WriteString("  " + emptyText(s) + "\n")
+		}
+		return true
+	}
+
+	if section(types.DigestCare, len(d.Overdue)) {
+		for _, plant := range d.Overdue {
+			fmt.Fprintf(&b, "  %s: %s\n", plant.PlantName, careNeeds(plant))
+		}
+	}
+	if section(types.DigestHealth, len(d.HealthAlerts)) {
+		for _, alert := range d.HealthAlerts {
+			fmt.Fprintf(&b, "  %s: %s → %s since %s\n", alert.PlantName, alert.PeakHealth, alert.Health, alert.DetectedAt.Format("Jan 02"))
+		}
+	}
+	if section(types.DigestTasks, len(d.Tasks)) {
+		for _, task := range d.Tasks {
+			fmt.Fprintf(&b, "  %s  %s (%s)\n", task.DueDate.Format("Mon Jan 02"), task.Title, task.PlantNames.String)
+		}
+	}
+	if section(types.DigestJournal, len(d.JournalEntries)) {
+		for _, entry := range d.JournalEntries {
+			fmt.Fprintf(&b, "  %s  %s: %s\n", entry.EntryDate.Format("Jan 02"), entry.PlantName, entry.Title)
+		}
+	}
+	if section(types.DigestHarvests, len(d.Harvests)) {
+		for _, yield := range d.Harvests {
+			fmt.Fprintf(&b, "  %s: %s\n", yield.PlantName, yieldText(yield.YieldTotals))
+		}
+		fmt.Fprintf(&b, "  Total: %s\n", yieldText(d.HarvestTotals()))
+	}
+	return strings.TrimLeft(b.String(), "\n")
+}
+
+func emptyText(section types.DigestSection) string {
+	switch section {
+	case types.DigestCare:
+		return "Every plant is watered and fed."
+	case types.DigestHealth:
+		return "No health declines."
+	case types.DigestTasks:
+		return "Nothing due this week."
+	case types.DigestJournal:
+		return "No new journal entries."
+	default:
+		return "Nothing picked this week."
+	}
+}
+
+func careNeeds(plant types.CareSchedule) string {
+	var needs []string
+	if plant.WateringStatus == types.CareOverdue {
+		needs = append(needs, "water since "+plant.WateringDue.Time.Format("Jan 02"))
+	}
+	if plant.FertilizingStatus == types.CareOverdue {
+		needs = append(needs, "fertilizer since "+plant.FertilizingDue.Time.Format("Jan 02"))
+	}
+	return strings.Join(needs, ", ")
+}
+
+func yieldText(totals types.YieldTotals) string {
+	pickings := "pickings"
+	if totals.HarvestCount == 1 {
+		pickings = "picking"
+	}
+	text := fmt.Sprintf("%d %s, %d pods", totals.HarvestCount, pickings, totals.PodCount)
+	if totals.TotalWeightGrams > 0 {
+		text += fmt.Sprintf(", %.0f g", totals.TotalWeightGrams)
+	}
+	return text
+}
+
+func frequencyText(frequency types.DigestFrequency) string {
+	switch frequency {
+	case types.DigestDaily:
+		return "every morning"
+	case types.DigestWeekly:
+		return "every week"
+	default:
+		return "when you preview it"
+	}
+}
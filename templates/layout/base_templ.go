@@ -46,7 +46,7 @@ func Base(props BaseProps) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</title><link href=\"/css/bootstrap.min.css\" rel=\"stylesheet\"><link rel=\"icon\" type=\"image/svg+xml\" href=\"/img/favicon.svg\"><link href=\"https://cdn.jsdelivr.net/npm/bootstrap-icons@1.11.2/font/bootstrap-icons.min.css\" rel=\"stylesheet\"><script src=\"/js/htmx.min.js\"></script></head><body><div>")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</title><link href=\"/css/bootstrap.min.css\" rel=\"stylesheet\"><link rel=\"icon\" type=\"image/svg+xml\" href=\"/img/favicon.svg\"><link href=\"https://cdn.jsdelivr.net/npm/bootstrap-icons@1.11.2/font/bootstrap-icons.min.css\" rel=\"stylesheet\"><script src=\"/js/htmx.min.js\"></script></head><body><nav class=\"navbar navbar-expand navbar-light bg-light border-bottom\"><div class=\"container\"><a class=\"navbar-brand\" href=\"/\">Pepper Analytics</a><div class=\"navbar-nav\"><a class=\"nav-link\" href=\"/\">Plants</a> <a class=\"nav-link\" href=\"/tasks\">Tasks</a> <a class=\"nav-link\" href=\"/varieties\">Varieties</a> <a class=\"nav-link\" href=\"/species\">Species</a> <a class=\"nav-link\" href=\"/projects\">Projects</a> <a class=\"nav-link\" href=\"/pollinations\">Pollinations</a> <a class=\"nav-link\" href=\"/crosses\">Crosses</a> <a class=\"nav-link\" href=\"/seed-lots\">Seed Inventory</a> <a class=\"nav-link\" href=\"/germination\">Germination</a> <a class=\"nav-link\" href=\"/traits\">Traits</a> <a class=\"nav-link\" href=\"/analytics/segregation\">Segregation</a> <a class=\"nav-link\" href=\"/analytics/relatedness\">Relatedness</a> <a class=\"nav-link\" href=\"/analytics/heat\">Heat</a> <a class=\"nav-link\" href=\"/notifications\">Notifications</a> <a class=\"nav-link\" href=\"/admin/jobs\">Jobs</a></div></div></nav><div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}